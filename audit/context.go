@@ -0,0 +1,36 @@
+package audit
+
+import "context"
+
+// SystemActor is recorded for changes made outside an HTTP request, such as
+// scheduled jobs and background workers.
+const SystemActor = "system"
+
+type contextKey struct{}
+
+// Metadata identifies who made a change and in which request.
+type Metadata struct {
+	Actor     string
+	RequestID string
+}
+
+// WithMetadata returns a context carrying m.
+func WithMetadata(ctx context.Context, m Metadata) context.Context {
+	return context.WithValue(ctx, contextKey{}, m)
+}
+
+// WithActor returns a context whose actor is replaced, keeping the request ID.
+func WithActor(ctx context.Context, actor string) context.Context {
+	m := FromContext(ctx)
+	m.Actor = actor
+	return WithMetadata(ctx, m)
+}
+
+// FromContext returns the metadata of ctx, with SystemActor when none was set.
+func FromContext(ctx context.Context) Metadata {
+	m, _ := ctx.Value(contextKey{}).(Metadata)
+	if m.Actor == "" {
+		m.Actor = SystemActor
+	}
+	return m
+}
@@ -0,0 +1,76 @@
+// Command webhook-simulator posts signed sample payment webhooks to a running
+// instance, e.g.
+//
+//	go run ./cmd/webhook-simulator -reference fake-12-1 -status settlement
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"crud-clean-architecture/payment"
+
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	_ = godotenv.Load()
+
+	baseURL := flag.String("url", "http://localhost:8080", "base URL of the running API")
+	provider := flag.String("provider", "fake", "provider name used in the webhook path")
+	secret := flag.String("secret", os.Getenv("PAYMENT_WEBHOOK_SECRET"), "shared webhook secret")
+	reference := flag.String("reference", "", "provider reference of the payment (provider_ref)")
+	status := flag.String("status", "settlement", "gateway status: pending, settlement, capture, deny, expire, cancel")
+	amount := flag.Float64("amount", 0, "amount reported by the gateway (0 skips the amount check)")
+	eventID := flag.String("event-id", "", "event ID; reuse one to test deduplication")
+	repeat := flag.Int("repeat", 1, "number of times to deliver the same event")
+	tamper := flag.Bool("tamper", false, "send an invalid signature")
+	flag.Parse()
+
+	if *reference == "" {
+		log.Fatal("-reference is required")
+	}
+	if *eventID == "" {
+		*eventID = fmt.Sprintf("evt-%d", time.Now().UnixNano())
+	}
+
+	body, err := json.Marshal(payment.FakeWebhookPayload{
+		EventID:           *eventID,
+		Reference:         *reference,
+		TransactionStatus: *status,
+		Amount:            *amount,
+	})
+	if err != nil {
+		log.Fatalf("failed to encode payload: %v", err)
+	}
+
+	signature := payment.Sign(*secret, body)
+	if *tamper {
+		signature = payment.Sign(*secret+"-tampered", body)
+	}
+
+	url := *baseURL + "/payments/webhooks/" + *provider
+	for i := 0; i < *repeat; i++ {
+		req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			log.Fatalf("failed to build request: %v", err)
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set(payment.SignatureHeader, signature)
+
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			log.Fatalf("failed to deliver webhook: %v", err)
+		}
+		respBody, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		fmt.Printf("#%d %s -> %s\n%s\n", i+1, *eventID, resp.Status, respBody)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import (
+	"crud-clean-architecture/jobs"
+	"log"
+	"os"
+)
+
+// InitMailer returns an SMTP mailer when SMTP_HOST is set, otherwise a mailer that only logs
+func InitMailer() jobs.Mailer {
+	host := os.Getenv("SMTP_HOST")
+	if host == "" {
+		log.Println("SMTP_HOST not set, emails will only be logged")
+		return jobs.LogMailer{}
+	}
+
+	port := os.Getenv("SMTP_PORT")
+	if port == "" {
+		port = "587"
+	}
+
+	from := os.Getenv("MAIL_FROM")
+	if from == "" {
+		from = "no-reply@localhost"
+	}
+
+	return jobs.NewSMTPMailer(host, port, os.Getenv("SMTP_USERNAME"), os.Getenv("SMTP_PASSWORD"), from)
+}
@@ -0,0 +1,41 @@
+package config
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/payment"
+	"log"
+	"os"
+)
+
+// InitPaymentProviders registers a provider for every supported payment method
+// that can be served. QRIS, e-wallet and card need an external gateway and stay
+// unregistered until PAYMENT_GATEWAY names one; the fake gateway is only for
+// tests and local development.
+func InitPaymentProviders() *payment.Registry {
+	registry := payment.NewRegistry()
+
+	registry.Register(domain.PaymentMethodCash, payment.NewCashProvider())
+	registry.Register(domain.PaymentMethodBankTransfer, payment.NewBankTransferProvider(
+		os.Getenv("BANK_NAME"),
+		os.Getenv("BANK_ACCOUNT_NUMBER"),
+		os.Getenv("BANK_ACCOUNT_HOLDER"),
+	))
+
+	// QRIS, e-wallet dan kartu diproses gateway eksternal
+	switch gateway := os.Getenv("PAYMENT_GATEWAY"); gateway {
+	case "":
+		log.Println("PAYMENT_GATEWAY is not set, QRIS, e-wallet and card payments are disabled")
+		return registry
+	case "fake":
+		log.Println("PAYMENT_GATEWAY=fake: QRIS, e-wallet and card payments are simulated, do not use in production")
+	default:
+		log.Fatalf("unknown PAYMENT_GATEWAY %q, expected fake or empty", gateway)
+	}
+	fakeGateway := payment.NewFakeProvider("fake", domain.PaymentStatusPending)
+	fakeGateway.SetWebhookSecret(os.Getenv("PAYMENT_WEBHOOK_SECRET"))
+	registry.Register(domain.PaymentMethodQRIS, fakeGateway)
+	registry.Register(domain.PaymentMethodEWallet, fakeGateway)
+	registry.Register(domain.PaymentMethodCard, fakeGateway)
+
+	return registry
+}
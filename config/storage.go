@@ -0,0 +1,41 @@
+package config
+
+import (
+	"crud-clean-architecture/storage"
+	"log"
+	"os"
+)
+
+// InitStorage returns the file storage selected by STORAGE_DRIVER (local or s3)
+func InitStorage() storage.Storage {
+	switch driver := os.Getenv("STORAGE_DRIVER"); driver {
+	case "s3":
+		return storage.NewS3Storage(storage.S3Config{
+			Endpoint:  os.Getenv("S3_ENDPOINT"),
+			Bucket:    os.Getenv("S3_BUCKET"),
+			Region:    os.Getenv("S3_REGION"),
+			AccessKey: os.Getenv("S3_ACCESS_KEY"),
+			SecretKey: os.Getenv("S3_SECRET_KEY"),
+			PublicURL: os.Getenv("S3_PUBLIC_URL"),
+		})
+	case "", "local":
+		return newLocalStorage()
+	default:
+		log.Printf("Unknown STORAGE_DRIVER %q, falling back to local storage", driver)
+		return newLocalStorage()
+	}
+}
+
+// newLocalStorage menyimpan file di MEDIA_DIR yang disajikan server di /media;
+// MEDIA_BASE_URL dapat diisi URL absolut (mis. lewat CDN)
+func newLocalStorage() *storage.LocalStorage {
+	dir := os.Getenv("MEDIA_DIR")
+	if dir == "" {
+		dir = "uploads"
+	}
+	baseURL := os.Getenv("MEDIA_BASE_URL")
+	if baseURL == "" {
+		baseURL = "/media"
+	}
+	return storage.NewLocalStorage(dir, baseURL)
+}
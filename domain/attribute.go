@@ -0,0 +1,44 @@
+package domain
+
+import "regexp"
+
+type AttributeType string
+
+const (
+	AttributeTypeString  AttributeType = "string"
+	AttributeTypeNumber  AttributeType = "number"
+	AttributeTypeEnum    AttributeType = "enum"
+	AttributeTypeBoolean AttributeType = "boolean"
+)
+
+// AttributeDefinition is one field of a category attribute schema, e.g.
+// volume (number) for drinks or spiciness (enum) for food. Products of the
+// category store their values in Product.Attributes keyed by Name.
+type AttributeDefinition struct {
+	Name     string        `json:"name"`
+	Type     AttributeType `json:"type"`
+	Required bool          `json:"required"`
+	Options  []string      `json:"options,omitempty"`
+}
+
+// AttributeSchemaForm replaces the attribute schema of a category; an empty
+// list removes it.
+type AttributeSchemaForm struct {
+	Attributes []AttributeDefinitionForm `json:"attributes" binding:"max=50,dive"`
+}
+
+type AttributeDefinitionForm struct {
+	Name     string        `json:"name" binding:"required,max=64"`
+	Type     AttributeType `json:"type" binding:"required,oneof=string number enum boolean"`
+	Required bool          `json:"required"`
+	Options  []string      `json:"options" binding:"omitempty,max=50,dive,required,max=255"`
+}
+
+// Nama atribut dipakai sebagai key JSON dan parameter filter attr[name]
+var attributeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// ValidAttributeName reports whether name can be used as an attribute name:
+// lowercase letters, digits and underscores, starting with a letter.
+func ValidAttributeName(name string) bool {
+	return len(name) <= 64 && attributeNamePattern.MatchString(name)
+}
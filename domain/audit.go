@@ -0,0 +1,67 @@
+package domain
+
+import "time"
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+// Entity type audit; product dan category memakai nama aggregate outbox
+const (
+	AuditEntityOrder               = "order"
+	AuditEntityPayment             = "payment"
+	AuditEntityWebhookSubscription = "webhook_subscription"
+	AuditEntityProductPrice        = "product_price"
+	AuditEntityProductVariant      = "product_variant"
+	AuditEntityModifierGroup       = "modifier_group"
+	AuditEntityProductImage        = "product_image"
+	AuditEntityTag                 = "tag"
+)
+
+// AuditChange is the value of one field before and after the operation; nil
+// before means the entity was created, nil after that it was deleted.
+type AuditChange struct {
+	Before interface{} `json:"before"`
+	After  interface{} `json:"after"`
+}
+
+type AuditLog struct {
+	ID         uint                   `json:"id" gorm:"primaryKey"`
+	Actor      string                 `json:"actor" gorm:"size:191;index"`
+	RequestID  string                 `json:"request_id" gorm:"size:64;index"`
+	EntityType string                 `json:"entity_type" gorm:"size:64;index:idx_audit_entity"`
+	EntityID   uint                   `json:"entity_id" gorm:"index:idx_audit_entity"`
+	Action     AuditAction            `json:"action" gorm:"size:16"`
+	Changes    map[string]AuditChange `json:"changes" gorm:"serializer:json;type:longtext"`
+	CreatedAt  time.Time              `json:"created_at" gorm:"index"`
+}
+
+// AuditQueryForm holds the query string of GET /audit. Results are newest
+// first; pass the smallest ID seen as before_id to fetch the next page.
+type AuditQueryForm struct {
+	EntityType string      `form:"entity" binding:"omitempty,oneof=category product product_price product_variant modifier_group product_image tag order payment webhook_subscription"`
+	EntityID   uint        `form:"entity_id"`
+	Actor      string      `form:"actor" binding:"omitempty,max=191"`
+	Action     AuditAction `form:"action" binding:"omitempty,oneof=create update delete"`
+	RequestID  string      `form:"request_id" binding:"omitempty,max=64"`
+	From       string      `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To         string      `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	BeforeID   uint        `form:"before_id"`
+	Limit      int         `form:"limit" binding:"omitempty,gt=0,lte=200"`
+}
+
+type AuditFilter struct {
+	EntityType string
+	EntityID   uint
+	Actor      string
+	Action     AuditAction
+	RequestID  string
+	From       *time.Time
+	To         *time.Time
+	BeforeID   uint
+	Limit      int
+}
@@ -0,0 +1,117 @@
+package domain
+
+import (
+	"sync"
+	"time"
+)
+
+// AvailabilitySchedule limits when a product can be ordered. A product
+// without a schedule is always available; with one it is available while any
+// of its windows covers the time in Timezone.
+type AvailabilitySchedule struct {
+	Timezone string               `json:"timezone"`
+	Windows  []AvailabilityWindow `json:"windows"`
+}
+
+// AvailabilityWindow covers the time from StartTime to EndTime (HH:MM) on
+// Days (mon..sun) between StartDate and EndDate (YYYY-MM-DD, inclusive).
+// Empty fields do not restrict: no days means every day and no times means
+// the whole day. A window whose EndTime is not after StartTime runs past
+// midnight and belongs to the day it starts on.
+type AvailabilityWindow struct {
+	Days      []string `json:"days,omitempty"`
+	StartTime string   `json:"start_time,omitempty"`
+	EndTime   string   `json:"end_time,omitempty"`
+	StartDate string   `json:"start_date,omitempty"`
+	EndDate   string   `json:"end_date,omitempty"`
+}
+
+// AvailabilityForm replaces the availability schedule of a product.
+type AvailabilityForm struct {
+	Timezone string                   `json:"timezone" binding:"required,timezone"`
+	Windows  []AvailabilityWindowForm `json:"windows" binding:"required,min=1,max=20,dive"`
+}
+
+type AvailabilityWindowForm struct {
+	Days      []string `json:"days" binding:"omitempty,max=7,dive,oneof=mon tue wed thu fri sat sun"`
+	StartTime string   `json:"start_time" binding:"omitempty,datetime=15:04"`
+	EndTime   string   `json:"end_time" binding:"omitempty,datetime=15:04"`
+	StartDate string   `json:"start_date" binding:"omitempty,datetime=2006-01-02"`
+	EndDate   string   `json:"end_date" binding:"omitempty,datetime=2006-01-02"`
+}
+
+var weekdayNames = [...]string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// AvailableAt reports whether the schedule allows ordering at t. A nil
+// schedule is always available.
+func (s *AvailabilitySchedule) AvailableAt(t time.Time) bool {
+	if s == nil {
+		return true
+	}
+	local := t.In(loadLocation(s.Timezone))
+	for _, window := range s.Windows {
+		if window.covers(local) {
+			return true
+		}
+	}
+	return false
+}
+
+// locations menyimpan hasil time.LoadLocation yang membaca tzdata dari disk
+var locations sync.Map
+
+func loadLocation(name string) *time.Location {
+	if location, ok := locations.Load(name); ok {
+		return location.(*time.Location)
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		// Zona waktu sudah divalidasi saat disimpan; UTC hanya untuk jaga-jaga
+		location = time.UTC
+	}
+	locations.Store(name, location)
+	return location
+}
+
+func (w AvailabilityWindow) covers(local time.Time) bool {
+	if w.StartTime == "" && w.EndTime == "" {
+		return w.appliesOn(local)
+	}
+	minute := local.Hour()*60 + local.Minute()
+	start, end := clockMinute(w.StartTime, 0), clockMinute(w.EndTime, 24*60)
+	if start < end {
+		return minute >= start && minute < end && w.appliesOn(local)
+	}
+	// Melewati tengah malam: bagian setelah 00:00 milik hari sebelumnya
+	if minute >= start {
+		return w.appliesOn(local)
+	}
+	return minute < end && w.appliesOn(local.AddDate(0, 0, -1))
+}
+
+// appliesOn cek hari dan rentang tanggal untuk hari kalender day
+func (w AvailabilityWindow) appliesOn(day time.Time) bool {
+	date := day.Format("2006-01-02")
+	if (w.StartDate != "" && date < w.StartDate) || (w.EndDate != "" && date > w.EndDate) {
+		return false
+	}
+	if len(w.Days) == 0 {
+		return true
+	}
+	name := weekdayNames[day.Weekday()]
+	for _, d := range w.Days {
+		if d == name {
+			return true
+		}
+	}
+	return false
+}
+
+// clockMinute mengubah HH:MM menjadi menit sejak tengah malam, fallback jika kosong
+func clockMinute(clock string, fallback int) int {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return fallback
+	}
+	return t.Hour()*60 + t.Minute()
+}
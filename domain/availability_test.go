@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestAvailableAt(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Skip("tzdata not available")
+	}
+	at := func(loc *time.Location, value string) time.Time {
+		parsed, err := time.ParseInLocation("2006-01-02 15:04", value, loc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return parsed
+	}
+	// 2026-10-19 adalah hari Senin
+	officeHours := &AvailabilitySchedule{Timezone: "Asia/Jakarta", Windows: []AvailabilityWindow{
+		{Days: []string{"mon"}, StartTime: "09:00", EndTime: "17:00"},
+	}}
+	lateNight := &AvailabilitySchedule{Timezone: "Asia/Jakarta", Windows: []AvailabilityWindow{
+		{Days: []string{"fri"}, StartTime: "22:00", EndTime: "02:00"},
+	}}
+	dateRange := &AvailabilitySchedule{Timezone: "Asia/Jakarta", Windows: []AvailabilityWindow{
+		{StartDate: "2026-10-19", EndDate: "2026-10-20"},
+	}}
+	lastNight := &AvailabilitySchedule{Timezone: "Asia/Jakarta", Windows: []AvailabilityWindow{
+		{StartTime: "22:00", EndTime: "02:00", StartDate: "2026-10-19", EndDate: "2026-10-20"},
+	}}
+
+	tests := []struct {
+		name     string
+		schedule *AvailabilitySchedule
+		at       time.Time
+		want     bool
+	}{
+		{"nil schedule", nil, at(time.UTC, "2026-10-19 03:00"), true},
+		{"inside window", officeHours, at(jakarta, "2026-10-19 09:00"), true},
+		{"end is exclusive", officeHours, at(jakarta, "2026-10-19 17:00"), false},
+		{"other day", officeHours, at(jakarta, "2026-10-20 10:00"), false},
+		{"converted to schedule timezone", officeHours, at(time.UTC, "2026-10-19 02:00"), true},
+		{"outside window in schedule timezone", officeHours, at(time.UTC, "2026-10-19 10:30"), false},
+		{"day changes with timezone", officeHours, at(time.UTC, "2026-10-18 23:30"), false},
+		{"before midnight", lateNight, at(jakarta, "2026-10-23 23:00"), true},
+		{"after midnight belongs to previous day", lateNight, at(jakarta, "2026-10-24 01:59"), true},
+		{"window end after midnight", lateNight, at(jakarta, "2026-10-24 02:00"), false},
+		{"early hours of the start day", lateNight, at(jakarta, "2026-10-23 01:00"), false},
+		{"late hours of the next day", lateNight, at(jakarta, "2026-10-24 23:00"), false},
+		{"before start date", dateRange, at(jakarta, "2026-10-18 23:59"), false},
+		{"on start date", dateRange, at(jakarta, "2026-10-19 00:00"), true},
+		{"end date is inclusive", dateRange, at(jakarta, "2026-10-20 23:59"), true},
+		{"after end date", dateRange, at(jakarta, "2026-10-21 00:00"), false},
+		{"date bounds in schedule timezone", dateRange, at(time.UTC, "2026-10-18 17:00"), true},
+		{"night of the last date runs past it", lastNight, at(jakarta, "2026-10-21 01:00"), true},
+		{"night after the last date", lastNight, at(jakarta, "2026-10-21 22:00"), false},
+		{"morning of the first date", lastNight, at(jakarta, "2026-10-19 01:00"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.schedule.AvailableAt(tt.at); got != tt.want {
+				t.Fatalf("AvailableAt(%s) = %v, want %v", tt.at, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAvailableAtInvalidTimezone(t *testing.T) {
+	schedule := &AvailabilitySchedule{Timezone: "Nowhere/Invalid", Windows: []AvailabilityWindow{
+		{StartTime: "09:00", EndTime: "17:00"},
+	}}
+	if !schedule.AvailableAt(time.Date(2026, 10, 19, 10, 0, 0, 0, time.UTC)) {
+		t.Fatal("expected unknown timezone to fall back to UTC")
+	}
+}
@@ -0,0 +1,55 @@
+package domain
+
+type BatchOp string
+
+const (
+	BatchOpCreate BatchOp = "create"
+	BatchOpUpdate BatchOp = "update"
+	BatchOpDelete BatchOp = "delete"
+)
+
+type BatchStatus string
+
+const (
+	BatchStatusOK BatchStatus = "ok"
+	// BatchStatusFailed marks the operation that rejected the batch.
+	BatchStatusFailed BatchStatus = "failed"
+	// BatchStatusRolledBack marks valid operations that were not applied because another one failed.
+	BatchStatusRolledBack BatchStatus = "rolled_back"
+)
+
+// ProductBatchOperation is one item of POST /products/batch. ID is required for
+// update and delete, Data for create and update. A non-zero Version must match
+// the current version, like If-Match on the single-item endpoints.
+type ProductBatchOperation struct {
+	Op      BatchOp      `json:"op" binding:"required,oneof=create update delete"`
+	ID      uint         `json:"id"`
+	Version uint         `json:"version"`
+	Data    *ProductForm `json:"data"`
+}
+
+type ProductBatchForm struct {
+	Operations []ProductBatchOperation `json:"operations" binding:"required,min=1,max=500"`
+}
+
+// CategoryBatchOperation is one item of POST /categories/batch.
+type CategoryBatchOperation struct {
+	Op      BatchOp       `json:"op" binding:"required,oneof=create update delete"`
+	ID      uint          `json:"id"`
+	Version uint          `json:"version"`
+	Data    *CategoryForm `json:"data"`
+}
+
+type CategoryBatchForm struct {
+	Operations []CategoryBatchOperation `json:"operations" binding:"required,min=1,max=500"`
+}
+
+// BatchResult reports the outcome of one operation, in request order.
+type BatchResult struct {
+	Index  int               `json:"index"`
+	Op     BatchOp           `json:"op"`
+	ID     uint              `json:"id,omitempty"`
+	Status BatchStatus       `json:"status"`
+	Data   interface{}       `json:"data,omitempty"`
+	Errors map[string]string `json:"errors,omitempty"`
+}
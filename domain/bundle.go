@@ -0,0 +1,41 @@
+package domain
+
+type ProductType string
+
+const (
+	ProductTypeSimple ProductType = "simple"
+	ProductTypeBundle ProductType = "bundle"
+)
+
+// BundleComponent is a product included in a bundle (combo). Components
+// with variants name the variant whose stock is used.
+type BundleComponent struct {
+	ID          uint  `json:"id" gorm:"primaryKey"`
+	BundleID    uint  `json:"bundle_id" gorm:"index"`
+	ComponentID uint  `json:"product_id" gorm:"index"`
+	VariantID   *uint `json:"variant_id"`
+	Quantity    int   `json:"quantity"`
+}
+
+// BundleComponentsForm replaces the components of a bundle.
+type BundleComponentsForm struct {
+	Components []BundleComponentForm `json:"components" binding:"required,min=1,max=20,dive"`
+}
+
+type BundleComponentForm struct {
+	ProductID uint  `json:"product_id" binding:"required"`
+	VariantID *uint `json:"variant_id"`
+	Quantity  int   `json:"quantity" binding:"required,gt=0"`
+}
+
+// OrderDetailComponent records what a bundle order detail contained and the
+// part of its subtotal allocated to the component, so sales reports count
+// revenue per component product instead of per bundle.
+type OrderDetailComponent struct {
+	ID            uint    `json:"id" gorm:"primaryKey"`
+	OrderDetailID uint    `json:"order_detail_id" gorm:"index"`
+	ProductID     uint    `json:"product_id" gorm:"index"`
+	VariantID     *uint   `json:"variant_id"`
+	Quantity      int     `json:"quantity"`
+	Revenue       float64 `json:"revenue"`
+}
@@ -3,8 +3,33 @@ package domain
 type Category struct {
 	ID   uint   `json:"id" gorm:"primaryKey"`
 	Name string `json:"name" gorm:"unique;not null"`
+	// Slug dibuat dari nama dan dipakai sebagai alternatif ID di URL
+	Slug        string `json:"slug" gorm:"size:191;index"`
+	Description string `json:"description" gorm:"type:text"`
+	// AttributeSchema mendefinisikan atribut yang boleh/wajib diisi oleh produk di kategori ini
+	AttributeSchema []AttributeDefinition `json:"attribute_schema" gorm:"serializer:json;type:text"`
+	// Translations berisi nama dan deskripsi per locale selain DefaultLocale
+	Translations map[string]Translation `json:"translations" gorm:"serializer:json;type:text"`
+	// Version naik setiap update, dipakai sebagai ETag untuk optimistic locking
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
 
 type CategoryForm struct {
-	Name string `json:"name" binding:"required,max=255"`
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"omitempty,max=2000"`
+}
+
+// Localize replaces the name and description with their translations for
+// locale, keeping the default ones when there are none.
+func (c *Category) Localize(locale string) {
+	translation, ok := c.Translations[locale]
+	if !ok {
+		return
+	}
+	if translation.Name != "" {
+		c.Name = translation.Name
+	}
+	if translation.Description != "" {
+		c.Description = translation.Description
+	}
 }
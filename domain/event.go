@@ -0,0 +1,43 @@
+package domain
+
+import "time"
+
+type EventType string
+
+const (
+	EventOrderCreated    EventType = "order.created"
+	EventOrderPaid       EventType = "order.paid"
+	EventOrderCancelled  EventType = "order.cancelled"
+	EventOrderUpdated    EventType = "order.updated"
+	EventOrderDeleted    EventType = "order.deleted"
+	EventProductCreated  EventType = "product.created"
+	EventProductUpdated  EventType = "product.updated"
+	EventProductDeleted  EventType = "product.deleted"
+	EventCategoryCreated EventType = "category.created"
+	EventCategoryUpdated EventType = "category.updated"
+	EventCategoryDeleted EventType = "category.deleted"
+)
+
+const (
+	AggregateOrder    = "order"
+	AggregateProduct  = "product"
+	AggregateCategory = "category"
+)
+
+// OutboxMessage is a domain event stored in the same transaction as the
+// change that raised it. The relay publishes it afterwards and sets PublishedAt;
+// failed messages are retried at NextAttemptAt and get DeadAt once the relay
+// gives up on them.
+type OutboxMessage struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	AggregateType string     `json:"aggregate_type" gorm:"size:64"`
+	AggregateID   uint       `json:"aggregate_id"`
+	EventType     EventType  `json:"event_type" gorm:"size:64"`
+	Payload       string     `json:"payload" gorm:"type:longtext"`
+	Attempts      int        `json:"attempts"`
+	LastError     string     `json:"last_error" gorm:"type:text"`
+	NextAttemptAt *time.Time `json:"next_attempt_at" gorm:"index"`
+	PublishedAt   *time.Time `json:"published_at" gorm:"index"`
+	DeadAt        *time.Time `json:"dead_at" gorm:"index"`
+	CreatedAt     time.Time  `json:"created_at"`
+}
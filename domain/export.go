@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type ExportType string
+
+const (
+	ExportTypeOrders   ExportType = "orders"
+	ExportTypeProducts ExportType = "products"
+)
+
+type ExportStatus string
+
+const (
+	ExportStatusPending   ExportStatus = "pending"
+	ExportStatusCompleted ExportStatus = "completed"
+	ExportStatusFailed    ExportStatus = "failed"
+)
+
+// ExportForm selects the file format and whether the export runs in the background.
+type ExportForm struct {
+	Format string `form:"format" binding:"omitempty,oneof=csv xlsx"`
+	Async  bool   `form:"async"`
+}
+
+// Export is a background export. The filters are kept so the worker can
+// rebuild the same result set the request asked for.
+type Export struct {
+	ID            string        `json:"id"`
+	Type          ExportType    `json:"type"`
+	Format        string        `json:"format"`
+	Status        ExportStatus  `json:"status"`
+	OrderFilter   OrderFilter   `json:"order_filter"`
+	ProductFilter ProductFilter `json:"product_filter"`
+	FileName      string        `json:"file_name"`
+	Rows          int           `json:"rows"`
+	Error         string        `json:"error,omitempty"`
+	CreatedAt     time.Time     `json:"created_at"`
+	CompletedAt   *time.Time    `json:"completed_at,omitempty"`
+}
@@ -0,0 +1,53 @@
+package domain
+
+// PriceBucketBounds are the upper bounds of the price facet buckets; the
+// last bucket has no upper bound.
+var PriceBucketBounds = []float64{10000, 25000, 50000, 100000}
+
+// ProductFacets are the counts shown next to the product list filters. Each
+// facet is counted on the products matching the other filters, so choosing a
+// category or price range still shows the alternatives with their counts.
+// Tags are counted on the full filter because they narrow each other.
+type ProductFacets struct {
+	Categories  []CategoryFacet  `json:"categories"`
+	PriceRanges []PriceFacet     `json:"price_ranges"`
+	Tags        []TagFacet       `json:"tags"`
+	Attributes  []AttributeFacet `json:"attributes"`
+}
+
+type CategoryFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// PriceFacet counts products with Min <= price < Max; Max is nil for the
+// last bucket.
+type PriceFacet struct {
+	Min   float64  `json:"min"`
+	Max   *float64 `json:"max"`
+	Count int64    `json:"count"`
+}
+
+type TagFacet struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type AttributeFacet struct {
+	Name   string                `json:"name"`
+	Values []AttributeValueFacet `json:"values"`
+}
+
+// AttributeValueFacet counts an attribute value in the form used by the
+// attr[name]=value filter.
+type AttributeValueFacet struct {
+	Value string `json:"value"`
+	Count int64  `json:"count"`
+}
+
+// ProductListMeta is the meta member of the product list response.
+type ProductListMeta struct {
+	Facets *ProductFacets `json:"facets"`
+}
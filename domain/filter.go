@@ -0,0 +1,134 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OrderQueryForm holds the query string accepted by the order list and export
+// endpoints. Dates are calendar days (YYYY-MM-DD) in server time; to is inclusive.
+type OrderQueryForm struct {
+	From   string      `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To     string      `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	Status OrderStatus `form:"status" binding:"omitempty,oneof=pending paid cancelled"`
+}
+
+// OrderFilter restricts orders to [From, To) by order date and to Status.
+// Zero values mean no restriction.
+type OrderFilter struct {
+	From   *time.Time  `json:"from,omitempty"`
+	To     *time.Time  `json:"to,omitempty"`
+	Status OrderStatus `json:"status,omitempty"`
+}
+
+// Filter resolves the form into an OrderFilter.
+func (f *OrderQueryForm) Filter() (OrderFilter, error) {
+	var filter OrderFilter
+	if f.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", f.From, time.Local)
+		if err != nil {
+			return filter, err
+		}
+		filter.From = &from
+	}
+	if f.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", f.To, time.Local)
+		if err != nil {
+			return filter, err
+		}
+		to = to.AddDate(0, 0, 1) // to inklusif, jadi batas atas adalah awal hari berikutnya
+		filter.To = &to
+	}
+	filter.Status = f.Status
+	return filter, nil
+}
+
+func (f OrderFilter) IsEmpty() bool {
+	return f.From == nil && f.To == nil && f.Status == ""
+}
+
+// ProductQueryForm holds the query string accepted by the product list and
+// export endpoints. Attributes is read by the handler from attr[name]=value.
+// Tags is a comma separated list matched with TagMode (default all); prices
+// are restricted to [MinPrice, MaxPrice). AvailableAt is an RFC 3339 time or
+// "now".
+type ProductQueryForm struct {
+	CategoryID  uint              `form:"category_id"`
+	MinPrice    *float64          `form:"min_price" binding:"omitempty,gte=0"`
+	MaxPrice    *float64          `form:"max_price" binding:"omitempty,gt=0"`
+	Tags        string            `form:"tags"`
+	TagMode     TagMode           `form:"tag_mode" binding:"omitempty,oneof=all any"`
+	AvailableAt string            `form:"available_at"`
+	Attributes  map[string]string `form:"-"`
+}
+
+// ProductFilter restricts products to CategoryID, to prices in [MinPrice,
+// MaxPrice), to products having all (or any, depending on TagMode) of Tags and
+// to products whose attributes equal the given values and to products whose
+// availability schedule allows AvailableAt. Zero values mean no restriction.
+type ProductFilter struct {
+	CategoryID  uint              `json:"category_id,omitempty"`
+	MinPrice    *float64          `json:"min_price,omitempty"`
+	MaxPrice    *float64          `json:"max_price,omitempty"`
+	Tags        []string          `json:"tags,omitempty"`
+	TagMode     TagMode           `json:"tag_mode,omitempty"`
+	Attributes  map[string]string `json:"attributes,omitempty"`
+	AvailableAt *time.Time        `json:"available_at,omitempty"`
+	// UnavailableIDs are the products AvailableAt excludes, resolved once per
+	// request because schedules are evaluated in Go; nil means not resolved yet.
+	UnavailableIDs []uint `json:"-"`
+}
+
+// Filter resolves the form into a ProductFilter.
+func (f *ProductQueryForm) Filter() (ProductFilter, error) {
+	filter := ProductFilter{CategoryID: f.CategoryID, MinPrice: f.MinPrice, MaxPrice: f.MaxPrice, Attributes: f.Attributes}
+	for name := range f.Attributes {
+		if !ValidAttributeName(name) {
+			return ProductFilter{}, fmt.Errorf("invalid attribute name %q", name)
+		}
+	}
+
+	seen := map[string]bool{}
+	for _, tag := range strings.Split(f.Tags, ",") {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if !ValidTagName(tag) {
+			return ProductFilter{}, fmt.Errorf("invalid tag name %q", tag)
+		}
+		seen[tag] = true
+		filter.Tags = append(filter.Tags, tag)
+	}
+	// Urutan tag tidak mempengaruhi hasil, jadi diurutkan supaya filter yang sama menghasilkan key cache yang sama
+	sort.Strings(filter.Tags)
+	if len(filter.Tags) > 0 {
+		filter.TagMode = f.TagMode
+		if filter.TagMode == "" {
+			filter.TagMode = TagModeAll
+		}
+	}
+
+	if f.AvailableAt != "" {
+		at := time.Now()
+		if f.AvailableAt != "now" {
+			parsed, err := time.Parse(time.RFC3339, f.AvailableAt)
+			if err != nil {
+				return ProductFilter{}, errors.New("available_at must be an RFC 3339 time or now")
+			}
+			at = parsed
+		}
+		// Jadwal memakai menit, jadi dibulatkan supaya key cache facet tidak berganti tiap detik
+		at = at.UTC().Truncate(time.Minute)
+		filter.AvailableAt = &at
+	}
+	return filter, nil
+}
+
+func (f ProductFilter) IsEmpty() bool {
+	return f.CategoryID == 0 && f.MinPrice == nil && f.MaxPrice == nil && len(f.Tags) == 0 &&
+		len(f.Attributes) == 0 && f.AvailableAt == nil
+}
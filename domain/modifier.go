@@ -0,0 +1,49 @@
+package domain
+
+import "time"
+
+// ModifierGroup is a set of options a customer picks from when ordering a
+// product, e.g. spice level (exactly one) or extra toppings (zero to three).
+type ModifierGroup struct {
+	ID        uint             `json:"id" gorm:"primaryKey"`
+	ProductID uint             `json:"product_id" gorm:"index"`
+	Name      string           `json:"name"`
+	MinSelect int              `json:"min_select"`
+	MaxSelect int              `json:"max_select"`
+	Options   []ModifierOption `json:"options" gorm:"foreignKey:GroupID"`
+	CreatedAt time.Time        `json:"created_at"`
+	UpdatedAt time.Time        `json:"updated_at"`
+}
+
+// ModifierOption adds PriceDelta to the unit price when selected; "no
+// peanuts" style options simply have a zero delta.
+type ModifierOption struct {
+	ID         uint    `json:"id" gorm:"primaryKey"`
+	GroupID    uint    `json:"group_id" gorm:"index"`
+	Name       string  `json:"name"`
+	PriceDelta float64 `json:"price_delta"`
+}
+
+type ModifierGroupForm struct {
+	Name      string               `json:"name" binding:"required,max=255"`
+	MinSelect int                  `json:"min_select" binding:"gte=0"`
+	MaxSelect int                  `json:"max_select" binding:"required,gt=0,gtefield=MinSelect"`
+	Options   []ModifierOptionForm `json:"options" binding:"required,min=1,max=50,dive"`
+}
+
+type ModifierOptionForm struct {
+	Name       string  `json:"name" binding:"required,max=255"`
+	PriceDelta float64 `json:"price_delta" binding:"gte=0"`
+}
+
+// OrderDetailModifier is an option selected for an order detail. Only
+// OptionID is sent by the client; the names and price are copied from the
+// option when the order is created so later menu changes do not alter it.
+type OrderDetailModifier struct {
+	ID            uint    `json:"id" gorm:"primaryKey"`
+	OrderDetailID uint    `json:"order_detail_id" gorm:"index"`
+	OptionID      uint    `json:"option_id"`
+	GroupName     string  `json:"group_name"`
+	OptionName    string  `json:"option_name"`
+	PriceDelta    float64 `json:"price_delta"`
+}
@@ -1,6 +1,18 @@
 package domain
 
-import "time"
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusPending   OrderStatus = "pending"
+	OrderStatusPaid      OrderStatus = "paid"
+	OrderStatusCancelled OrderStatus = "cancelled"
+)
 
 type Order struct {
 	ID            uint   `json:"id" gorm:"primaryKey"`
@@ -8,18 +20,73 @@ type Order struct {
 
 	OrderDate  time.Time     `json:"order_date"`
 	TotalPrice float64       `json:"total_price"`
+	Status     OrderStatus   `json:"status" gorm:"size:32;default:pending"`
+	PaidAmount float64       `json:"paid_amount"`
+	PaidAt     *time.Time    `json:"paid_at"`
 	Details    []OrderDetail `json:"details" gorm:"foreignKey:OrderID"`
+	Payments   []Payment     `json:"payments" gorm:"foreignKey:OrderID"`
 	CreatedAt  time.Time     `json:"created_at"`
 	UpdatedAt  time.Time     `json:"updated_at"`
 }
 
 type OrderDetail struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	OrderID   uint      `json:"order_id"`
-	ProductID uint      `json:"product_id" binding:"required"`
-	Quantity  int       `json:"quantity" binding:"required,gt=0"`
-	Subtotal  float64   `json:"subtotal"`
-	Product   Product   `json:"product" gorm:"foreignKey:ProductID"`
-	CreatedAt time.Time `json:"created_at"`
-	UpdatedAt time.Time `json:"updated_at"`
+	ID         uint                   `json:"id" gorm:"primaryKey"`
+	OrderID    uint                   `json:"order_id"`
+	ProductID  uint                   `json:"product_id" binding:"required"`
+	VariantID  *uint                  `json:"variant_id"`
+	Quantity   int                    `json:"quantity" binding:"required,gt=0"`
+	Subtotal   float64                `json:"subtotal"`
+	Product    Product                `json:"product" gorm:"foreignKey:ProductID"`
+	Variant    *ProductVariant        `json:"variant,omitempty" gorm:"foreignKey:VariantID"`
+	Modifiers  []OrderDetailModifier  `json:"modifiers" gorm:"foreignKey:OrderDetailID"`
+	Components []OrderDetailComponent `json:"components,omitempty" gorm:"foreignKey:OrderDetailID"`
+	CreatedAt  time.Time              `json:"created_at"`
+	UpdatedAt  time.Time              `json:"updated_at"`
+}
+
+// GenerateInvoiceNumber builds the invoice code from the order ID and date.
+func (o *Order) GenerateInvoiceNumber() string {
+	datePart := o.OrderDate.Format("20060102") // Format tanggal menjadi YYYYMMDD
+	return fmt.Sprintf("INV-%s-%d", datePart, o.ID)
+}
+
+// RoundMoney rounds an amount to two decimal places so float sums can be compared safely.
+func RoundMoney(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// OutstandingAmount returns the part of the total not yet covered by pending or paid payments.
+func (o *Order) OutstandingAmount(payments []Payment) float64 {
+	var committed float64
+	for _, payment := range payments {
+		if payment.IsCommitted() {
+			committed += payment.Amount
+		}
+	}
+	return RoundMoney(o.TotalPrice - committed)
+}
+
+// ApplyPayments recalculates PaidAmount from the settled payments and marks
+// the order paid once they cover the total.
+func (o *Order) ApplyPayments(payments []Payment, now time.Time) {
+	var paid float64
+	for _, payment := range payments {
+		if payment.Status == PaymentStatusPaid {
+			paid += payment.Amount
+		}
+	}
+	o.PaidAmount = RoundMoney(paid)
+
+	if o.Status == OrderStatusCancelled {
+		return
+	}
+	if o.PaidAmount >= RoundMoney(o.TotalPrice) {
+		o.Status = OrderStatusPaid
+		if o.PaidAt == nil {
+			o.PaidAt = &now
+		}
+		return
+	}
+	o.Status = OrderStatusPending
+	o.PaidAt = nil
 }
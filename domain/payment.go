@@ -0,0 +1,79 @@
+package domain
+
+import "time"
+
+type PaymentMethod string
+
+const (
+	PaymentMethodCash         PaymentMethod = "cash"
+	PaymentMethodBankTransfer PaymentMethod = "bank_transfer"
+	PaymentMethodQRIS         PaymentMethod = "qris"
+	PaymentMethodEWallet      PaymentMethod = "ewallet"
+	PaymentMethodCard         PaymentMethod = "card"
+)
+
+type PaymentStatus string
+
+const (
+	PaymentStatusPending   PaymentStatus = "pending"
+	PaymentStatusPaid      PaymentStatus = "paid"
+	PaymentStatusFailed    PaymentStatus = "failed"
+	PaymentStatusExpired   PaymentStatus = "expired"
+	PaymentStatusCancelled PaymentStatus = "cancelled"
+)
+
+type Payment struct {
+	ID           uint          `json:"id" gorm:"primaryKey"`
+	OrderID      uint          `json:"order_id" gorm:"index"`
+	Method       PaymentMethod `json:"method" gorm:"size:32"`
+	Provider     string        `json:"provider" gorm:"size:64;index:idx_payments_provider_ref"`
+	ProviderRef  string        `json:"provider_ref" gorm:"size:191;index:idx_payments_provider_ref"`
+	Amount       float64       `json:"amount"`
+	Tendered     float64       `json:"tendered"`
+	Change       float64       `json:"change"`
+	Status       PaymentStatus `json:"status" gorm:"size:32;default:pending"`
+	Instructions string        `json:"instructions,omitempty"`
+	PaymentURL   string        `json:"payment_url,omitempty"`
+	ExpiresAt    *time.Time    `json:"expires_at,omitempty"`
+	PaidAt       *time.Time    `json:"paid_at,omitempty"`
+	CreatedAt    time.Time     `json:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type PaymentForm struct {
+	Method   PaymentMethod `json:"method" binding:"required,oneof=cash bank_transfer qris ewallet card"`
+	Amount   float64       `json:"amount" binding:"gte=0"`
+	Tendered float64       `json:"tendered" binding:"gte=0"`
+}
+
+type PaymentStatusForm struct {
+	Status PaymentStatus `json:"status" binding:"required,oneof=paid failed expired cancelled"`
+}
+
+// CanTransitionTo reports whether a payment in status s may move to next.
+// Only pending payments can change; paid, failed, expired and cancelled are final.
+func (s PaymentStatus) CanTransitionTo(next PaymentStatus) bool {
+	if s == next {
+		return false
+	}
+	return s == PaymentStatusPending
+}
+
+// IsCommitted reports whether the payment still counts towards the order balance.
+func (p Payment) IsCommitted() bool {
+	return p.Status == PaymentStatusPending || p.Status == PaymentStatusPaid
+}
+
+// PaymentEvent records every processed provider callback so retried
+// deliveries of the same event are applied only once.
+type PaymentEvent struct {
+	ID          uint          `json:"id" gorm:"primaryKey"`
+	Provider    string        `json:"provider" gorm:"size:64;uniqueIndex:idx_payment_events_provider_event"`
+	EventID     string        `json:"event_id" gorm:"size:191;uniqueIndex:idx_payment_events_provider_event"`
+	PaymentID   uint          `json:"payment_id" gorm:"index"`
+	RawStatus   string        `json:"raw_status" gorm:"size:64"`
+	Status      PaymentStatus `json:"status" gorm:"size:32"`
+	Applied     bool          `json:"applied"`
+	Payload     string        `json:"payload" gorm:"type:text"`
+	ProcessedAt time.Time     `json:"processed_at"`
+}
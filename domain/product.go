@@ -1,15 +1,49 @@
 package domain
 
 type Product struct {
-	ID         uint     `json:"id" gorm:"primaryKey"`
-	Name       string   `json:"name"`
-	Price      float64  `json:"price"`
-	CategoryID uint     `json:"category_id"`
-	Category   Category `json:"category" gorm:"foreignKey:CategoryID"`
+	ID          uint        `json:"id" gorm:"primaryKey"`
+	Name        string      `json:"name"`
+	Slug        string      `json:"slug" gorm:"size:191;index"`
+	Description string      `json:"description" gorm:"type:text"`
+	Price       float64     `json:"price"`
+	Type        ProductType `json:"type" gorm:"size:16;not null;default:simple"`
+	CategoryID  uint        `json:"category_id"`
+	Category    Category    `json:"category" gorm:"foreignKey:CategoryID"`
+	// Variants kosong berarti produk dijual dengan harga produk itu sendiri
+	Variants       []ProductVariant  `json:"variants" gorm:"foreignKey:ProductID"`
+	ModifierGroups []ModifierGroup   `json:"modifier_groups" gorm:"foreignKey:ProductID"`
+	Components     []BundleComponent `json:"components,omitempty" gorm:"foreignKey:BundleID"`
+	Images         []ProductImage    `json:"images" gorm:"foreignKey:ProductID"`
+	Tags           []Tag             `json:"tags" gorm:"many2many:product_tags"`
+	// Attributes berisi nilai atribut sesuai skema kategori, divalidasi di service
+	Attributes map[string]interface{} `json:"attributes" gorm:"serializer:json;type:text"`
+	// Translations berisi nama dan deskripsi per locale selain DefaultLocale
+	Translations map[string]Translation `json:"translations" gorm:"serializer:json;type:text"`
+	// Availability kosong berarti produk selalu bisa dipesan
+	Availability *AvailabilitySchedule `json:"availability" gorm:"serializer:json;type:text"`
+	// Version naik setiap update, dipakai sebagai ETag untuk optimistic locking
+	Version uint `json:"version" gorm:"not null;default:1"`
 }
 
 type ProductForm struct {
-	Name       string  `json:"name" binding:"required,max=255"`
-	Price      float64 `json:"price" binding:"required,gt=0"`
-	CategoryID uint    `json:"category_id" binding:"required"`
+	Name        string  `json:"name" binding:"required,max=255"`
+	Price       float64 `json:"price" binding:"required,gt=0"`
+	CategoryID  uint    `json:"category_id" binding:"required"`
+	Description string  `json:"description" binding:"omitempty,max=2000"`
+	// Attributes divalidasi terhadap skema atribut kategori di service
+	Attributes map[string]interface{} `json:"attributes" binding:"omitempty,max=50"`
+}
+
+// Localize replaces the names and descriptions of the product and its category
+// with their translations for locale, keeping the default ones when there are none.
+func (p *Product) Localize(locale string) {
+	if translation, ok := p.Translations[locale]; ok {
+		if translation.Name != "" {
+			p.Name = translation.Name
+		}
+		if translation.Description != "" {
+			p.Description = translation.Description
+		}
+	}
+	p.Category.Localize(locale)
 }
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ProductImage is an uploaded product photo. Images are shown in Position
+// order; the storage keys are kept to delete the files with the image.
+type ProductImage struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	ProductID    uint      `json:"product_id" gorm:"index"`
+	Position     int       `json:"position"`
+	URL          string    `json:"url" gorm:"size:1024"`
+	ThumbnailURL string    `json:"thumbnail_url" gorm:"size:1024"`
+	StorageKey   string    `json:"-" gorm:"size:255"`
+	ThumbnailKey string    `json:"-" gorm:"size:255"`
+	ContentType  string    `json:"content_type" gorm:"size:64"`
+	Width        int       `json:"width"`
+	Height       int       `json:"height"`
+	Size         int64     `json:"size"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// ProductImageOrderForm lists every image ID of the product in the new order.
+type ProductImageOrderForm struct {
+	ImageIDs []uint `json:"image_ids" binding:"required,min=1"`
+}
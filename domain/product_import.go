@@ -0,0 +1,75 @@
+package domain
+
+type ProductImportMode string
+
+const (
+	// ProductImportCreate rejects rows whose product already exists in the category.
+	ProductImportCreate ProductImportMode = "create"
+	// ProductImportUpsert updates the price of existing products instead.
+	ProductImportUpsert ProductImportMode = "upsert"
+)
+
+type ProductImportCommit string
+
+const (
+	// ProductImportAtomic commits every row in one transaction, or nothing if any row is invalid.
+	ProductImportAtomic ProductImportCommit = "atomic"
+	// ProductImportBatch skips invalid rows and commits the rest in batches.
+	ProductImportBatch ProductImportCommit = "batch"
+)
+
+type ProductImportAction string
+
+const (
+	ProductImportActionCreate ProductImportAction = "create"
+	ProductImportActionUpdate ProductImportAction = "update"
+	ProductImportActionSkip   ProductImportAction = "skip"
+)
+
+// ProductImportForm holds the query string of POST /products/import.
+type ProductImportForm struct {
+	DryRun    bool                `form:"dry_run"`
+	Mode      ProductImportMode   `form:"mode" binding:"omitempty,oneof=create upsert"`
+	Commit    ProductImportCommit `form:"commit" binding:"omitempty,oneof=atomic batch"`
+	BatchSize int                 `form:"batch_size" binding:"omitempty,gt=0,lte=1000"`
+}
+
+// ProductImportRow is one CSV record as read from the file. Line is the
+// 1-based line number in the file, header included.
+type ProductImportRow struct {
+	Line     int
+	Name     string
+	Price    string
+	Category string
+}
+
+// ProductImportItem is a validated row ready to be written. Product.ID is set
+// when an existing product is updated; Product.CategoryID is zero when the
+// category has to be created first.
+type ProductImportItem struct {
+	Line         int
+	Product      Product
+	CategoryName string
+}
+
+type ProductImportResult struct {
+	Line      int                 `json:"line"`
+	Name      string              `json:"name"`
+	Category  string              `json:"category"`
+	Action    ProductImportAction `json:"action"`
+	ProductID uint                `json:"product_id,omitempty"`
+	Errors    map[string]string   `json:"errors,omitempty"`
+}
+
+type ProductImportReport struct {
+	DryRun  bool                  `json:"dry_run"`
+	Mode    ProductImportMode     `json:"mode"`
+	Commit  ProductImportCommit   `json:"commit"`
+	Total   int                   `json:"total"`
+	Valid   int                   `json:"valid"`
+	Invalid int                   `json:"invalid"`
+	Created int                   `json:"created"`
+	Updated int                   `json:"updated"`
+	Failed  int                   `json:"failed"`
+	Rows    []ProductImportResult `json:"rows"`
+}
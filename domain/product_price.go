@@ -0,0 +1,23 @@
+package domain
+
+import "time"
+
+// ProductPrice is one entry of a product's price history. A price applies from
+// EffectiveFrom until the next entry; future entries are scheduled changes
+// that the scheduler copies to Product.Price once they are due.
+type ProductPrice struct {
+	ID            uint      `json:"id" gorm:"primaryKey"`
+	ProductID     uint      `json:"product_id" gorm:"index:idx_product_price_effective"`
+	Price         float64   `json:"price"`
+	EffectiveFrom time.Time `json:"effective_from" gorm:"index:idx_product_price_effective"`
+	// Applied menandai harga yang sudah disalin ke kolom products.price
+	Applied   bool      `json:"applied" gorm:"not null;default:false;index"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ProductPriceForm schedules a price change; effective_from is RFC 3339 and
+// must be in the future.
+type ProductPriceForm struct {
+	Price         float64 `json:"price" binding:"required,gt=0"`
+	EffectiveFrom string  `json:"effective_from" binding:"required,datetime=2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// ProductVariant is a sellable size or portion of a product (e.g. Sate 10
+// tusuk vs 20 tusuk) with its own SKU, price and stock.
+type ProductVariant struct {
+	ID         uint              `json:"id" gorm:"primaryKey"`
+	ProductID  uint              `json:"product_id" gorm:"index"`
+	SKU        string            `json:"sku" gorm:"size:64;uniqueIndex"`
+	Name       string            `json:"name"`
+	Price      float64           `json:"price"`
+	Stock      int               `json:"stock"`
+	Attributes map[string]string `json:"attributes" gorm:"serializer:json;type:text"`
+	CreatedAt  time.Time         `json:"created_at"`
+	UpdatedAt  time.Time         `json:"updated_at"`
+}
+
+type ProductVariantForm struct {
+	SKU        string            `json:"sku" binding:"required,max=64"`
+	Name       string            `json:"name" binding:"required,max=255"`
+	Price      float64           `json:"price" binding:"required,gt=0"`
+	Stock      int               `json:"stock" binding:"gte=0"`
+	Attributes map[string]string `json:"attributes" binding:"omitempty,max=20,dive,keys,required,max=64,endkeys,max=255"`
+}
@@ -0,0 +1,109 @@
+package domain
+
+import "time"
+
+type ReportInterval string
+
+const (
+	ReportIntervalDay   ReportInterval = "day"
+	ReportIntervalWeek  ReportInterval = "week"
+	ReportIntervalMonth ReportInterval = "month"
+)
+
+// ReportQueryForm holds the query string accepted by the report endpoints.
+// Dates are calendar days (YYYY-MM-DD) in the requested timezone; to is inclusive.
+type ReportQueryForm struct {
+	From     string         `form:"from" binding:"omitempty,datetime=2006-01-02"`
+	To       string         `form:"to" binding:"omitempty,datetime=2006-01-02"`
+	TZ       string         `form:"tz" binding:"omitempty,timezone"`
+	Interval ReportInterval `form:"interval" binding:"omitempty,oneof=day week month"`
+	SortBy   string         `form:"sort" binding:"omitempty,oneof=quantity revenue"`
+	Limit    int            `form:"limit" binding:"omitempty,gt=0,lte=100"`
+}
+
+// ReportFilter is the resolved filter: [From, To) as absolute instants and
+// the location used to bucket periods.
+type ReportFilter struct {
+	From     time.Time
+	To       time.Time
+	Location *time.Location
+	Interval ReportInterval
+	SortBy   string
+	Limit    int
+}
+
+// RevenuePoint is one period of the revenue series. Revenue, here and in the
+// other reports and the daily sales summary, is the total price of the orders
+// that were not cancelled, whether or not they are paid yet.
+type RevenuePoint struct {
+	Period     string  `json:"period"`
+	OrderCount int64   `json:"order_count"`
+	Revenue    float64 `json:"revenue"`
+}
+
+type ProductSales struct {
+	ProductID uint    `json:"product_id"`
+	Name      string  `json:"name"`
+	Quantity  int64   `json:"quantity"`
+	Revenue   float64 `json:"revenue"`
+}
+
+type CategorySales struct {
+	CategoryID uint    `json:"category_id"`
+	Name       string  `json:"name"`
+	Quantity   int64   `json:"quantity"`
+	Revenue    float64 `json:"revenue"`
+}
+
+type OrderValueSummary struct {
+	OrderCount        int64   `json:"order_count"`
+	Revenue           float64 `json:"revenue"`
+	AverageOrderValue float64 `json:"average_order_value"`
+}
+
+// ReportPeriod returns the bucket label of t for the interval, in t's location:
+// 2006-01-02 for days, the Monday of the ISO week for weeks and 2006-01 for months.
+func ReportPeriod(t time.Time, interval ReportInterval) string {
+	switch interval {
+	case ReportIntervalWeek:
+		offset := (int(t.Weekday()) + 6) % 7
+		return t.AddDate(0, 0, -offset).Format("2006-01-02")
+	case ReportIntervalMonth:
+		return t.Format("2006-01")
+	default:
+		return t.Format("2006-01-02")
+	}
+}
+
+// ReportBucket is one period of a report series: [Start, End).
+type ReportBucket struct {
+	Period string
+	Start  time.Time
+	End    time.Time
+}
+
+// ReportBuckets splits [from, to) into consecutive periods of the interval in
+// from's location. The first and last bucket are cut at from and to, so a
+// weekly series starting on a Wednesday has a shorter first week.
+func ReportBuckets(from, to time.Time, interval ReportInterval) []ReportBucket {
+	var buckets []ReportBucket
+	for start := from; start.Before(to); {
+		// Batas periode dihitung dari tanggal kalender supaya aman terhadap DST
+		var end time.Time
+		switch interval {
+		case ReportIntervalWeek:
+			days := 7 - (int(start.Weekday())+6)%7
+			end = time.Date(start.Year(), start.Month(), start.Day()+days, 0, 0, 0, 0, start.Location())
+		case ReportIntervalMonth:
+			end = time.Date(start.Year(), start.Month()+1, 1, 0, 0, 0, 0, start.Location())
+		default:
+			end = time.Date(start.Year(), start.Month(), start.Day()+1, 0, 0, 0, 0, start.Location())
+		}
+		if end.After(to) {
+			end = to
+		}
+		buckets = append(buckets, ReportBucket{Period: ReportPeriod(start, interval), Start: start, End: end})
+		start = end
+	}
+	return buckets
+}
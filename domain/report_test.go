@@ -0,0 +1,111 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReportPeriod(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		interval ReportInterval
+		want     string
+	}{
+		{"day", time.Date(2026, 3, 4, 15, 0, 0, 0, time.UTC), ReportIntervalDay, "2026-03-04"},
+		{"day uses the location of t", time.Date(2026, 3, 4, 20, 0, 0, 0, time.UTC).In(jakarta), ReportIntervalDay, "2026-03-05"},
+		{"empty interval is day", time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC), "", "2026-03-04"},
+		{"week on monday", time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), ReportIntervalWeek, "2026-03-02"},
+		{"week on sunday", time.Date(2026, 3, 8, 23, 59, 0, 0, time.UTC), ReportIntervalWeek, "2026-03-02"},
+		{"week across months", time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC), ReportIntervalWeek, "2026-03-30"},
+		{"week across years", time.Date(2027, 1, 1, 12, 0, 0, 0, time.UTC), ReportIntervalWeek, "2026-12-28"},
+		{"month", time.Date(2026, 12, 31, 23, 0, 0, 0, time.UTC), ReportIntervalMonth, "2026-12"},
+		{"month uses the location of t", time.Date(2026, 12, 31, 18, 0, 0, 0, time.UTC).In(jakarta), ReportIntervalMonth, "2027-01"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ReportPeriod(tt.t, tt.interval); got != tt.want {
+				t.Fatalf("ReportPeriod(%s, %q) = %s, want %s", tt.t, tt.interval, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReportBuckets(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	date := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 0, 0, 0, 0, jakarta)
+	}
+
+	tests := []struct {
+		name     string
+		from, to time.Time
+		interval ReportInterval
+		want     []ReportBucket
+	}{
+		{"days", date(2026, 2, 27), date(2026, 3, 2), ReportIntervalDay, []ReportBucket{
+			{"2026-02-27", date(2026, 2, 27), date(2026, 2, 28)},
+			{"2026-02-28", date(2026, 2, 28), date(2026, 3, 1)},
+			{"2026-03-01", date(2026, 3, 1), date(2026, 3, 2)},
+		}},
+		{"weeks cut at the range", date(2026, 3, 4), date(2026, 3, 19), ReportIntervalWeek, []ReportBucket{
+			{"2026-03-02", date(2026, 3, 4), date(2026, 3, 9)},
+			{"2026-03-09", date(2026, 3, 9), date(2026, 3, 16)},
+			{"2026-03-16", date(2026, 3, 16), date(2026, 3, 19)},
+		}},
+		{"months", date(2026, 11, 15), date(2027, 2, 1), ReportIntervalMonth, []ReportBucket{
+			{"2026-11", date(2026, 11, 15), date(2026, 12, 1)},
+			{"2026-12", date(2026, 12, 1), date(2027, 1, 1)},
+			{"2027-01", date(2027, 1, 1), date(2027, 2, 1)},
+		}},
+		{"empty range", date(2026, 3, 4), date(2026, 3, 4), ReportIntervalDay, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ReportBuckets(tt.from, tt.to, tt.interval)
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %d buckets, got %+v", len(tt.want), got)
+			}
+			for i := range got {
+				if got[i].Period != tt.want[i].Period || !got[i].Start.Equal(tt.want[i].Start) || !got[i].End.Equal(tt.want[i].End) {
+					t.Fatalf("bucket %d = %+v, want %+v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReportBucketsAcrossDST(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 8 Maret 2026 hanya 23 jam di New York
+	from := time.Date(2026, 3, 7, 0, 0, 0, 0, newYork)
+	to := time.Date(2026, 3, 10, 0, 0, 0, 0, newYork)
+
+	buckets := ReportBuckets(from, to, ReportIntervalDay)
+	want := []string{"2026-03-07", "2026-03-08", "2026-03-09"}
+	if len(buckets) != len(want) {
+		t.Fatalf("expected %d buckets, got %+v", len(want), buckets)
+	}
+	for i, bucket := range buckets {
+		if bucket.Period != want[i] {
+			t.Fatalf("bucket %d period = %s, want %s", i, bucket.Period, want[i])
+		}
+		if bucket.Start.Hour() != 0 || bucket.End.Hour() != 0 {
+			t.Fatalf("bucket %d does not start and end at local midnight: %+v", i, bucket)
+		}
+	}
+	if hours := buckets[1].End.Sub(buckets[1].Start).Hours(); hours != 23 {
+		t.Fatalf("expected a 23 hour day, got %v", hours)
+	}
+}
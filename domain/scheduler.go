@@ -0,0 +1,53 @@
+package domain
+
+import "time"
+
+type JobRunStatus string
+
+const (
+	JobRunRunning   JobRunStatus = "running"
+	JobRunSucceeded JobRunStatus = "succeeded"
+	JobRunFailed    JobRunStatus = "failed"
+)
+
+type JobRunTrigger string
+
+const (
+	JobRunTriggerSchedule JobRunTrigger = "schedule"
+	JobRunTriggerManual   JobRunTrigger = "manual"
+)
+
+// ScheduledJobRun is one execution of a scheduled job.
+type ScheduledJobRun struct {
+	ID         uint          `json:"id" gorm:"primaryKey"`
+	JobName    string        `json:"job_name" gorm:"size:100;index:idx_scheduled_job_runs_job"`
+	Trigger    JobRunTrigger `json:"trigger" gorm:"size:16"`
+	Instance   string        `json:"instance" gorm:"size:255"`
+	Status     JobRunStatus  `json:"status" gorm:"size:16"`
+	Output     string        `json:"output" gorm:"type:text"`
+	Error      string        `json:"error" gorm:"type:text"`
+	StartedAt  time.Time     `json:"started_at" gorm:"index:idx_scheduled_job_runs_job"`
+	FinishedAt *time.Time    `json:"finished_at"`
+	DurationMs int64         `json:"duration_ms"`
+}
+
+// ScheduledJob describes a registered job for the admin API.
+type ScheduledJob struct {
+	Name        string           `json:"name"`
+	Schedule    string           `json:"schedule"`
+	Description string           `json:"description"`
+	NextRunAt   *time.Time       `json:"next_run_at"`
+	LastRun     *ScheduledJobRun `json:"last_run"`
+}
+
+// DailySalesSummary is written by the nightly sales summary job.
+type DailySalesSummary struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	Date       time.Time `json:"date" gorm:"type:date;uniqueIndex"`
+	OrderCount int64     `json:"order_count"`
+	PaidCount  int64     `json:"paid_count"`
+	Revenue    float64   `json:"revenue"`
+	ItemsSold  int64     `json:"items_sold"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
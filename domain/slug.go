@@ -0,0 +1,15 @@
+package domain
+
+import "time"
+
+// Slug maps a URL slug to a category or product. Every slug an entity ever
+// had is kept so links to a renamed entity keep working; Current marks the
+// one in use, which is also stored on the entity itself.
+type Slug struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	EntityType string    `json:"entity_type" gorm:"size:32;uniqueIndex:idx_slugs_entity_slug"`
+	Slug       string    `json:"slug" gorm:"size:191;uniqueIndex:idx_slugs_entity_slug"`
+	EntityID   uint      `json:"entity_id" gorm:"index"`
+	Current    bool      `json:"current"`
+	CreatedAt  time.Time `json:"created_at"`
+}
@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"regexp"
+	"time"
+)
+
+// Tag is a label such as halal, vegetarian or best-seller. Unlike the
+// category a product can have many tags.
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"size:64;uniqueIndex"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ProductTag is a row of the product_tags join table behind Product.Tags.
+type ProductTag struct {
+	ProductID uint `gorm:"primaryKey"`
+	TagID     uint `gorm:"primaryKey"`
+}
+
+// TagCount is a tag with the number of products it is assigned to.
+type TagCount struct {
+	Tag
+	ProductCount int64 `json:"product_count"`
+}
+
+type TagForm struct {
+	Name string `json:"name" binding:"required,max=64"`
+}
+
+// ProductTagsForm replaces the tags of a product; an empty list removes them.
+type ProductTagsForm struct {
+	TagIDs []uint `json:"tag_ids" binding:"max=20"`
+}
+
+type TagMode string
+
+const (
+	TagModeAll TagMode = "all"
+	TagModeAny TagMode = "any"
+)
+
+// Nama tag dipakai di query tags=a,b sehingga tidak boleh mengandung koma atau spasi
+var tagNamePattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// ValidTagName reports whether name is lowercase letters and digits,
+// optionally separated by single hyphens (e.g. best-seller).
+func ValidTagName(name string) bool {
+	return len(name) <= 64 && tagNamePattern.MatchString(name)
+}
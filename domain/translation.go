@@ -0,0 +1,29 @@
+package domain
+
+// DefaultLocale is the language of the Name columns; other locales are stored
+// as translations and fall back to it.
+const DefaultLocale = "id"
+
+// SupportedLocales lists the locales the catalog can be translated to and
+// requested in, the default first.
+var SupportedLocales = []string{DefaultLocale, "en"}
+
+func IsSupportedLocale(locale string) bool {
+	for _, supported := range SupportedLocales {
+		if supported == locale {
+			return true
+		}
+	}
+	return false
+}
+
+// Translation holds the localized fields of a category or product.
+type Translation struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+type TranslationForm struct {
+	Name        string `json:"name" binding:"required,max=255"`
+	Description string `json:"description" binding:"omitempty,max=2000"`
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "testing"
+
+func TestProductLocalize(t *testing.T) {
+	product := Product{
+		Name:        "Es Kopi Susu",
+		Description: "Kopi dengan susu dan gula aren",
+		Translations: map[string]Translation{
+			"en": {Name: "Iced Milk Coffee"},
+		},
+		Category: Category{
+			Name:        "Minuman",
+			Description: "Minuman dingin dan panas",
+			Translations: map[string]Translation{
+				"en": {Name: "Drinks", Description: "Hot and cold drinks"},
+			},
+		},
+	}
+
+	english := product
+	english.Localize("en")
+	if english.Name != "Iced Milk Coffee" || english.Description != "Kopi dengan susu dan gula aren" {
+		t.Fatalf("expected translated name and default description, got %q / %q", english.Name, english.Description)
+	}
+	if english.Category.Name != "Drinks" || english.Category.Description != "Hot and cold drinks" {
+		t.Fatalf("expected translated category, got %+v", english.Category)
+	}
+
+	indonesian := product
+	indonesian.Localize(DefaultLocale)
+	if indonesian.Name != "Es Kopi Susu" || indonesian.Category.Description != "Minuman dingin dan panas" {
+		t.Fatalf("expected default names, got %+v", indonesian)
+	}
+}
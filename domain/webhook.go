@@ -0,0 +1,56 @@
+package domain
+
+import "time"
+
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliveryDelivered WebhookDeliveryStatus = "delivered"
+	WebhookDeliveryDead      WebhookDeliveryStatus = "dead"
+)
+
+type WebhookSubscription struct {
+	ID         uint        `json:"id" gorm:"primaryKey"`
+	URL        string      `json:"url" gorm:"size:2048;not null"`
+	Secret     string      `json:"-" gorm:"size:255;not null"`
+	EventTypes []EventType `json:"event_types" gorm:"serializer:json"`
+	Active     bool        `json:"active" gorm:"default:true"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+}
+
+type WebhookSubscriptionForm struct {
+	URL        string      `json:"url" binding:"required,url,max=2048"`
+	Secret     string      `json:"secret" binding:"required,min=16,max=255"`
+	EventTypes []EventType `json:"event_types" binding:"required,min=1,dive,oneof=order.created order.paid order.cancelled order.updated order.deleted product.created product.updated product.deleted category.created category.updated category.deleted"`
+}
+
+// Subscribes reports whether the subscription wants events of the given type.
+func (s WebhookSubscription) Subscribes(eventType EventType) bool {
+	for _, t := range s.EventTypes {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one attempt-tracked send of an event to a subscription.
+// Deliveries that exhaust their retries stay in the table with status dead.
+// An event is delivered at most once per subscription.
+type WebhookDelivery struct {
+	ID             uint                  `json:"id" gorm:"primaryKey"`
+	SubscriptionID uint                  `json:"subscription_id" gorm:"uniqueIndex:idx_webhook_deliveries_event"`
+	EventID        string                `json:"event_id" gorm:"size:64;uniqueIndex:idx_webhook_deliveries_event;index"`
+	EventType      EventType             `json:"event_type" gorm:"size:64"`
+	Payload        string                `json:"payload" gorm:"type:longtext"`
+	Status         WebhookDeliveryStatus `json:"status" gorm:"size:32;index:idx_webhook_deliveries_due"`
+	Attempts       int                   `json:"attempts"`
+	LastError      string                `json:"last_error" gorm:"type:text"`
+	ResponseStatus int                   `json:"response_status"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" gorm:"index:idx_webhook_deliveries_due"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at"`
+}
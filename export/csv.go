@@ -0,0 +1,66 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{w: csv.NewWriter(w)}
+}
+
+func (c *csvWriter) WriteRow(cells ...interface{}) error {
+	record := make([]string, len(cells))
+	for i, cell := range cells {
+		record[i] = formatCell(cell)
+	}
+	if err := c.w.Write(record); err != nil {
+		return err
+	}
+
+	// Flush berkala supaya data langsung mengalir ke client
+	c.rows++
+	if c.rows%500 == 0 {
+		c.w.Flush()
+		return c.w.Error()
+	}
+	return nil
+}
+
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+func formatCell(cell interface{}) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return escapeFormula(v)
+	case int, int64, uint:
+		return fmt.Sprint(v)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return escapeFormula(fmt.Sprint(v))
+	}
+}
+
+// escapeFormula prefixes text that a spreadsheet would evaluate as a formula
+// with a quote, so names like "=HYPERLINK(...)" are shown as text (CSV/formula
+// injection). Numbers are written by type and never escaped.
+func escapeFormula(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,40 @@
+package export
+
+import (
+	"errors"
+	"io"
+)
+
+const (
+	FormatCSV  = "csv"
+	FormatXLSX = "xlsx"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+// Writer streams tabular rows to an output. Cells may be string, int, int64,
+// uint or float64; numbers keep their type in XLSX.
+type Writer interface {
+	WriteRow(cells ...interface{}) error
+	Close() error
+}
+
+// NewWriter returns a streaming writer for the format.
+func NewWriter(w io.Writer, format, sheetName string) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w), nil
+	case FormatXLSX:
+		return newXLSXWriter(w, sheetName)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+}
+
+// ContentType returns the MIME type of the format.
+func ContentType(format string) string {
+	if format == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
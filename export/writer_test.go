@@ -0,0 +1,119 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestEscapeFormula(t *testing.T) {
+	tests := map[string]string{
+		"":                     "",
+		"Kopi Susu":            "Kopi Susu",
+		"=HYPERLINK(\"x\")":    "'=HYPERLINK(\"x\")",
+		"+62812":               "'+62812",
+		"-5":                   "'-5",
+		"@SUM(A1)":             "'@SUM(A1)",
+		"\tcmd":                "'\tcmd",
+		"\rcmd":                "'\rcmd",
+		"a=b":                  "a=b",
+		"'already quoted":      "'already quoted",
+		" =leading whitespace": " =leading whitespace",
+	}
+	for input, want := range tests {
+		if got := escapeFormula(input); got != want {
+			t.Errorf("escapeFormula(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestCSVWriterEscapesTextOnly(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatCSV, "products")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("=1+1", -5, -2.5, uint(3), nil, "Kopi"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"'=1+1", "-5", "-2.5", "3", "", "Kopi"}
+	if len(records) != 1 || strings.Join(records[0], "|") != strings.Join(want, "|") {
+		t.Fatalf("expected %q, got %q", want, records)
+	}
+}
+
+func TestXLSXWriterEscapesTextOnly(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatXLSX, "orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("@cmd", -10, "<b>"); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	sheet := readZipFile(t, buf.Bytes(), "xl/worksheets/sheet1.xml")
+	for _, want := range []string{
+		`<c r="A1" t="inlineStr"><is><t xml:space="preserve">&#39;@cmd</t></is></c>`,
+		`<c r="B1"><v>-10</v></c>`,
+		`<t xml:space="preserve">&lt;b&gt;</t>`,
+	} {
+		if !strings.Contains(sheet, want) {
+			t.Fatalf("expected sheet to contain %s, got %s", want, sheet)
+		}
+	}
+}
+
+func TestNewWriterUnsupportedFormat(t *testing.T) {
+	if _, err := NewWriter(io.Discard, "pdf", "orders"); err != ErrUnsupportedFormat {
+		t.Fatalf("expected ErrUnsupportedFormat, got %v", err)
+	}
+}
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, want := range tests {
+		if got := columnName(index); got != want {
+			t.Errorf("columnName(%d) = %s, want %s", index, got, want)
+		}
+	}
+}
+
+func readZipFile(t *testing.T, data []byte, name string) string {
+	t.Helper()
+	r, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range r.File {
+		if f.Name != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rc.Close()
+		body, err := io.ReadAll(rc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(body)
+	}
+	t.Fatalf("%s not found in workbook", name)
+	return ""
+}
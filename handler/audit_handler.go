@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"net/http"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService service.AuditService
+}
+
+func NewAuditHandler(auditService service.AuditService) *AuditHandler {
+	return &AuditHandler{auditService}
+}
+
+func (h *AuditHandler) GetLogs(c *gin.Context) {
+	var req domain.AuditQueryForm
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	logs, err := h.auditService.GetLogs(&req)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Audit logs retrieved successfully", logs, nil)
+}
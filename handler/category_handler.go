@@ -1,8 +1,8 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
-	"strconv"
 
 	"crud-clean-architecture/domain"
 	"crud-clean-architecture/service"
@@ -46,9 +46,10 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 
 	// Simpan kategori baru
 	category := domain.Category{
-		Name: req.Name,
+		Name:        req.Name,
+		Description: req.Description,
 	}
-	if err := h.categoryService.CreateCategory(&category); err != nil {
+	if err := h.categoryService.CreateCategory(c.Request.Context(), &category); err != nil {
 		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
 		return
 	}
@@ -57,7 +58,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 }
 
 func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
-	categories, err := h.categoryService.GetAllCategories()
+	categories, err := h.categoryService.GetAllCategories(requestLocale(c))
 	if err != nil {
 		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
 		return
@@ -67,25 +68,32 @@ func (h *CategoryHandler) GetAllCategories(c *gin.Context) {
 }
 
 func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
 		return
 	}
 
-	category, err := h.categoryService.GetCategoryByID(uint(id))
+	category, err := h.categoryService.GetCategoryByID(id)
 	if err != nil {
 		utils.JSONResponse(c, http.StatusNotFound, "Category not found", nil, nil)
 		return
 	}
+	locale := requestLocale(c)
+	if writeETag(c, categoryETag(c, category, locale)) {
+		return
+	}
+	category.Localize(locale)
 
 	utils.JSONResponse(c, http.StatusOK, "Category retrieved successfully", category, nil)
 }
 
 func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 	var req domain.CategoryForm
@@ -97,29 +105,159 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 	category := domain.Category{
-		Name: req.Name,
+		Name:        req.Name,
+		Description: req.Description,
+		Version:     version,
 	}
-	category.ID = uint(id)
-	if err := h.categoryService.UpdateCategory(&category); err != nil {
-		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+	category.ID = id
+	if err := h.categoryService.UpdateCategory(c.Request.Context(), &category); err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErr.Errors)
+			return
+		}
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
 		return
 	}
 
+	c.Header("ETag", categoryETag(c, &category, domain.DefaultLocale))
 	utils.JSONResponse(c, http.StatusOK, "Category updated successfully", category, nil)
 }
 
-func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+func (h *CategoryHandler) PatchCategory(c *gin.Context) {
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.PatchCategory(c.Request.Context(), id, version, patch)
 	if err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", categoryETag(c, category, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Category updated successfully", category, nil)
+}
+
+func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
 		return
 	}
 	// Hapus kategori
-	err = h.categoryService.DeleteCategory(uint(id))
+	err := h.categoryService.DeleteCategory(c.Request.Context(), id, version)
 	if err != nil {
-		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
 		return
 	}
 
 	utils.JSONResponse(c, http.StatusOK, "Category deleted successfully", nil, nil)
 }
+
+func (h *CategoryHandler) ApplyBatch(c *gin.Context) {
+	var req domain.CategoryBatchForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	results, err := h.categoryService.ApplyBatch(c.Request.Context(), req.Operations)
+	if errors.Is(err, service.ErrBatchRejected) {
+		utils.JSONResponse(c, http.StatusUnprocessableEntity, err.Error(), results, nil)
+		return
+	}
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Categories batch applied successfully", results, nil)
+}
+
+func (h *CategoryHandler) SetAttributeSchema(c *gin.Context) {
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req domain.AttributeSchemaForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	category, err := h.categoryService.SetAttributeSchema(c.Request.Context(), id, version, &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", categoryETag(c, category, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Category attributes updated successfully", category, nil)
+}
+
+func (h *CategoryHandler) SetTranslation(c *gin.Context) {
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TranslationForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	category, err := h.categoryService.SetTranslation(c.Request.Context(), id, version, c.Param("locale"), &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", categoryETag(c, category, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Category translation updated successfully", category, nil)
+}
+
+func (h *CategoryHandler) DeleteTranslation(c *gin.Context) {
+	id, ok := resolveID(c, h.categoryService.ResolveCategory)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	category, err := h.categoryService.SetTranslation(c.Request.Context(), id, version, c.Param("locale"), nil)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", categoryETag(c, category, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Category translation deleted successfully", category, nil)
+}
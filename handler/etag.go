@@ -0,0 +1,85 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// writeETag sets the ETag header and answers 304 when the client's
+// If-None-Match already has it. It reports whether the response was sent.
+func writeETag(c *gin.Context, etag string) bool {
+	c.Header("ETag", etag)
+	if utils.ETagMatches(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return true
+	}
+	return false
+}
+
+// productETag includes the version of the embedded category, which changes
+// without bumping the product's own version, and the languages of the
+// response. If-Match only compares the product version.
+func productETag(c *gin.Context, product *domain.Product, locale string) string {
+	etag := utils.ETag(product.Version)
+	if product.Category.ID != 0 {
+		etag = utils.ETag(product.Version, product.Category.Version)
+	}
+	return localizedETag(c, etag, locale)
+}
+
+func categoryETag(c *gin.Context, category *domain.Category, locale string) string {
+	return localizedETag(c, utils.ETag(category.Version), locale)
+}
+
+// localizedETag menambahkan locale katalog, dan locale pesan jika berbeda,
+// karena keduanya mengubah isi response
+func localizedETag(c *gin.Context, etag, locale string) string {
+	if messageLocale := utils.MessageLocale(c); messageLocale != locale {
+		return utils.WithLocale(etag, locale, messageLocale)
+	}
+	return utils.WithLocale(etag, locale)
+}
+
+// ifMatchVersion returns the version from the If-Match header, 0 for "*".
+// It answers 428 when the header is missing and 400 when it is not a single ETag.
+func ifMatchVersion(c *gin.Context) (uint, bool) {
+	header := c.GetHeader("If-Match")
+	if header == "" {
+		utils.JSONResponse(c, http.StatusPreconditionRequired, "If-Match header is required", nil, nil)
+		return 0, false
+	}
+	if strings.Contains(header, ",") {
+		utils.JSONResponse(c, http.StatusBadRequest, "If-Match must contain a single ETag", nil, nil)
+		return 0, false
+	}
+	version, err := utils.ParseETag(header)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return 0, false
+	}
+	return version, true
+}
+
+func catalogErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrVersionConflict):
+		return http.StatusPreconditionFailed
+	case errors.Is(err, repository.ErrProductNotFound), errors.Is(err, repository.ErrCategoryNotFound),
+		errors.Is(err, repository.ErrPriceNotFound), errors.Is(err, repository.ErrVariantNotFound),
+		errors.Is(err, repository.ErrModifierGroupNotFound), errors.Is(err, repository.ErrImageNotFound),
+		errors.Is(err, repository.ErrTagNotFound), errors.Is(err, repository.ErrTranslationNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, repository.ErrPriceAlreadyEffective), errors.Is(err, repository.ErrProductInBundle),
+		errors.Is(err, repository.ErrInvalidComponent):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
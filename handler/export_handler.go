@@ -0,0 +1,135 @@
+package handler
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/export"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExportHandler struct {
+	exportService service.ExportService
+}
+
+func NewExportHandler(exportService service.ExportService) *ExportHandler {
+	return &ExportHandler{exportService}
+}
+
+func (h *ExportHandler) ExportOrders(c *gin.Context) {
+	var query domain.OrderQueryForm
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+	filter, err := query.Filter()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+
+	h.export(c, &domain.Export{Type: domain.ExportTypeOrders, OrderFilter: filter})
+}
+
+func (h *ExportHandler) ExportProducts(c *gin.Context) {
+	var query domain.ProductQueryForm
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	query.Attributes = c.QueryMap("attr")
+	filter, err := query.Filter()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+
+	h.export(c, &domain.Export{Type: domain.ExportTypeProducts, ProductFilter: filter})
+}
+
+func (h *ExportHandler) GetExport(c *gin.Context) {
+	exp, err := h.exportService.GetExport(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, exportErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Export retrieved successfully", exp, nil)
+}
+
+func (h *ExportHandler) DownloadExport(c *gin.Context) {
+	exp, err := h.exportService.GetExport(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, exportErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	path, err := h.exportService.FilePath(exp)
+	if err != nil {
+		utils.JSONResponse(c, exportErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	c.FileAttachment(path, exp.FileName)
+}
+
+// export menjalankan export di background jika diminta atau datanya besar, selain itu di-stream langsung
+func (h *ExportHandler) export(c *gin.Context, exp *domain.Export) {
+	var form domain.ExportForm
+	if err := c.ShouldBindQuery(&form); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+	exp.Format = form.Format
+	if exp.Format == "" {
+		exp.Format = export.FormatCSV
+	}
+
+	async := form.Async
+	if !async {
+		large, err := h.exportService.ShouldRunAsync(exp)
+		if err != nil {
+			utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+			return
+		}
+		async = large
+	}
+
+	if async {
+		if err := h.exportService.StartExport(c.Request.Context(), exp); err != nil {
+			utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+			return
+		}
+		utils.JSONResponse(c, http.StatusAccepted, "Export queued", exp, nil)
+		return
+	}
+
+	c.Header("Content-Type", export.ContentType(exp.Format))
+	c.Header("Content-Disposition", `attachment; filename="`+service.ExportFileName(exp)+`"`)
+	c.Status(http.StatusOK)
+	// Header sudah terkirim, jadi error di tengah stream hanya bisa dicatat
+	if _, err := h.exportService.Stream(c.Request.Context(), c.Writer, exp); err != nil {
+		log.Printf("export %s: %v", exp.Type, err)
+	}
+}
+
+func exportErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, repository.ErrExportNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrExportNotReady):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
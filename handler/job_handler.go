@@ -0,0 +1,74 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"crud-clean-architecture/queue"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type JobHandler struct {
+	jobService service.JobService
+}
+
+func NewJobHandler(jobService service.JobService) *JobHandler {
+	return &JobHandler{jobService}
+}
+
+func (h *JobHandler) GetStats(c *gin.Context) {
+	stats, err := h.jobService.GetStats()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Queue stats retrieved successfully", stats, nil)
+}
+
+func (h *JobHandler) GetDeadJobs(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit <= 0 {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid limit", nil, nil)
+		return
+	}
+
+	jobs, err := h.jobService.GetDeadJobs(int64(limit))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Dead jobs retrieved successfully", jobs, nil)
+}
+
+func (h *JobHandler) RetryDeadJob(c *gin.Context) {
+	job, err := h.jobService.RetryDeadJob(c.Param("id"))
+	if err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Job re-queued successfully", job, nil)
+}
+
+func (h *JobHandler) DeleteDeadJob(c *gin.Context) {
+	if err := h.jobService.DeleteDeadJob(c.Param("id")); err != nil {
+		if errors.Is(err, queue.ErrJobNotFound) {
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Job deleted successfully", nil, nil)
+}
@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// requestLocale picks the catalog locale from ?lang= or Accept-Language and
+// falls back to the default locale.
+func requestLocale(c *gin.Context) string {
+	c.Header("Vary", "Accept-Language")
+	locale := utils.NegotiateLocale(c.Query("lang"), domain.SupportedLocales)
+	if locale == "" {
+		locale = utils.NegotiateLocale(c.GetHeader("Accept-Language"), domain.SupportedLocales)
+	}
+	if locale == "" {
+		locale = domain.DefaultLocale
+	}
+	c.Header("Content-Language", locale)
+	return locale
+}
@@ -1,10 +1,12 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
 	"crud-clean-architecture/service"
 	"crud-clean-architecture/utils"
 
@@ -28,7 +30,21 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		return
 	}
 
-	err := h.orderService.CreateOrder(&order)
+	err := h.orderService.CreateOrder(c.Request.Context(), &order)
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		utils.JSONResponse(c, http.StatusBadRequest, "Some items are not available", nil, validationErr.Errors)
+		return
+	}
+	if errors.Is(err, service.ErrVariantRequired) || errors.Is(err, service.ErrVariantNotInProduct) ||
+		errors.Is(err, service.ErrInvalidModifiers) {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+	if errors.Is(err, repository.ErrInsufficientStock) {
+		utils.JSONResponse(c, http.StatusConflict, err.Error(), nil, nil)
+		return
+	}
 	if err != nil {
 		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
 		return
@@ -38,7 +54,19 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 }
 
 func (h *OrderHandler) GetAllOrders(c *gin.Context) {
-	orders, err := h.orderService.GetAllOrders()
+	var query domain.OrderQueryForm
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+	filter, err := query.Filter()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+
+	orders, err := h.orderService.GetOrders(filter)
 	if err != nil {
 		utils.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch orders", nil, nil)
 		return
@@ -52,7 +80,7 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 
 	order, err := h.orderService.GetOrderByID(uint(id))
 	if err != nil {
-		utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+		utils.JSONResponse(c, paymentErrorStatus(err), err.Error(), nil, nil)
 		return
 	}
 
@@ -62,11 +90,110 @@ func (h *OrderHandler) GetOrderByID(c *gin.Context) {
 func (h *OrderHandler) DeleteOrder(c *gin.Context) {
 	id, _ := strconv.Atoi(c.Param("id"))
 
-	err := h.orderService.DeleteOrder(uint(id))
+	err := h.orderService.DeleteOrder(c.Request.Context(), uint(id))
 	if err != nil {
-		utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+		utils.JSONResponse(c, paymentErrorStatus(err), err.Error(), nil, nil)
 		return
 	}
 
 	utils.JSONResponse(c, http.StatusOK, "Order deleted successfully", nil, nil)
 }
+
+func (h *OrderHandler) CancelOrder(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	order, err := h.orderService.CancelOrder(c.Request.Context(), uint(id))
+	if err != nil {
+		utils.JSONResponse(c, paymentErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Order cancelled successfully", order, nil)
+}
+
+func (h *OrderHandler) CreatePayment(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	var req domain.PaymentForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	payment, err := h.orderService.CreatePayment(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		utils.JSONResponse(c, paymentErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Payment recorded successfully", payment, nil)
+}
+
+func (h *OrderHandler) GetOrderPayments(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	payments, err := h.orderService.GetOrderPayments(uint(id))
+	if err != nil {
+		utils.JSONResponse(c, paymentErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Payments fetched successfully", payments, nil)
+}
+
+func (h *OrderHandler) UpdatePaymentStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+	paymentID, err := strconv.Atoi(c.Param("payment_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid payment ID format", nil, nil)
+		return
+	}
+
+	var req domain.PaymentStatusForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	payment, err := h.orderService.UpdatePaymentStatus(c.Request.Context(), uint(id), uint(paymentID), req.Status)
+	if err != nil {
+		utils.JSONResponse(c, paymentErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Payment updated successfully", payment, nil)
+}
+
+// paymentErrorStatus maps order and payment errors from the service to HTTP status codes.
+func paymentErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, service.ErrOrderNotFound), errors.Is(err, repository.ErrPaymentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, service.ErrOrderAlreadyPaid), errors.Is(err, service.ErrOrderCancelled),
+		errors.Is(err, service.ErrOrderPaymentPending), errors.Is(err, service.ErrInvalidPaymentStatus),
+		errors.Is(err, service.ErrOrderHasPayments):
+		return http.StatusConflict
+	case errors.Is(err, service.ErrPaymentExceedsBalance), errors.Is(err, service.ErrInsufficientTendered):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
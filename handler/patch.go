@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxPatchSize = 1 << 20
+
+// readMergePatch reads a merge patch body sent as application/merge-patch+json
+// (application/json is accepted too).
+func readMergePatch(c *gin.Context) ([]byte, bool) {
+	if contentType := c.ContentType(); contentType != "application/merge-patch+json" && contentType != "application/json" {
+		utils.JSONResponse(c, http.StatusUnsupportedMediaType, "Content-Type must be application/merge-patch+json", nil, nil)
+		return nil, false
+	}
+	patch, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxPatchSize))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid request payload", nil, nil)
+		return nil, false
+	}
+	return patch, true
+}
+
+// respondPatchError maps the errors of a merge patch, a product child update
+// (variants, modifier groups, bundle components, images) or a category
+// attribute schema update to a response.
+func respondPatchError(c *gin.Context, err error) {
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErr.Errors)
+	case errors.Is(err, service.ErrInvalidPatch):
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+	default:
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+	}
+}
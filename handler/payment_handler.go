@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"crud-clean-architecture/payment"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxWebhookBodySize = 1 << 20
+
+type PaymentHandler struct {
+	paymentService service.PaymentService
+}
+
+func NewPaymentHandler(paymentService service.PaymentService) *PaymentHandler {
+	return &PaymentHandler{paymentService}
+}
+
+func (h *PaymentHandler) ReceiveWebhook(c *gin.Context) {
+	// Body mentah dibutuhkan untuk verifikasi signature
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxWebhookBodySize))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Failed to read request body", nil, nil)
+		return
+	}
+
+	event, err := h.paymentService.HandleWebhook(c.Request.Context(), c.Param("provider"), body, c.GetHeader(payment.SignatureHeader))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrDuplicatePaymentEvent):
+			// Provider mengirim ulang event yang sama; balas sukses agar tidak di-retry lagi
+			utils.JSONResponse(c, http.StatusOK, "Event already processed", nil, nil)
+		case errors.Is(err, payment.ErrInvalidSignature):
+			utils.JSONResponse(c, http.StatusUnauthorized, err.Error(), nil, nil)
+		case errors.Is(err, payment.ErrProviderNotFound), errors.Is(err, repository.ErrPaymentNotFound):
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+		case errors.Is(err, payment.ErrWebhookNotSupported), errors.Is(err, payment.ErrInvalidWebhook),
+			errors.Is(err, payment.ErrUnknownGatewayStatus):
+			utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		default:
+			utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		}
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Webhook processed successfully", event, nil)
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -45,11 +46,18 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 
 	// Simpan produk baru
 	product := domain.Product{
-		Name:       req.Name,
-		Price:      req.Price,
-		CategoryID: req.CategoryID,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		CategoryID:  req.CategoryID,
+		Attributes:  req.Attributes,
 	}
-	if err := h.productService.CreateProduct(&product); err != nil {
+	if err := h.productService.CreateProduct(c.Request.Context(), &product); err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErr.Errors)
+			return
+		}
 		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
 		return
 	}
@@ -58,65 +66,566 @@ func (h *ProductHandler) CreateProduct(c *gin.Context) {
 }
 
 func (h *ProductHandler) GetAllProducts(c *gin.Context) {
-	products, err := h.productService.GetAllProducts()
+	var query domain.ProductQueryForm
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	query.Attributes = c.QueryMap("attr")
+	filter, err := query.Filter()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+
+	if err := h.productService.ResolveAvailability(&filter); err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch products", nil, nil)
+		return
+	}
+
+	products, err := h.productService.GetProducts(filter, requestLocale(c))
 	if err != nil {
 		utils.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch products", nil, nil)
 		return
 	}
+	facets, err := h.productService.GetFacets(filter)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch product facets", nil, nil)
+		return
+	}
 
-	utils.JSONResponse(c, http.StatusOK, "Products retrieved successfully", products, nil)
+	utils.JSONResponseWithMeta(c, http.StatusOK, "Products retrieved successfully", products, domain.ProductListMeta{Facets: facets})
 }
 
 func (h *ProductHandler) GetProductByID(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
 		return
 	}
 
-	product, err := h.productService.GetProductByID(uint(id))
+	product, err := h.productService.GetProductByID(id)
 	if err != nil {
 		utils.JSONResponse(c, http.StatusNotFound, "Product not found", nil, nil)
 		return
 	}
+	locale := requestLocale(c)
+	if writeETag(c, productETag(c, product, locale)) {
+		return
+	}
+	product.Localize(locale)
 
 	utils.JSONResponse(c, http.StatusOK, "Product retrieved successfully", product, nil)
 }
 
 func (h *ProductHandler) UpdateProduct(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
-	if err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
 		return
 	}
 
-	var product domain.Product
-	if err := c.ShouldBindJSON(&product); err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid request payload", nil, nil)
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	// PUT mengganti seluruh field, jadi divalidasi dengan aturan yang sama seperti create
+	var req domain.ProductForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
 		return
 	}
 
-	product.ID = uint(id)
-	if err := h.productService.UpdateProduct(&product); err != nil {
-		utils.JSONResponse(c, http.StatusInternalServerError, "Failed to update product", nil, nil)
+	product := domain.Product{
+		ID:          id,
+		Name:        req.Name,
+		Description: req.Description,
+		Price:       req.Price,
+		CategoryID:  req.CategoryID,
+		Attributes:  req.Attributes,
+		Version:     version,
+	}
+	if err := h.productService.UpdateProduct(c.Request.Context(), &product); err != nil {
+		var validationErr *service.ValidationError
+		if errors.As(err, &validationErr) {
+			utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErr.Errors)
+			return
+		}
+		status := catalogErrorStatus(err)
+		message := "Failed to update product"
+		if status != http.StatusInternalServerError {
+			message = err.Error()
+		}
+		utils.JSONResponse(c, status, message, nil, nil)
 		return
 	}
 
+	c.Header("ETag", productETag(c, &product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product updated successfully", product, nil)
+}
+
+func (h *ProductHandler) PatchProduct(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+	patch, ok := readMergePatch(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.PatchProduct(c.Request.Context(), id, version, patch)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
 	utils.JSONResponse(c, http.StatusOK, "Product updated successfully", product, nil)
 }
 
 func (h *ProductHandler) DeleteProduct(c *gin.Context) {
-	id, err := strconv.Atoi(c.Param("id"))
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	err := h.productService.DeleteProduct(c.Request.Context(), id, version)
 	if err != nil {
-		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
 		return
 	}
 
-	err = h.productService.DeleteProduct(uint(id))
+	utils.JSONResponse(c, http.StatusOK, "Product deleted successfully", nil, nil)
+}
+
+func (h *ProductHandler) ApplyBatch(c *gin.Context) {
+	var req domain.ProductBatchForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	results, err := h.productService.ApplyBatch(c.Request.Context(), req.Operations)
+	if errors.Is(err, service.ErrBatchRejected) {
+		utils.JSONResponse(c, http.StatusUnprocessableEntity, err.Error(), results, nil)
+		return
+	}
 	if err != nil {
 		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
 		return
 	}
 
-	utils.JSONResponse(c, http.StatusOK, "Product deleted successfully", nil, nil)
+	utils.JSONResponse(c, http.StatusOK, "Products batch applied successfully", results, nil)
+}
+
+func (h *ProductHandler) GetPriceHistory(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	prices, err := h.productService.GetPriceHistory(id)
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Price history retrieved successfully", prices, nil)
+}
+
+func (h *ProductHandler) SchedulePrice(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.ProductPriceForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	price, err := h.productService.SchedulePrice(c.Request.Context(), id, &req)
+	if errors.Is(err, service.ErrPriceNotInFuture) {
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, map[string]string{"effective_from": err.Error()})
+		return
+	}
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Price scheduled successfully", price, nil)
+}
+
+func (h *ProductHandler) CancelScheduledPrice(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	priceID, err := strconv.Atoi(c.Param("price_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid price ID format", nil, nil)
+		return
+	}
+
+	err = h.productService.CancelScheduledPrice(c.Request.Context(), id, uint(priceID))
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Scheduled price cancelled successfully", nil, nil)
+}
+
+func (h *ProductHandler) GetVariants(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	variants, err := h.productService.GetVariants(id)
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Variants retrieved successfully", variants, nil)
+}
+
+func (h *ProductHandler) CreateVariant(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.ProductVariantForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	variant, err := h.productService.CreateVariant(c.Request.Context(), id, &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Variant created successfully", variant, nil)
+}
+
+func (h *ProductHandler) UpdateVariant(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid variant ID format", nil, nil)
+		return
+	}
+
+	var req domain.ProductVariantForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	variant, err := h.productService.UpdateVariant(c.Request.Context(), id, uint(variantID), &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Variant updated successfully", variant, nil)
+}
+
+func (h *ProductHandler) DeleteVariant(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	variantID, err := strconv.Atoi(c.Param("variant_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid variant ID format", nil, nil)
+		return
+	}
+
+	err = h.productService.DeleteVariant(c.Request.Context(), id, uint(variantID))
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Variant deleted successfully", nil, nil)
+}
+
+func (h *ProductHandler) GetModifierGroups(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	groups, err := h.productService.GetModifierGroups(id)
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Modifier groups retrieved successfully", groups, nil)
+}
+
+func (h *ProductHandler) CreateModifierGroup(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.ModifierGroupForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	group, err := h.productService.CreateModifierGroup(c.Request.Context(), id, &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Modifier group created successfully", group, nil)
+}
+
+func (h *ProductHandler) UpdateModifierGroup(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	groupID, err := strconv.Atoi(c.Param("group_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid modifier group ID format", nil, nil)
+		return
+	}
+
+	var req domain.ModifierGroupForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	group, err := h.productService.UpdateModifierGroup(c.Request.Context(), id, uint(groupID), &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Modifier group updated successfully", group, nil)
+}
+
+func (h *ProductHandler) DeleteModifierGroup(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	groupID, err := strconv.Atoi(c.Param("group_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid modifier group ID format", nil, nil)
+		return
+	}
+
+	err = h.productService.DeleteModifierGroup(c.Request.Context(), id, uint(groupID))
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Modifier group deleted successfully", nil, nil)
+}
+
+func (h *ProductHandler) SetBundleComponents(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.BundleComponentsForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	product, err := h.productService.SetBundleComponents(c.Request.Context(), id, req.Components)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Bundle components updated successfully", product, nil)
+}
+
+// ClearBundleComponents turns a bundle back into a simple product.
+func (h *ProductHandler) ClearBundleComponents(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.SetBundleComponents(c.Request.Context(), id, nil)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Bundle components removed successfully", product, nil)
+}
+
+func (h *ProductHandler) SetTags(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.ProductTagsForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	product, err := h.productService.SetTags(c.Request.Context(), id, req.TagIDs)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product tags updated successfully", product, nil)
+}
+
+func (h *ProductHandler) RemoveTag(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	tagID, err := strconv.Atoi(c.Param("tag_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid tag ID format", nil, nil)
+		return
+	}
+
+	product, err := h.productService.RemoveTag(c.Request.Context(), id, uint(tagID))
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product tag removed successfully", product, nil)
+}
+
+func (h *ProductHandler) SetTranslation(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	var req domain.TranslationForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	product, err := h.productService.SetTranslation(c.Request.Context(), id, version, c.Param("locale"), &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product translation updated successfully", product, nil)
+}
+
+func (h *ProductHandler) DeleteTranslation(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(c)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.SetTranslation(c.Request.Context(), id, version, c.Param("locale"), nil)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product translation deleted successfully", product, nil)
+}
+
+func (h *ProductHandler) SetAvailability(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.AvailabilityForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	product, err := h.productService.SetAvailability(c.Request.Context(), id, &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product availability updated successfully", product, nil)
+}
+
+func (h *ProductHandler) ClearAvailability(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	product, err := h.productService.SetAvailability(c.Request.Context(), id, nil)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	c.Header("ETag", productETag(c, product, domain.DefaultLocale))
+	utils.JSONResponse(c, http.StatusOK, "Product availability removed successfully", product, nil)
 }
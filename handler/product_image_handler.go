@@ -0,0 +1,119 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxImageFileSize = 10 << 20
+
+type ProductImageHandler struct {
+	imageService   service.ProductImageService
+	productService service.ProductService
+}
+
+func NewProductImageHandler(imageService service.ProductImageService, productService service.ProductService) *ProductImageHandler {
+	return &ProductImageHandler{imageService, productService}
+}
+
+func (h *ProductImageHandler) GetImages(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	images, err := h.imageService.GetImages(id)
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Images retrieved successfully", images, nil)
+}
+
+// UploadImage menerima gambar sebagai field multipart "file"
+func (h *ProductImageHandler) UploadImage(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImageFileSize+1<<20)
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "file is required", nil, nil)
+		return
+	}
+	if fileHeader.Size > maxImageFileSize {
+		utils.JSONResponse(c, http.StatusRequestEntityTooLarge, "File is too large", nil, nil)
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+
+	image, err := h.imageService.Upload(c.Request.Context(), id, data)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Image uploaded successfully", image, nil)
+}
+
+func (h *ProductImageHandler) ReorderImages(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+
+	var req domain.ProductImageOrderForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	images, err := h.imageService.ReorderImages(c.Request.Context(), id, req.ImageIDs)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Images reordered successfully", images, nil)
+}
+
+func (h *ProductImageHandler) DeleteImage(c *gin.Context) {
+	id, ok := resolveID(c, h.productService.ResolveProduct)
+	if !ok {
+		return
+	}
+	imageID, err := strconv.Atoi(c.Param("image_id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid image ID format", nil, nil)
+		return
+	}
+
+	err = h.imageService.DeleteImage(c.Request.Context(), id, uint(imageID))
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Image deleted successfully", nil, nil)
+}
@@ -0,0 +1,80 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxImportFileSize = 10 << 20
+
+type ProductImportHandler struct {
+	importService service.ProductImportService
+}
+
+func NewProductImportHandler(importService service.ProductImportService) *ProductImportHandler {
+	return &ProductImportHandler{importService}
+}
+
+// ImportProducts menerima CSV sebagai field multipart "file" atau langsung sebagai body request
+func (h *ProductImportHandler) ImportProducts(c *gin.Context) {
+	var form domain.ProductImportForm
+	if err := c.ShouldBindQuery(&form); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	var reader io.Reader = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportFileSize)
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		fileHeader, err := c.FormFile("file")
+		if err != nil {
+			utils.JSONResponse(c, http.StatusBadRequest, "file is required", nil, nil)
+			return
+		}
+		if fileHeader.Size > maxImportFileSize {
+			utils.JSONResponse(c, http.StatusRequestEntityTooLarge, "File is too large", nil, nil)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+			return
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	rows, err := h.importService.ParseCSV(reader)
+	if err != nil {
+		status := http.StatusBadRequest
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			status = http.StatusRequestEntityTooLarge
+		}
+		utils.JSONResponse(c, status, err.Error(), nil, nil)
+		return
+	}
+
+	report, err := h.importService.Import(c.Request.Context(), rows, &form)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	switch {
+	case report.DryRun:
+		utils.JSONResponse(c, http.StatusOK, "Dry run completed, nothing was imported", report, nil)
+	case report.Commit == domain.ProductImportAtomic && report.Invalid > 0:
+		utils.JSONResponse(c, http.StatusUnprocessableEntity, "Import has invalid rows, nothing was imported", report, nil)
+	default:
+		utils.JSONResponse(c, http.StatusOK, "Products imported successfully", report, nil)
+	}
+}
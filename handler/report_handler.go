@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReportHandler struct {
+	reportService service.ReportService
+}
+
+func NewReportHandler(reportService service.ReportService) *ReportHandler {
+	return &ReportHandler{reportService}
+}
+
+func (h *ReportHandler) GetRevenue(c *gin.Context) {
+	filter, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+
+	points, err := h.reportService.GetRevenueSeries(filter)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Revenue report retrieved successfully", points, nil)
+}
+
+func (h *ReportHandler) GetTopProducts(c *gin.Context) {
+	filter, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+
+	products, err := h.reportService.GetTopProducts(filter)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Top products retrieved successfully", products, nil)
+}
+
+func (h *ReportHandler) GetRevenueByCategory(c *gin.Context) {
+	filter, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+
+	categories, err := h.reportService.GetRevenueByCategory(filter)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Revenue by category retrieved successfully", categories, nil)
+}
+
+func (h *ReportHandler) GetAverageOrderValue(c *gin.Context) {
+	filter, ok := h.bindFilter(c)
+	if !ok {
+		return
+	}
+
+	summary, err := h.reportService.GetOrderValueSummary(filter)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Average order value retrieved successfully", summary, nil)
+}
+
+// bindFilter validasi query string dan mengubahnya menjadi filter report
+func (h *ReportHandler) bindFilter(c *gin.Context) (domain.ReportFilter, bool) {
+	var req domain.ReportQueryForm
+	if err := c.ShouldBindQuery(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return domain.ReportFilter{}, false
+	}
+
+	filter, err := h.reportService.ParseFilter(&req)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return domain.ReportFilter{}, false
+	}
+	return filter, true
+}
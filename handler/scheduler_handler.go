@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"crud-clean-architecture/scheduler"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SchedulerHandler struct {
+	schedulerService service.SchedulerService
+}
+
+func NewSchedulerHandler(schedulerService service.SchedulerService) *SchedulerHandler {
+	return &SchedulerHandler{schedulerService}
+}
+
+func (h *SchedulerHandler) GetJobs(c *gin.Context) {
+	jobs, err := h.schedulerService.GetJobs()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Scheduled jobs retrieved successfully", jobs, nil)
+}
+
+func (h *SchedulerHandler) GetJobRuns(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit <= 0 {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid limit", nil, nil)
+		return
+	}
+
+	runs, err := h.schedulerService.GetJobRuns(c.Param("name"), limit)
+	if err != nil {
+		if errors.Is(err, scheduler.ErrJobNotFound) {
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Job runs retrieved successfully", runs, nil)
+}
+
+func (h *SchedulerHandler) TriggerJob(c *gin.Context) {
+	run, err := h.schedulerService.TriggerJob(c.Param("name"))
+	if err != nil {
+		switch {
+		case errors.Is(err, scheduler.ErrJobNotFound):
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+		case errors.Is(err, scheduler.ErrJobRunning):
+			utils.JSONResponse(c, http.StatusConflict, err.Error(), nil, nil)
+		default:
+			utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		}
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusAccepted, "Job triggered successfully", run, nil)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// resolveID reads the :id path parameter as an ID or slug. A GET with an old
+// slug is redirected to the same path with the current slug. It reports
+// whether the handler should continue.
+func resolveID(c *gin.Context, resolve func(idOrSlug string) (uint, string, error)) (uint, bool) {
+	param := c.Param("id")
+	id, redirect, err := resolve(param)
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return 0, false
+	}
+	if redirect != "" && (c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead) {
+		// Ganti segmen slug lama saja, sisa path dan query tetap
+		location := *c.Request.URL
+		segments := strings.Split(location.Path, "/")
+		for i, segment := range segments {
+			if segment == param {
+				segments[i] = redirect
+				break
+			}
+		}
+		location.Path = strings.Join(segments, "/")
+		location.RawPath = ""
+		c.Redirect(http.StatusMovedPermanently, location.RequestURI())
+		return 0, false
+	}
+	return id, true
+}
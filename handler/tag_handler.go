@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TagHandler struct {
+	tagService service.TagService
+}
+
+func NewTagHandler(tagService service.TagService) *TagHandler {
+	return &TagHandler{tagService}
+}
+
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	var req domain.TagForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	tag, err := h.tagService.CreateTag(c.Request.Context(), &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Tag created successfully", tag, nil)
+}
+
+// GetAllTags returns the tags with product counts; the product list filters
+// (category_id, tags, tag_mode, attr[...]) restrict which products are counted.
+func (h *TagHandler) GetAllTags(c *gin.Context) {
+	var query domain.ProductQueryForm
+	if err := c.ShouldBindQuery(&query); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+	query.Attributes = c.QueryMap("attr")
+	filter, err := query.Filter()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, err.Error(), nil, nil)
+		return
+	}
+
+	tags, err := h.tagService.GetTagCounts(filter)
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, "Failed to fetch tags", nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Tags retrieved successfully", tags, nil)
+}
+
+func (h *TagHandler) GetTagByID(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	tag, err := h.tagService.GetTagByID(uint(id))
+	if err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Tag retrieved successfully", tag, nil)
+}
+
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	var req domain.TagForm
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	tag, err := h.tagService.UpdateTag(c.Request.Context(), uint(id), &req)
+	if err != nil {
+		respondPatchError(c, err)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Tag updated successfully", tag, nil)
+}
+
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	if err := h.tagService.DeleteTag(c.Request.Context(), uint(id)); err != nil {
+		utils.JSONResponse(c, catalogErrorStatus(err), err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Tag deleted successfully", nil, nil)
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/service"
+	"crud-clean-architecture/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookHandler struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookHandler(webhookService service.WebhookService) *WebhookHandler {
+	return &WebhookHandler{webhookService}
+}
+
+func (h *WebhookHandler) CreateSubscription(c *gin.Context) {
+	var req domain.WebhookSubscriptionForm
+
+	// Validasi input
+	if err := c.ShouldBindJSON(&req); err != nil {
+		validationErrors := utils.FormatValidationErrors(err)
+		utils.JSONResponse(c, http.StatusBadRequest, "Validation error", nil, validationErrors)
+		return
+	}
+
+	subscription := domain.WebhookSubscription{
+		URL:        req.URL,
+		Secret:     req.Secret,
+		EventTypes: req.EventTypes,
+	}
+	if err := h.webhookService.CreateSubscription(c.Request.Context(), &subscription); err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusCreated, "Webhook subscription created successfully", subscription, nil)
+}
+
+func (h *WebhookHandler) GetAllSubscriptions(c *gin.Context) {
+	subscriptions, err := h.webhookService.GetAllSubscriptions()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Webhook subscriptions retrieved successfully", subscriptions, nil)
+}
+
+func (h *WebhookHandler) DeleteSubscription(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), uint(id)); err != nil {
+		if errors.Is(err, repository.ErrWebhookSubscriptionNotFound) {
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+			return
+		}
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Webhook subscription deleted successfully", nil, nil)
+}
+
+func (h *WebhookHandler) GetDeadLetters(c *gin.Context) {
+	deliveries, err := h.webhookService.GetDeadLetters()
+	if err != nil {
+		utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Dead letters retrieved successfully", deliveries, nil)
+}
+
+func (h *WebhookHandler) RetryDelivery(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.JSONResponse(c, http.StatusBadRequest, "Invalid ID format", nil, nil)
+		return
+	}
+
+	delivery, err := h.webhookService.RetryDelivery(uint(id))
+	if err != nil {
+		switch {
+		case errors.Is(err, repository.ErrWebhookDeliveryNotFound):
+			utils.JSONResponse(c, http.StatusNotFound, err.Error(), nil, nil)
+		case errors.Is(err, service.ErrDeliveryNotDead):
+			utils.JSONResponse(c, http.StatusConflict, err.Error(), nil, nil)
+		default:
+			utils.JSONResponse(c, http.StatusInternalServerError, err.Error(), nil, nil)
+		}
+		return
+	}
+
+	utils.JSONResponse(c, http.StatusOK, "Delivery re-queued successfully", delivery, nil)
+}
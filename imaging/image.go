@@ -0,0 +1,117 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	_ "image/gif" // daftarkan decoder gif untuk image.Decode
+	"image/jpeg"
+	"image/png"
+)
+
+// MaxPixels membatasi ukuran gambar yang di-decode supaya file kecil dengan
+// dimensi raksasa tidak menghabiskan memori
+const MaxPixels = 40_000_000
+
+var (
+	ErrUnsupportedImage = errors.New("unsupported image format, use jpeg, png or gif")
+	ErrImageTooLarge    = errors.New("image dimensions are too large")
+)
+
+// Image is a decoded upload with the content type and file extension of its format.
+type Image struct {
+	Image       image.Image
+	Format      string
+	ContentType string
+	Extension   string
+}
+
+var formats = map[string]struct{ contentType, extension string }{
+	"jpeg": {"image/jpeg", ".jpg"},
+	"png":  {"image/png", ".png"},
+	"gif":  {"image/gif", ".gif"},
+}
+
+// Decode checks the format and dimensions before decoding the whole image.
+func Decode(data []byte) (*Image, error) {
+	config, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	info, ok := formats[format]
+	if !ok {
+		return nil, ErrUnsupportedImage
+	}
+	if config.Width*config.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, ErrUnsupportedImage
+	}
+	return &Image{Image: img, Format: format, ContentType: info.contentType, Extension: info.extension}, nil
+}
+
+// Thumbnail scales img down to fit in a size x size box, keeping the aspect
+// ratio. Each target pixel is the average of the source pixels it covers,
+// which looks noticeably better than nearest-neighbour for photos.
+func Thumbnail(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= size && height <= size {
+		return img
+	}
+
+	targetWidth, targetHeight := size, size
+	if width > height {
+		targetHeight = max(1, height*size/width)
+	} else {
+		targetWidth = max(1, width*size/height)
+	}
+
+	// Salin ke RGBA dulu supaya akses piksel cepat untuk semua tipe image
+	src := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(src, src.Bounds(), img, bounds.Min, draw.Src)
+
+	dst := image.NewRGBA(image.Rect(0, 0, targetWidth, targetHeight))
+	for y := 0; y < targetHeight; y++ {
+		y0, y1 := y*height/targetHeight, max((y+1)*height/targetHeight, y*height/targetHeight+1)
+		for x := 0; x < targetWidth; x++ {
+			x0, x1 := x*width/targetWidth, max((x+1)*width/targetWidth, x*width/targetWidth+1)
+
+			var r, g, b, a, count uint64
+			for sy := y0; sy < y1; sy++ {
+				offset := src.PixOffset(x0, sy)
+				for sx := x0; sx < x1; sx++ {
+					r += uint64(src.Pix[offset])
+					g += uint64(src.Pix[offset+1])
+					b += uint64(src.Pix[offset+2])
+					a += uint64(src.Pix[offset+3])
+					offset += 4
+					count++
+				}
+			}
+			dst.SetRGBA(x, y, color.RGBA{uint8(r / count), uint8(g / count), uint8(b / count), uint8(a / count)})
+		}
+	}
+	return dst
+}
+
+// EncodeThumbnail writes PNG for sources that may be transparent and JPEG
+// otherwise, returning the data and its content type and extension.
+func EncodeThumbnail(img image.Image, format string) ([]byte, string, string, error) {
+	var buf bytes.Buffer
+	if format == "png" || format == "gif" {
+		if err := png.Encode(&buf, img); err != nil {
+			return nil, "", "", err
+		}
+		return buf.Bytes(), "image/png", ".png", nil
+	}
+	if err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: 85}); err != nil {
+		return nil, "", "", err
+	}
+	return buf.Bytes(), "image/jpeg", ".jpg", nil
+}
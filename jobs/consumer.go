@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/queue"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// OutboxConsumer turns outbox events into background jobs.
+type OutboxConsumer struct {
+	queue *queue.Queue
+}
+
+func NewOutboxConsumer(q *queue.Queue) *OutboxConsumer {
+	return &OutboxConsumer{q}
+}
+
+func (c *OutboxConsumer) Name() string {
+	return "jobs"
+}
+
+func (c *OutboxConsumer) Consume(ctx context.Context, message *domain.OutboxMessage) error {
+	switch message.EventType {
+	case domain.EventOrderPaid:
+		// Unique key mencegah invoice dobel karena relay bersifat at-least-once
+		_, err := c.queue.Enqueue(ctx, TypeRenderInvoice, RenderInvoicePayload{OrderID: message.AggregateID},
+			queue.WithUniqueKey(fmt.Sprintf("invoice:%d", message.AggregateID), 7*24*time.Hour))
+		if errors.Is(err, queue.ErrDuplicateJob) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
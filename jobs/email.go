@@ -0,0 +1,68 @@
+package jobs
+
+import (
+	"context"
+	"crud-clean-architecture/queue"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/smtp"
+	"strings"
+)
+
+type Email struct {
+	To      []string `json:"to"`
+	Subject string   `json:"subject"`
+	HTML    string   `json:"html"`
+}
+
+// Mailer sends a single email.
+type Mailer interface {
+	Send(ctx context.Context, email Email) error
+}
+
+// LogMailer only logs emails; used when no SMTP server is configured.
+type LogMailer struct{}
+
+func (LogMailer) Send(ctx context.Context, email Email) error {
+	log.Printf("mail: to=%s subject=%q (%d bytes, SMTP not configured)",
+		strings.Join(email.To, ","), email.Subject, len(email.HTML))
+	return nil
+}
+
+// SMTPMailer sends HTML emails through an SMTP server with PLAIN auth.
+type SMTPMailer struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+func NewSMTPMailer(host, port, username, password, from string) *SMTPMailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPMailer{addr: host + ":" + port, auth: auth, from: from}
+}
+
+func (m *SMTPMailer) Send(ctx context.Context, email Email) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(email.To, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", email.Subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/html; charset=UTF-8\r\n\r\n")
+	msg.WriteString(email.HTML)
+
+	return smtp.SendMail(m.addr, m.auth, m.from, email.To, []byte(msg.String()))
+}
+
+func SendEmailHandler(mailer Mailer) queue.HandlerFunc {
+	return func(ctx context.Context, job *queue.Job) error {
+		var email Email
+		if err := json.Unmarshal(job.Payload, &email); err != nil {
+			return err
+		}
+		return mailer.Send(ctx, email)
+	}
+}
@@ -0,0 +1,20 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+
+	"crud-clean-architecture/queue"
+	"crud-clean-architecture/service"
+)
+
+// ExportHandler writes a queued export to disk.
+func ExportHandler(exportService service.ExportService) queue.HandlerFunc {
+	return func(ctx context.Context, job *queue.Job) error {
+		var payload service.ExportPayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+		return exportService.RunExport(ctx, payload.ExportID)
+	}
+}
@@ -0,0 +1,72 @@
+package jobs
+
+import (
+	"bytes"
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/queue"
+	"crud-clean-architecture/service"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html/template"
+	"log"
+	"time"
+)
+
+var invoiceTemplate = template.Must(template.New("invoice").Parse(`<!DOCTYPE html>
+<html>
+<body>
+<h2>Invoice {{.InvoiceNumber}}</h2>
+<p>Tanggal: {{.OrderDate.Format "02-01-2006 15:04"}}</p>
+<table border="1" cellpadding="4" cellspacing="0">
+<tr><th>Produk</th><th>Qty</th><th>Subtotal</th></tr>
+{{range .Details}}<tr><td>{{.Product.Name}}</td><td>{{.Quantity}}</td><td>{{printf "%.2f" .Subtotal}}</td></tr>
+{{end}}<tr><td colspan="2"><b>Total</b></td><td><b>{{printf "%.2f" .TotalPrice}}</b></td></tr>
+</table>
+<p>Status: {{.Status}} &middot; Dibayar: {{printf "%.2f" .PaidAmount}}</p>
+</body>
+</html>
+`))
+
+// RenderInvoice renders the HTML invoice for an order.
+func RenderInvoice(order *domain.Order) (string, error) {
+	var buf bytes.Buffer
+	if err := invoiceTemplate.Execute(&buf, order); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RenderInvoiceHandler renders the invoice and hands it to the email job.
+func RenderInvoiceHandler(q *queue.Queue, orderService service.OrderService, recipient string) queue.HandlerFunc {
+	return func(ctx context.Context, job *queue.Job) error {
+		var payload RenderInvoicePayload
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return err
+		}
+
+		order, err := orderService.GetOrderByID(payload.OrderID)
+		if err != nil {
+			return fmt.Errorf("order %d: %w", payload.OrderID, err)
+		}
+		html, err := RenderInvoice(order)
+		if err != nil {
+			return err
+		}
+
+		if recipient == "" {
+			log.Printf("invoice: rendered %s, INVOICE_EMAIL_TO not set so it is not mailed", order.InvoiceNumber)
+			return nil
+		}
+		_, err = q.Enqueue(ctx, TypeSendEmail, Email{
+			To:      []string{recipient},
+			Subject: "Invoice " + order.InvoiceNumber,
+			HTML:    html,
+		}, queue.WithUniqueKey("invoice-email:"+order.InvoiceNumber, 7*24*time.Hour))
+		if errors.Is(err, queue.ErrDuplicateJob) {
+			return nil
+		}
+		return err
+	}
+}
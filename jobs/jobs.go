@@ -0,0 +1,23 @@
+package jobs
+
+import (
+	"crud-clean-architecture/queue"
+	"crud-clean-architecture/service"
+)
+
+const (
+	TypeSendEmail     = "email.send"
+	TypeRenderInvoice = "invoice.render"
+)
+
+type RenderInvoicePayload struct {
+	OrderID uint `json:"order_id"`
+}
+
+// Register wires every background job handler into worker.
+func Register(worker *queue.Worker, q *queue.Queue, orderService service.OrderService, exportService service.ExportService,
+	mailer Mailer, invoiceRecipient string) {
+	worker.Register(TypeSendEmail, SendEmailHandler(mailer))
+	worker.Register(TypeRenderInvoice, RenderInvoiceHandler(q, orderService, invoiceRecipient))
+	worker.Register(service.TypeExport, ExportHandler(exportService))
+}
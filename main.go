@@ -1,15 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"log"
+	"net/http"
+	"os"
+	"os/signal"
 	"reflect"
+	"syscall"
+	"time"
 
 	"crud-clean-architecture/config"
 	"crud-clean-architecture/domain"
 	"crud-clean-architecture/handler"
+	"crud-clean-architecture/jobs"
+	"crud-clean-architecture/middleware"
+	"crud-clean-architecture/outbox"
+	"crud-clean-architecture/queue"
 	"crud-clean-architecture/repository"
 	"crud-clean-architecture/routes"
+	"crud-clean-architecture/scheduler"
 	"crud-clean-architecture/service"
+	"crud-clean-architecture/storage"
+	"crud-clean-architecture/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gin-gonic/gin/binding"
@@ -18,6 +33,18 @@ import (
 )
 
 func main() {
+	// Mode proses: api (HTTP saja), worker (background saja) atau all (keduanya)
+	mode := flag.String("mode", "all", "process mode: api, worker or all")
+	flag.Parse()
+	if *mode != "api" && *mode != "worker" && *mode != "all" {
+		log.Fatalf("unknown mode %q, expected api, worker or all", *mode)
+	}
+	runAPI := *mode == "api" || *mode == "all"
+	runWorker := *mode == "worker" || *mode == "all"
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
 		log.Println("No .env file found, using default values")
@@ -28,7 +55,14 @@ func main() {
 	db := config.InitDB()
 
 	// Migrate Database
-	err := db.AutoMigrate(&domain.Category{}, &domain.Product{}, &domain.Order{}, &domain.OrderDetail{})
+	err := db.AutoMigrate(
+		&domain.Category{}, &domain.Tag{}, &domain.Product{}, &domain.Slug{}, &domain.ProductPrice{}, &domain.ProductVariant{},
+		&domain.ModifierGroup{}, &domain.ModifierOption{}, &domain.BundleComponent{}, &domain.ProductImage{},
+		&domain.Order{}, &domain.OrderDetail{}, &domain.OrderDetailModifier{}, &domain.OrderDetailComponent{},
+		&domain.Payment{}, &domain.PaymentEvent{},
+		&domain.WebhookSubscription{}, &domain.WebhookDelivery{}, &domain.OutboxMessage{},
+		&domain.ScheduledJobRun{}, &domain.DailySalesSummary{}, &domain.AuditLog{},
+	)
 	if err != nil {
 		log.Fatalf("failed to migrate database: %v", err)
 	}
@@ -36,20 +70,109 @@ func main() {
 	// Initialize Repositories
 	categoryRepo := repository.NewCategoryRepository(db, redisClient)
 	productRepo := repository.NewProductRepository(db, redisClient)
+	tagRepo := repository.NewTagRepository(db, redisClient)
 	orderRepo := repository.NewOrderRepository(db, redisClient)
+	paymentRepo := repository.NewPaymentRepository(db, redisClient)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	schedulerRepo := repository.NewSchedulerRepository(db)
+	salesSummaryRepo := repository.NewSalesSummaryRepository(db)
+	reportRepo := repository.NewReportRepository(db, redisClient)
+	exportRepo := repository.NewExportRepository(redisClient)
+
+	// Beri slug pada kategori dan produk lama yang belum punya
+	if _, err := categoryRepo.BackfillSlugs(); err != nil {
+		log.Printf("failed to backfill category slugs: %v", err)
+	}
+	if _, err := productRepo.BackfillSlugs(); err != nil {
+		log.Printf("failed to backfill product slugs: %v", err)
+	}
+
+	// Initialize Job Queue
+	jobQueue := queue.New(redisClient)
+
+	// Initialize Payment Providers
+	paymentProviders := config.InitPaymentProviders()
+
+	// Initialize File Storage
+	fileStorage := config.InitStorage()
 
 	// Initialize Services
+	auditService := service.NewAuditService(auditRepo)
 	categoryService := service.NewCategoryService(categoryRepo)
-	productService := service.NewProductService(productRepo)
-	orderService := service.NewOrderService(orderRepo, productRepo)
+	productService := service.NewProductService(productRepo, categoryRepo, tagRepo, fileStorage)
+	tagService := service.NewTagService(tagRepo)
+	orderService := service.NewOrderService(orderRepo, productRepo, paymentRepo, paymentProviders)
+	webhookService := service.NewWebhookService(webhookRepo)
+	paymentService := service.NewPaymentService(paymentRepo, paymentProviders)
+	jobService := service.NewJobService(jobQueue)
+	reportService := service.NewReportService(reportRepo)
+	exportService := service.NewExportService(orderRepo, productRepo, exportRepo, jobQueue, exportDir())
+	productImportService := service.NewProductImportService(productRepo, categoryRepo)
+	productImageService := service.NewProductImageService(productRepo, fileStorage)
+	maintenanceService := service.NewMaintenanceService(orderService, productService, categoryService, exportService,
+		orderRepo, salesSummaryRepo, outboxRepo, webhookRepo, schedulerRepo)
+
+	// Initialize Scheduler
+	jobScheduler := scheduler.New(redisClient, schedulerRepo)
+	registerScheduledJobs(jobScheduler, maintenanceService)
+	schedulerService := service.NewSchedulerService(jobScheduler)
+
+	if runWorker {
+		// Start Webhook Dispatcher
+		webhookDispatcher := webhook.NewDispatcher(webhookRepo)
+		go webhookDispatcher.Start(ctx)
+
+		// Start Outbox Relay
+		outboxRelay := outbox.NewRelay(outboxRepo,
+			outbox.NewCacheInvalidationConsumer(redisClient),
+			outbox.NewRedisStreamConsumer(redisClient, "events"),
+			webhookDispatcher,
+			jobs.NewOutboxConsumer(jobQueue),
+		)
+		go outboxRelay.Start(ctx)
+
+		// Start Job Worker
+		jobWorker := queue.NewWorker(jobQueue)
+		jobs.Register(jobWorker, jobQueue, orderService, exportService, config.InitMailer(), os.Getenv("INVOICE_EMAIL_TO"))
+		go jobWorker.Start(ctx)
+
+		// Start Scheduler
+		go jobScheduler.Start(ctx)
+
+		log.Println("Background workers started")
+	}
+
+	if !runAPI {
+		<-ctx.Done()
+		log.Println("Worker stopped")
+		return
+	}
 
 	// Initialize Handlers
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 	productHandler := handler.NewProductHandler(productService)
+	tagHandler := handler.NewTagHandler(tagService)
 	orderHandler := handler.NewOrderHandler(orderService)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	jobHandler := handler.NewJobHandler(jobService)
+	schedulerHandler := handler.NewSchedulerHandler(schedulerService)
+	reportHandler := handler.NewReportHandler(reportService)
+	exportHandler := handler.NewExportHandler(exportService)
+	productImportHandler := handler.NewProductImportHandler(productImportService)
+	productImageHandler := handler.NewProductImageHandler(productImageService, productService)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	// Setup Router
 	r := gin.Default()
+	r.Use(middleware.RequestContext())
+
+	// Sajikan file upload jika memakai storage local
+	if local, ok := fileStorage.(*storage.LocalStorage); ok {
+		r.Static("/media", local.Dir())
+	}
 
 	// Setup custom validator
 	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
@@ -60,12 +183,86 @@ func main() {
 
 	// Register Routes
 	routes.RegisterCategoryRoutes(r.Group("/categories"), categoryHandler)
-	routes.RegisterProductRoutes(r.Group("/products"), productHandler)
-	routes.RegisterOrderRoutes(r.Group("/orders"), orderHandler)
+	routes.RegisterProductRoutes(r.Group("/products"), productHandler, exportHandler, productImportHandler, productImageHandler)
+	routes.RegisterTagRoutes(r.Group("/tags"), tagHandler)
+	routes.RegisterOrderRoutes(r.Group("/orders"), orderHandler, exportHandler)
+	routes.RegisterPaymentRoutes(r.Group("/payments"), paymentHandler)
+	routes.RegisterWebhookRoutes(r.Group("/webhooks"), webhookHandler)
+	routes.RegisterReportRoutes(r.Group("/reports"), reportHandler)
+	routes.RegisterExportRoutes(r.Group("/exports"), exportHandler)
+	routes.RegisterAuditRoutes(r.Group("/audit"), auditHandler)
+	routes.RegisterJobRoutes(r.Group("/admin/jobs"), jobHandler)
+	routes.RegisterSchedulerRoutes(r.Group("/admin/scheduler"), schedulerHandler)
 
 	// Run the Server
-	log.Println("Server running at http://localhost:8080")
-	if err := r.Run(":8080"); err != nil {
-		log.Fatalf("failed to start server: %v", err)
+	server := &http.Server{Addr: ":8080", Handler: r}
+	go func() {
+		log.Println("Server running at http://localhost:8080")
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("failed to start server: %v", err)
+		}
+	}()
+
+	// Tunggu sinyal lalu selesaikan request yang sedang berjalan sebelum keluar
+	<-ctx.Done()
+	stop()
+	log.Println("Shutting down server...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Printf("failed to shut down server gracefully: %v", err)
+	}
+	log.Println("Server stopped")
+}
+
+// exportDir adalah folder file export background (EXPORT_DIR, default ./exports)
+func exportDir() string {
+	if dir := os.Getenv("EXPORT_DIR"); dir != "" {
+		return dir
+	}
+	return "exports"
+}
+
+// registerScheduledJobs mendaftarkan job berulang; jadwal dapat diubah lewat env
+func registerScheduledJobs(s *scheduler.Scheduler, maintenance service.MaintenanceService) {
+	jobs := []struct {
+		name, envKey, defaultSpec, description string
+		lockTTL                                time.Duration
+		task                                   scheduler.TaskFunc
+	}{
+		{"sales-summary", "SCHEDULE_SALES_SUMMARY", "5 0 * * *", "Build yesterday's sales summary", 30 * time.Minute,
+			func(ctx context.Context) (string, error) {
+				return maintenance.BuildDailySalesSummary(time.Now().AddDate(0, 0, -1))
+			}},
+		{"expire-stale-orders", "SCHEDULE_EXPIRE_ORDERS", "*/15 * * * *", "Cancel unpaid pending orders older than 24 hours", 10 * time.Minute,
+			func(ctx context.Context) (string, error) {
+				return maintenance.ExpireStaleOrders(ctx, 24*time.Hour)
+			}},
+		{"purge-old-records", "SCHEDULE_PURGE", "30 2 * * *", "Purge published outbox messages, delivered webhooks and job runs older than 30 days", time.Hour,
+			func(ctx context.Context) (string, error) {
+				return maintenance.PurgeOldRecords(30 * 24 * time.Hour)
+			}},
+		{"purge-export-files", "SCHEDULE_PURGE_EXPORTS", "15 * * * *", "Remove export files older than 24 hours", 10 * time.Minute,
+			func(ctx context.Context) (string, error) {
+				return maintenance.PurgeExportFiles()
+			}},
+		{"cache-warmup", "SCHEDULE_CACHE_WARMUP", "*/10 * * * *", "Reload cached category, product and order lists", 5 * time.Minute,
+			func(ctx context.Context) (string, error) {
+				return maintenance.WarmCache()
+			}},
+		{"apply-scheduled-prices", "SCHEDULE_APPLY_PRICES", "* * * * *", "Apply scheduled product prices that became effective", time.Minute,
+			func(ctx context.Context) (string, error) {
+				return maintenance.ApplyScheduledPrices(ctx)
+			}},
+	}
+
+	for _, job := range jobs {
+		spec := os.Getenv(job.envKey)
+		if spec == "" {
+			spec = job.defaultSpec
+		}
+		if err := s.Register(job.name, spec, job.description, job.lockTTL, job.task); err != nil {
+			log.Fatalf("failed to register scheduled job: %v", err)
+		}
 	}
 }
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+
+	"crud-clean-architecture/audit"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	RequestIDHeader = "X-Request-ID"
+	ActorHeader     = "X-Actor"
+
+	// AnonymousActor dipakai jika request tidak mengirim header X-Actor
+	AnonymousActor = "anonymous"
+)
+
+// RequestContext attaches the actor and request ID to the request context so
+// services can record them in the audit log. The request ID is taken from
+// X-Request-ID when present and echoed back in the response.
+func RequestContext() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > 64 {
+			requestID = newRequestID()
+		}
+		actor := c.GetHeader(ActorHeader)
+		if actor == "" || len(actor) > 191 {
+			actor = AnonymousActor
+		}
+
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(audit.WithMetadata(c.Request.Context(), audit.Metadata{
+			Actor:     actor,
+			RequestID: requestID,
+		}))
+		c.Next()
+	}
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
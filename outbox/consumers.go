@@ -0,0 +1,63 @@
+package outbox
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"fmt"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// CacheInvalidationConsumer deletes the cached lists affected by a message.
+type CacheInvalidationConsumer struct {
+	redis *redis.Client
+}
+
+func NewCacheInvalidationConsumer(redis *redis.Client) *CacheInvalidationConsumer {
+	return &CacheInvalidationConsumer{redis}
+}
+
+func (c *CacheInvalidationConsumer) Name() string {
+	return "cache"
+}
+
+func (c *CacheInvalidationConsumer) Consume(ctx context.Context, message *domain.OutboxMessage) error {
+	keys := repository.CacheKeysFor(message.AggregateType)
+	if len(keys) == 0 {
+		return nil
+	}
+	return c.redis.Del(ctx, keys...).Err()
+}
+
+// RedisStreamConsumer appends messages to a Redis Stream for other services.
+// Readers should de-duplicate on the outbox_id field.
+type RedisStreamConsumer struct {
+	redis  *redis.Client
+	stream string
+	maxLen int64
+}
+
+func NewRedisStreamConsumer(redis *redis.Client, stream string) *RedisStreamConsumer {
+	return &RedisStreamConsumer{redis: redis, stream: stream, maxLen: 100000}
+}
+
+func (c *RedisStreamConsumer) Name() string {
+	return "redis-stream"
+}
+
+func (c *RedisStreamConsumer) Consume(ctx context.Context, message *domain.OutboxMessage) error {
+	return c.redis.XAdd(ctx, &redis.XAddArgs{
+		Stream: c.stream,
+		MaxLen: c.maxLen,
+		Approx: true,
+		Values: map[string]interface{}{
+			"outbox_id":      message.ID,
+			"event_type":     string(message.EventType),
+			"aggregate_type": message.AggregateType,
+			"aggregate_id":   fmt.Sprint(message.AggregateID),
+			"payload":        message.Payload,
+			"created_at":     message.CreatedAt.Format("2006-01-02T15:04:05.000Z07:00"),
+		},
+	}).Err()
+}
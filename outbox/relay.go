@@ -0,0 +1,127 @@
+package outbox
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// Consumer receives every outbox message. Delivery is at-least-once: a message
+// is handed out again until all consumers accept it, so Consume must be idempotent.
+type Consumer interface {
+	Name() string
+	Consume(ctx context.Context, message *domain.OutboxMessage) error
+}
+
+// Relay polls the outbox table and publishes pending messages to the consumers.
+// Several instances may run at once; each batch is claimed for ClaimTimeout so
+// the consumers run without holding row locks. Failed messages are retried with
+// exponential backoff and marked dead after MaxAttempts.
+type Relay struct {
+	repo         repository.OutboxRepository
+	consumers    []Consumer
+	BatchSize    int
+	PollInterval time.Duration
+	ClaimTimeout time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+}
+
+func NewRelay(repo repository.OutboxRepository, consumers ...Consumer) *Relay {
+	return &Relay{
+		repo:         repo,
+		consumers:    consumers,
+		BatchSize:    100,
+		PollInterval: time.Second,
+		ClaimTimeout: 5 * time.Minute,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   10 * time.Minute,
+	}
+}
+
+// Start runs the relay until ctx is cancelled.
+func (r *Relay) Start(ctx context.Context) {
+	ticker := time.NewTicker(r.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// Kuras outbox selama masih ada batch penuh
+			for ctx.Err() == nil {
+				if r.processBatch(ctx) < r.BatchSize {
+					break
+				}
+			}
+		}
+	}
+}
+
+// processBatch claims one batch, publishes it and returns its size.
+func (r *Relay) processBatch(ctx context.Context) int {
+	messages, err := r.repo.ClaimPending(time.Now(), r.ClaimTimeout, r.BatchSize)
+	if err != nil {
+		log.Printf("outbox: failed to claim messages: %v", err)
+		return 0
+	}
+	for i := range messages {
+		if ctx.Err() != nil {
+			// Sisa batch diambil lagi setelah klaimnya kedaluwarsa
+			break
+		}
+		r.attempt(ctx, &messages[i])
+	}
+	return len(messages)
+}
+
+func (r *Relay) attempt(ctx context.Context, message *domain.OutboxMessage) {
+	err := r.publish(ctx, message)
+
+	now := time.Now()
+	message.NextAttemptAt = nil
+	if err == nil {
+		message.PublishedAt = &now
+		message.LastError = ""
+	} else {
+		message.Attempts++
+		message.LastError = err.Error()
+		if message.Attempts >= r.MaxAttempts {
+			// Berhenti mencoba; pesan tetap di tabel untuk diperiksa
+			message.DeadAt = &now
+			log.Printf("outbox: message %d (%s) is dead after %d attempts: %v", message.ID, message.EventType, message.Attempts, err)
+		} else {
+			next := now.Add(r.backoff(message.Attempts))
+			message.NextAttemptAt = &next
+		}
+	}
+
+	if err := r.repo.UpdateMessage(message); err != nil {
+		log.Printf("outbox: failed to update message %d: %v", message.ID, err)
+	}
+}
+
+func (r *Relay) publish(ctx context.Context, message *domain.OutboxMessage) error {
+	for _, consumer := range r.consumers {
+		if err := consumer.Consume(ctx, message); err != nil {
+			return fmt.Errorf("%s: %w", consumer.Name(), err)
+		}
+	}
+	return nil
+}
+
+// backoff returns BaseBackoff * 2^(attempts-1), capped at MaxBackoff.
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := float64(r.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if delay > float64(r.MaxBackoff) {
+		return r.MaxBackoff
+	}
+	return time.Duration(delay)
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"testing"
+	"time"
+)
+
+type fakeOutboxRepository struct {
+	pending []domain.OutboxMessage
+	updated []domain.OutboxMessage
+}
+
+func (f *fakeOutboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]domain.OutboxMessage, error) {
+	claimed := f.pending
+	f.pending = nil
+	return claimed, nil
+}
+
+func (f *fakeOutboxRepository) UpdateMessage(message *domain.OutboxMessage) error {
+	f.updated = append(f.updated, *message)
+	return nil
+}
+
+func (f *fakeOutboxRepository) PurgePublished(before time.Time) (int64, error) {
+	return 0, nil
+}
+
+type fakeConsumer struct {
+	fail map[uint]bool
+}
+
+func (c *fakeConsumer) Name() string {
+	return "fake"
+}
+
+func (c *fakeConsumer) Consume(ctx context.Context, message *domain.OutboxMessage) error {
+	if c.fail[message.ID] {
+		return errors.New("unavailable")
+	}
+	return nil
+}
+
+func TestRelayProcessBatch(t *testing.T) {
+	repo := &fakeOutboxRepository{pending: []domain.OutboxMessage{
+		{ID: 1},
+		{ID: 2},
+		{ID: 3, Attempts: 9},
+	}}
+	relay := NewRelay(repo, &fakeConsumer{fail: map[uint]bool{2: true, 3: true}})
+
+	if n := relay.processBatch(context.Background()); n != 3 {
+		t.Fatalf("expected 3 claimed messages, got %d", n)
+	}
+	if len(repo.updated) != 3 {
+		t.Fatalf("expected 3 updates, got %d", len(repo.updated))
+	}
+
+	published := repo.updated[0]
+	if published.PublishedAt == nil || published.NextAttemptAt != nil || published.Attempts != 0 {
+		t.Fatalf("expected message 1 published, got %+v", published)
+	}
+
+	retried := repo.updated[1]
+	if retried.PublishedAt != nil || retried.DeadAt != nil || retried.Attempts != 1 || retried.LastError != "fake: unavailable" {
+		t.Fatalf("expected message 2 scheduled for retry, got %+v", retried)
+	}
+	if retried.NextAttemptAt == nil || retried.NextAttemptAt.Before(time.Now()) {
+		t.Fatalf("expected message 2 retried in the future, got %v", retried.NextAttemptAt)
+	}
+
+	dead := repo.updated[2]
+	if dead.DeadAt == nil || dead.NextAttemptAt != nil || dead.Attempts != 10 {
+		t.Fatalf("expected message 3 dead, got %+v", dead)
+	}
+}
+
+func TestRelayBackoff(t *testing.T) {
+	relay := NewRelay(&fakeOutboxRepository{})
+	cases := map[int]time.Duration{
+		1:  time.Second,
+		2:  2 * time.Second,
+		4:  8 * time.Second,
+		20: 10 * time.Minute,
+	}
+	for attempts, want := range cases {
+		if got := relay.backoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %v, want %v", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package payment
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"fmt"
+	"time"
+)
+
+// BankTransferProvider issues manual transfer instructions. The payment stays
+// pending until it is confirmed by staff or by a provider callback.
+type BankTransferProvider struct {
+	bankName      string
+	accountNumber string
+	accountHolder string
+	expiry        time.Duration
+}
+
+func NewBankTransferProvider(bankName, accountNumber, accountHolder string) *BankTransferProvider {
+	return &BankTransferProvider{
+		bankName:      bankName,
+		accountNumber: accountNumber,
+		accountHolder: accountHolder,
+		expiry:        24 * time.Hour,
+	}
+}
+
+func (p *BankTransferProvider) Name() string {
+	return "bank_transfer"
+}
+
+func (p *BankTransferProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	expiresAt := time.Now().Add(p.expiry)
+	return &Intent{
+		Reference: fmt.Sprintf("TRF-%d-%d", req.OrderID, time.Now().UnixNano()),
+		Status:    domain.PaymentStatusPending,
+		Instructions: fmt.Sprintf("Transfer %.2f ke %s %s a.n. %s dengan berita %s",
+			req.Amount, p.bankName, p.accountNumber, p.accountHolder, req.InvoiceNumber),
+		ExpiresAt: &expiresAt,
+	}, nil
+}
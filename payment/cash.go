@@ -0,0 +1,26 @@
+package payment
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"fmt"
+	"time"
+)
+
+// CashProvider settles payments immediately; the cashier has already received the money.
+type CashProvider struct{}
+
+func NewCashProvider() *CashProvider {
+	return &CashProvider{}
+}
+
+func (p *CashProvider) Name() string {
+	return "cash"
+}
+
+func (p *CashProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	return &Intent{
+		Reference: fmt.Sprintf("CASH-%d-%d", req.OrderID, time.Now().UnixNano()),
+		Status:    domain.PaymentStatusPaid,
+	}, nil
+}
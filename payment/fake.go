@@ -0,0 +1,108 @@
+package payment
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// FakeWebhookPayload is the callback body understood by FakeProvider.
+type FakeWebhookPayload struct {
+	EventID           string  `json:"event_id"`
+	Reference         string  `json:"reference"`
+	TransactionStatus string  `json:"transaction_status"`
+	Amount            float64 `json:"amount"`
+}
+
+// FakeProvider is an in-process gateway for tests and local development. It
+// records every request and answers with a configurable status or error.
+type FakeProvider struct {
+	mu       sync.Mutex
+	name     string
+	secret   string
+	status   domain.PaymentStatus
+	err      error
+	seq      int
+	requests []IntentRequest
+}
+
+func NewFakeProvider(name string, status domain.PaymentStatus) *FakeProvider {
+	return &FakeProvider{name: name, status: status}
+}
+
+func (p *FakeProvider) Name() string {
+	return p.name
+}
+
+// SetOutcome changes the status (or error) returned by subsequent intents.
+func (p *FakeProvider) SetOutcome(status domain.PaymentStatus, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.status = status
+	p.err = err
+}
+
+// SetWebhookSecret sets the shared secret used to verify callbacks.
+func (p *FakeProvider) SetWebhookSecret(secret string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.secret = secret
+}
+
+// Requests returns a copy of the intents received so far.
+func (p *FakeProvider) Requests() []IntentRequest {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	requests := make([]IntentRequest, len(p.requests))
+	copy(requests, p.requests)
+	return requests
+}
+
+func (p *FakeProvider) CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.requests = append(p.requests, req)
+	if p.err != nil {
+		return nil, p.err
+	}
+	p.seq++
+	reference := fmt.Sprintf("%s-%d-%d", p.name, req.OrderID, p.seq)
+	return &Intent{
+		Reference:  reference,
+		Status:     p.status,
+		PaymentURL: "https://pay.example.test/" + reference,
+	}, nil
+}
+
+func (p *FakeProvider) ParseWebhook(body []byte, signature string) (*WebhookEvent, error) {
+	p.mu.Lock()
+	secret := p.secret
+	p.mu.Unlock()
+
+	if !VerifySignature(secret, body, signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var payload FakeWebhookPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidWebhook, err)
+	}
+	if payload.EventID == "" || payload.Reference == "" {
+		return nil, fmt.Errorf("%w: event_id and reference are required", ErrInvalidWebhook)
+	}
+
+	status, err := MapGatewayStatus(payload.TransactionStatus)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", err, payload.TransactionStatus)
+	}
+
+	return &WebhookEvent{
+		EventID:   payload.EventID,
+		Reference: payload.Reference,
+		RawStatus: payload.TransactionStatus,
+		Status:    status,
+		Amount:    payload.Amount,
+	}, nil
+}
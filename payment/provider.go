@@ -0,0 +1,77 @@
+package payment
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	ErrProviderNotFound = errors.New("payment provider not found")
+)
+
+// IntentRequest describes the payment the order service wants a provider to collect.
+type IntentRequest struct {
+	OrderID       uint
+	InvoiceNumber string
+	Method        domain.PaymentMethod
+	Amount        float64
+}
+
+// Intent is the provider's answer to an IntentRequest. Reference identifies the
+// payment on the provider side and is used later to match callbacks.
+type Intent struct {
+	Reference    string
+	Status       domain.PaymentStatus
+	Instructions string
+	PaymentURL   string
+	ExpiresAt    *time.Time
+}
+
+// Provider is implemented by every payment channel (cash drawer, bank transfer,
+// QRIS/e-wallet or card gateway).
+type Provider interface {
+	Name() string
+	CreateIntent(ctx context.Context, req IntentRequest) (*Intent, error)
+}
+
+// Registry maps payment methods to the provider that handles them.
+type Registry struct {
+	mu        sync.RWMutex
+	providers map[domain.PaymentMethod]Provider
+}
+
+func NewRegistry() *Registry {
+	return &Registry{providers: make(map[domain.PaymentMethod]Provider)}
+}
+
+func (r *Registry) Register(method domain.PaymentMethod, provider Provider) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[method] = provider
+}
+
+// ByName returns the registered provider with the given Name, whatever method it serves.
+func (r *Registry) ByName(name string) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for _, provider := range r.providers {
+		if provider.Name() == name {
+			return provider, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, name)
+}
+
+func (r *Registry) Get(method domain.PaymentMethod) (Provider, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	provider, ok := r.providers[method]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrProviderNotFound, method)
+	}
+	return provider, nil
+}
@@ -0,0 +1,62 @@
+package payment
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/utils"
+	"errors"
+	"strings"
+)
+
+const SignatureHeader = "X-Signature"
+
+var (
+	ErrInvalidSignature     = errors.New("invalid webhook signature")
+	ErrInvalidWebhook       = errors.New("invalid webhook payload")
+	ErrWebhookNotSupported  = errors.New("payment provider does not accept webhooks")
+	ErrUnknownGatewayStatus = errors.New("unknown gateway status")
+)
+
+// WebhookEvent is a provider callback translated to our payment states.
+type WebhookEvent struct {
+	EventID   string
+	Reference string
+	RawStatus string
+	Status    domain.PaymentStatus
+	Amount    float64
+}
+
+// WebhookProvider is implemented by providers that notify us about payment
+// status changes. ParseWebhook must verify the signature before trusting body.
+type WebhookProvider interface {
+	Provider
+	ParseWebhook(body []byte, signature string) (*WebhookEvent, error)
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of body, prefixed with "sha256=".
+func Sign(secret string, body []byte) string {
+	return utils.SignHMAC(secret, body)
+}
+
+// VerifySignature compares signature against the expected HMAC in constant time.
+func VerifySignature(secret string, body []byte, signature string) bool {
+	return utils.VerifyHMAC(secret, body, signature)
+}
+
+// MapGatewayStatus translates the transaction statuses used by Indonesian
+// gateways (settlement, capture, deny, expire, ...) to a PaymentStatus.
+func MapGatewayStatus(raw string) (domain.PaymentStatus, error) {
+	switch strings.ToLower(raw) {
+	case "pending", "authorize":
+		return domain.PaymentStatusPending, nil
+	case "settlement", "capture", "success", "paid":
+		return domain.PaymentStatusPaid, nil
+	case "deny", "failure", "failed":
+		return domain.PaymentStatusFailed, nil
+	case "expire", "expired":
+		return domain.PaymentStatusExpired, nil
+	case "cancel", "cancelled":
+		return domain.PaymentStatusCancelled, nil
+	default:
+		return "", ErrUnknownGatewayStatus
+	}
+}
@@ -0,0 +1,340 @@
+package queue
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	readyKey    = "queue:ready"
+	delayedKey  = "queue:delayed"
+	inflightKey = "queue:inflight"
+	deadKey     = "queue:dead"
+	jobKeyPref  = "queue:job:"
+	uniqueKey   = "queue:unique:"
+)
+
+var (
+	ErrJobNotFound  = errors.New("job not found")
+	ErrDuplicateJob = errors.New("job with the same unique key was already enqueued")
+)
+
+// Job is a unit of background work. Payload is decoded by the handler registered for Type.
+type Job struct {
+	ID          string          `json:"id"`
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"`
+	Attempts    int             `json:"attempts"`
+	MaxAttempts int             `json:"max_attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	EnqueuedAt  time.Time       `json:"enqueued_at"`
+	RunAt       time.Time       `json:"run_at"`
+	FailedAt    *time.Time      `json:"failed_at,omitempty"`
+}
+
+// Stats is a snapshot of the queue sizes.
+type Stats struct {
+	Ready    int64 `json:"ready"`
+	Delayed  int64 `json:"delayed"`
+	InFlight int64 `json:"in_flight"`
+	Dead     int64 `json:"dead"`
+}
+
+// Option customises a job at enqueue time.
+type Option func(job *Job, opts *enqueueOptions)
+
+type enqueueOptions struct {
+	uniqueKey string
+	uniqueTTL time.Duration
+}
+
+// WithDelay schedules the job to run after d.
+func WithDelay(d time.Duration) Option {
+	return func(job *Job, _ *enqueueOptions) {
+		job.RunAt = job.EnqueuedAt.Add(d)
+	}
+}
+
+// WithMaxAttempts overrides the default number of attempts before the job is dead.
+func WithMaxAttempts(n int) Option {
+	return func(job *Job, _ *enqueueOptions) {
+		job.MaxAttempts = n
+	}
+}
+
+// WithUniqueKey drops the job with ErrDuplicateJob if another job with the same
+// key was enqueued during the last ttl. Useful for at-least-once producers.
+func WithUniqueKey(key string, ttl time.Duration) Option {
+	return func(_ *Job, opts *enqueueOptions) {
+		opts.uniqueKey = key
+		opts.uniqueTTL = ttl
+	}
+}
+
+// Queue stores jobs in Redis: ready jobs in a list, delayed and in-flight jobs
+// in sorted sets scored by due time, and failed jobs in a dead-letter set.
+type Queue struct {
+	redis *redis.Client
+}
+
+func New(redis *redis.Client) *Queue {
+	return &Queue{redis}
+}
+
+// Enqueue adds a job of the given type; payload is encoded as JSON.
+func (q *Queue) Enqueue(ctx context.Context, jobType string, payload interface{}, opts ...Option) (*Job, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	job := &Job{
+		ID:          newJobID(),
+		Type:        jobType,
+		Payload:     data,
+		MaxAttempts: 5,
+		EnqueuedAt:  now,
+		RunAt:       now,
+	}
+	var options enqueueOptions
+	for _, opt := range opts {
+		opt(job, &options)
+	}
+
+	if options.uniqueKey != "" {
+		ok, err := q.redis.SetNX(ctx, uniqueKey+options.uniqueKey, job.ID, options.uniqueTTL).Result()
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			return nil, ErrDuplicateJob
+		}
+	}
+
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+
+	pipe := q.redis.TxPipeline()
+	pipe.Set(ctx, jobKeyPref+job.ID, encoded, 0)
+	if job.RunAt.After(now) {
+		pipe.ZAdd(ctx, delayedKey, redis.Z{Score: score(job.RunAt), Member: job.ID})
+	} else {
+		pipe.LPush(ctx, readyKey, job.ID)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		if options.uniqueKey != "" {
+			_ = q.redis.Del(ctx, uniqueKey+options.uniqueKey).Err()
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+// dequeueScript memindahkan job dari ready ke inflight secara atomik
+var dequeueScript = redis.NewScript(`
+local id = redis.call('RPOP', KEYS[1])
+if not id then return false end
+redis.call('ZADD', KEYS[2], ARGV[1], id)
+return id
+`)
+
+// promoteScript memindahkan job yang sudah jatuh tempo dari sorted set ke ready
+var promoteScript = redis.NewScript(`
+local ids = redis.call('ZRANGEBYSCORE', KEYS[1], '-inf', ARGV[1], 'LIMIT', 0, 100)
+for _, id in ipairs(ids) do
+  redis.call('ZREM', KEYS[1], id)
+  redis.call('LPUSH', KEYS[2], id)
+end
+return #ids
+`)
+
+// dequeue reserves the next ready job until now+visibility. A job that is not
+// acked or failed before then becomes visible to other workers again.
+func (q *Queue) dequeue(ctx context.Context, visibility time.Duration) (*Job, error) {
+	id, err := dequeueScript.Run(ctx, q.redis, []string{readyKey, inflightKey},
+		score(time.Now().Add(visibility))).Text()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := q.get(ctx, id)
+	if errors.Is(err, ErrJobNotFound) {
+		// Data job sudah hilang; buang id dari inflight
+		_ = q.redis.ZRem(ctx, inflightKey, id).Err()
+		return nil, nil
+	}
+	return job, err
+}
+
+// promote moves due delayed jobs and expired in-flight jobs back to ready.
+func (q *Queue) promote(ctx context.Context) error {
+	now := score(time.Now())
+	if err := promoteScript.Run(ctx, q.redis, []string{delayedKey, readyKey}, now).Err(); err != nil {
+		return err
+	}
+	return promoteScript.Run(ctx, q.redis, []string{inflightKey, readyKey}, now).Err()
+}
+
+func (q *Queue) ack(ctx context.Context, job *Job) error {
+	pipe := q.redis.TxPipeline()
+	pipe.ZRem(ctx, inflightKey, job.ID)
+	pipe.Del(ctx, jobKeyPref+job.ID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// fail schedules a retry at retryAt, or moves the job to the dead-letter set
+// once it has used all its attempts.
+func (q *Queue) fail(ctx context.Context, job *Job, cause error, retryAt time.Time) error {
+	job.LastError = cause.Error()
+	dead := job.Attempts >= job.MaxAttempts
+	if dead {
+		now := time.Now()
+		job.FailedAt = &now
+	} else {
+		job.RunAt = retryAt
+	}
+
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	pipe := q.redis.TxPipeline()
+	pipe.Set(ctx, jobKeyPref+job.ID, encoded, 0)
+	pipe.ZRem(ctx, inflightKey, job.ID)
+	if dead {
+		pipe.ZAdd(ctx, deadKey, redis.Z{Score: score(*job.FailedAt), Member: job.ID})
+	} else {
+		pipe.ZAdd(ctx, delayedKey, redis.Z{Score: score(retryAt), Member: job.ID})
+	}
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// save persists job changes (e.g. the attempt counter) without moving it.
+func (q *Queue) save(ctx context.Context, job *Job) error {
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return q.redis.Set(ctx, jobKeyPref+job.ID, encoded, 0).Err()
+}
+
+func (q *Queue) get(ctx context.Context, id string) (*Job, error) {
+	data, err := q.redis.Get(ctx, jobKeyPref+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrJobNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(data, &job); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (q *Queue) Stats(ctx context.Context) (*Stats, error) {
+	pipe := q.redis.Pipeline()
+	ready := pipe.LLen(ctx, readyKey)
+	delayed := pipe.ZCard(ctx, delayedKey)
+	inflight := pipe.ZCard(ctx, inflightKey)
+	dead := pipe.ZCard(ctx, deadKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return &Stats{
+		Ready:    ready.Val(),
+		Delayed:  delayed.Val(),
+		InFlight: inflight.Val(),
+		Dead:     dead.Val(),
+	}, nil
+}
+
+// DeadJobs returns up to limit dead jobs, most recently failed first.
+func (q *Queue) DeadJobs(ctx context.Context, limit int64) ([]Job, error) {
+	ids, err := q.redis.ZRevRange(ctx, deadKey, 0, limit-1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := q.get(ctx, id)
+		if errors.Is(err, ErrJobNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, nil
+}
+
+// RetryDead moves a dead job back to the ready list with a fresh attempt budget.
+func (q *Queue) RetryDead(ctx context.Context, id string) (*Job, error) {
+	removed, err := q.redis.ZRem(ctx, deadKey, id).Result()
+	if err != nil {
+		return nil, err
+	}
+	if removed == 0 {
+		return nil, ErrJobNotFound
+	}
+
+	job, err := q.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	job.Attempts = 0
+	job.FailedAt = nil
+	job.RunAt = time.Now()
+
+	encoded, err := json.Marshal(job)
+	if err != nil {
+		return nil, err
+	}
+	pipe := q.redis.TxPipeline()
+	pipe.Set(ctx, jobKeyPref+job.ID, encoded, 0)
+	pipe.LPush(ctx, readyKey, job.ID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// DeleteDead drops a dead job permanently.
+func (q *Queue) DeleteDead(ctx context.Context, id string) error {
+	removed, err := q.redis.ZRem(ctx, deadKey, id).Result()
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return ErrJobNotFound
+	}
+	return q.redis.Del(ctx, jobKeyPref+id).Err()
+}
+
+func score(t time.Time) float64 {
+	return float64(t.UnixMilli())
+}
+
+func newJobID() string {
+	b := make([]byte, 12)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
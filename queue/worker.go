@@ -0,0 +1,143 @@
+package queue
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sync"
+	"time"
+)
+
+// HandlerFunc processes one job. Returning an error schedules a retry.
+type HandlerFunc func(ctx context.Context, job *Job) error
+
+// Worker pulls jobs from the queue and runs the handler registered for their type.
+type Worker struct {
+	queue             *Queue
+	handlers          map[string]HandlerFunc
+	Concurrency       int
+	VisibilityTimeout time.Duration
+	PollInterval      time.Duration
+	BaseBackoff       time.Duration
+	MaxBackoff        time.Duration
+}
+
+func NewWorker(queue *Queue) *Worker {
+	return &Worker{
+		queue:             queue,
+		handlers:          make(map[string]HandlerFunc),
+		Concurrency:       4,
+		VisibilityTimeout: 5 * time.Minute,
+		PollInterval:      time.Second,
+		BaseBackoff:       5 * time.Second,
+		MaxBackoff:        30 * time.Minute,
+	}
+}
+
+func (w *Worker) Register(jobType string, handler HandlerFunc) {
+	w.handlers[jobType] = handler
+}
+
+// Start runs the scheduler and Concurrency processing loops until ctx is cancelled.
+func (w *Worker) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		w.schedule(ctx)
+	}()
+
+	for i := 0; i < w.Concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.process(ctx)
+		}()
+	}
+
+	wg.Wait()
+}
+
+// schedule memindahkan job delayed dan job inflight yang kedaluwarsa ke ready
+func (w *Worker) schedule(ctx context.Context) {
+	ticker := time.NewTicker(w.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := w.queue.promote(ctx); err != nil && ctx.Err() == nil {
+				log.Printf("queue: failed to promote jobs: %v", err)
+			}
+		}
+	}
+}
+
+func (w *Worker) process(ctx context.Context) {
+	for ctx.Err() == nil {
+		job, err := w.queue.dequeue(ctx, w.VisibilityTimeout)
+		if err != nil && ctx.Err() == nil {
+			log.Printf("queue: failed to dequeue: %v", err)
+		}
+		if job == nil {
+			// Antrean kosong; tunggu sebelum mencoba lagi
+			select {
+			case <-ctx.Done():
+			case <-time.After(w.PollInterval):
+			}
+			continue
+		}
+		w.run(ctx, job)
+	}
+}
+
+func (w *Worker) run(ctx context.Context, job *Job) {
+	job.Attempts++
+	if err := w.queue.save(ctx, job); err != nil {
+		log.Printf("queue: failed to save job %s: %v", job.ID, err)
+	}
+
+	err := w.handle(ctx, job)
+	if err == nil {
+		if err := w.queue.ack(ctx, job); err != nil {
+			log.Printf("queue: failed to ack job %s: %v", job.ID, err)
+		}
+		return
+	}
+
+	log.Printf("queue: job %s (%s) attempt %d/%d failed: %v", job.ID, job.Type, job.Attempts, job.MaxAttempts, err)
+	if err := w.queue.fail(ctx, job, err, time.Now().Add(w.backoff(job.Attempts))); err != nil {
+		log.Printf("queue: failed to record failure of job %s: %v", job.ID, err)
+	}
+}
+
+func (w *Worker) handle(ctx context.Context, job *Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return fmt.Errorf("no handler registered for job type %q", job.Type)
+	}
+
+	// Batasi durasi handler agar selesai sebelum visibility timeout habis
+	ctx, cancel := context.WithTimeout(ctx, w.VisibilityTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return handler(ctx, job)
+}
+
+// backoff returns BaseBackoff * 2^(attempts-1), capped at MaxBackoff.
+func (w *Worker) backoff(attempts int) time.Duration {
+	delay := float64(w.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if delay > float64(w.MaxBackoff) {
+		return w.MaxBackoff
+	}
+	return time.Duration(delay)
+}
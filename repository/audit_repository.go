@@ -0,0 +1,141 @@
+package repository
+
+import (
+	"crud-clean-architecture/audit"
+	"crud-clean-architecture/domain"
+	"encoding/json"
+	"reflect"
+
+	"gorm.io/gorm"
+)
+
+// Field yang selalu berubah dan tidak informatif di diff
+var auditIgnoredFields = map[string]bool{"updated_at": true}
+
+type AuditRepository interface {
+	GetLogs(filter domain.AuditFilter) ([]domain.AuditLog, error)
+}
+
+type auditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) AuditRepository {
+	return &auditRepository{db}
+}
+
+// GetLogs returns the newest matching entries first.
+func (r *auditRepository) GetLogs(filter domain.AuditFilter) ([]domain.AuditLog, error) {
+	query := r.db.Model(&domain.AuditLog{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.Actor != "" {
+		query = query.Where("actor = ?", filter.Actor)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+	if filter.BeforeID != 0 {
+		query = query.Where("id < ?", filter.BeforeID)
+	}
+
+	var logs []domain.AuditLog
+	err := query.Order("id DESC").Limit(filter.Limit).Find(&logs).Error
+	return logs, err
+}
+
+// writeAudit records who changed the entity and how in the same transaction
+// as the change, so the audit log cannot miss a committed change. before is
+// nil for creates and after is nil for deletes; both should be read inside tx
+// after the row is locked. The actor and request ID come from the context of
+// tx. Updates that change nothing are not recorded.
+func writeAudit(tx *gorm.DB, entityType string, entityID uint, action domain.AuditAction, before, after interface{}) error {
+	changes, err := diffSnapshots(before, after)
+	if err != nil {
+		return err
+	}
+	if action == domain.AuditActionUpdate && len(changes) == 0 {
+		return nil
+	}
+
+	metadata := audit.FromContext(tx.Statement.Context)
+	return tx.Create(&domain.AuditLog{
+		Actor:      metadata.Actor,
+		RequestID:  metadata.RequestID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Action:     action,
+		Changes:    changes,
+	}).Error
+}
+
+// diffSnapshots compares the JSON form of before and after. Objects such as
+// attributes, translations and availability are compared key by key and
+// reported as "field.key"; lists such as tags and variants are compared whole.
+func diffSnapshots(before, after interface{}) (map[string]domain.AuditChange, error) {
+	beforeFields, err := snapshotFields(before)
+	if err != nil {
+		return nil, err
+	}
+	afterFields, err := snapshotFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := map[string]domain.AuditChange{}
+	diffFields("", beforeFields, afterFields, changes)
+	return changes, nil
+}
+
+func diffFields(prefix string, before, after map[string]interface{}, changes map[string]domain.AuditChange) {
+	add := func(field string) {
+		if auditIgnoredFields[field] {
+			return
+		}
+		oldValue, newValue := before[field], after[field]
+		oldObject, oldIsObject := oldValue.(map[string]interface{})
+		newObject, newIsObject := newValue.(map[string]interface{})
+		// Objek (atau objek yang baru dibuat/dihapus) dibandingkan per key
+		if (oldIsObject || oldValue == nil) && (newIsObject || newValue == nil) && (oldIsObject || newIsObject) {
+			diffFields(prefix+field+".", oldObject, newObject, changes)
+			return
+		}
+		if !reflect.DeepEqual(oldValue, newValue) {
+			changes[prefix+field] = domain.AuditChange{Before: oldValue, After: newValue}
+		}
+	}
+	for field := range before {
+		add(field)
+	}
+	for field := range after {
+		if _, seen := before[field]; !seen {
+			add(field)
+		}
+	}
+}
+
+func snapshotFields(entity interface{}) (map[string]interface{}, error) {
+	fields := map[string]interface{}{}
+	if entity == nil || (reflect.ValueOf(entity).Kind() == reflect.Ptr && reflect.ValueOf(entity).IsNil()) {
+		return fields, nil
+	}
+	data, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(data, &fields)
+	return fields, err
+}
@@ -0,0 +1,86 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffSnapshotsNestedFields(t *testing.T) {
+	before := &domain.Product{
+		ID:         1,
+		Name:       "Kopi",
+		Price:      20000,
+		Attributes: map[string]interface{}{"size": "M", "hot": true},
+		Translations: map[string]domain.Translation{
+			"en": {Name: "Coffee"},
+		},
+		Tags: []domain.Tag{{ID: 1, Name: "drink"}},
+	}
+	after := &domain.Product{
+		ID:         1,
+		Name:       "Kopi",
+		Price:      20000,
+		Attributes: map[string]interface{}{"size": "L", "hot": true},
+		Translations: map[string]domain.Translation{
+			"en": {Name: "Coffee"},
+			"ja": {Name: "Kohi"},
+		},
+		Tags: []domain.Tag{{ID: 1, Name: "drink"}, {ID: 2, Name: "hot"}},
+	}
+
+	changes, err := diffSnapshots(before, after)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var fields []string
+	for field := range changes {
+		fields = append(fields, field)
+	}
+	want := map[string]bool{"attributes.size": true, "translations.ja.name": true, "tags": true}
+	if len(changes) != len(want) {
+		t.Fatalf("expected changes %v, got %v", want, fields)
+	}
+	for field := range want {
+		if _, ok := changes[field]; !ok {
+			t.Fatalf("expected change of %s, got %v", field, fields)
+		}
+	}
+	if change := changes["attributes.size"]; change.Before != "M" || change.After != "L" {
+		t.Fatalf("unexpected attributes.size change %+v", change)
+	}
+	if change := changes["translations.ja.name"]; change.Before != nil || change.After != "Kohi" {
+		t.Fatalf("unexpected translations.ja.name change %+v", change)
+	}
+}
+
+func TestDiffSnapshotsCreateAndDelete(t *testing.T) {
+	tag := &domain.Tag{ID: 3, Name: "promo"}
+
+	created, err := diffSnapshots(nil, tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change := created["name"]; change.Before != nil || change.After != "promo" {
+		t.Fatalf("unexpected create diff %+v", created)
+	}
+
+	var none *domain.Tag
+	deleted, err := diffSnapshots(tag, none)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if change := deleted["name"]; change.Before != "promo" || change.After != nil {
+		t.Fatalf("unexpected delete diff %+v", deleted)
+	}
+
+	// updated_at selalu berubah dan tidak dihitung sebagai perubahan
+	unchanged, err := diffSnapshots(tag, &domain.Tag{ID: 3, Name: "promo", UpdatedAt: time.Now()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(unchanged, map[string]domain.AuditChange{}) {
+		t.Fatalf("expected no changes, got %+v", unchanged)
+	}
+}
@@ -0,0 +1,15 @@
+package repository
+
+// BatchItemError identifies the operation that made a batch roll back.
+type BatchItemError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchItemError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *BatchItemError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,34 @@
+package repository
+
+import "crud-clean-architecture/domain"
+
+// CacheKeysFor returns the cached list keys that become stale when an
+// aggregate of the given type changes. Products embed their category and
+// orders embed their products, so changes cascade to the dependent lists.
+func CacheKeysFor(aggregateType string) []string {
+	switch aggregateType {
+	case domain.AggregateCategory:
+		keys := append(localizedKeys(categoryCacheKey), localizedKeys(productCacheKey)...)
+		return append(keys, productFacetsCacheKey, orderCacheKey)
+	case domain.AggregateProduct:
+		return append(localizedKeys(productCacheKey), productFacetsCacheKey, orderCacheKey)
+	case domain.AggregateOrder:
+		return []string{orderCacheKey}
+	default:
+		return nil
+	}
+}
+
+// localizedKey adalah key cache daftar untuk satu locale
+func localizedKey(key, locale string) string {
+	return key + ":" + locale
+}
+
+// localizedKeys mengembalikan key cache daftar untuk semua locale
+func localizedKeys(key string) []string {
+	keys := make([]string, len(domain.SupportedLocales))
+	for i, locale := range domain.SupportedLocales {
+		keys[i] = localizedKey(key, locale)
+	}
+	return keys
+}
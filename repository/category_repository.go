@@ -9,19 +9,28 @@ import (
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 var (
 	ErrCategoryNameExists = errors.New("category name already exists")
+	ErrCategoryNotFound   = errors.New("category not found")
 )
 
 type CategoryRepository interface {
-	CreateCategory(category *domain.Category) error
-	GetAllCategories() ([]domain.Category, error)
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	GetAllCategories(locale string) ([]domain.Category, error)
 	GetCategoryByID(id uint) (*domain.Category, error)
-	UpdateCategory(category *domain.Category) error
-	DeleteCategory(id uint) error
+	UpdateCategory(ctx context.Context, category *domain.Category) error
+	DeleteCategory(ctx context.Context, id uint, version uint) error
+	PatchCategory(ctx context.Context, id uint, version uint, changes map[string]interface{}) (*domain.Category, error)
 	IsCategoryNameUnique(name string) (bool, error)
+	GetCategoryByName(name string) (*domain.Category, error)
+	ApplyBatch(ctx context.Context, ops []domain.CategoryBatchOperation) ([]domain.Category, error)
+	SetAttributeSchema(ctx context.Context, id uint, version uint, schema []domain.AttributeDefinition) (*domain.Category, error)
+	SetTranslation(ctx context.Context, id uint, version uint, locale string, translation *domain.Translation) (*domain.Category, error)
+	FindSlug(slug string) (*domain.Slug, error)
+	BackfillSlugs() (int, error)
 }
 
 type categoryRepository struct {
@@ -35,14 +44,39 @@ func NewCategoryRepository(db *gorm.DB, redis *redis.Client) CategoryRepository
 
 const categoryCacheKey = "categories:all"
 
-func (r *categoryRepository) CreateCategory(category *domain.Category) error {
-	ctx := context.Background()
-
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, categoryCacheKey).Err(); err != nil {
+func (r *categoryRepository) CreateCategory(ctx context.Context, category *domain.Category) error {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	category.Version = 1
+	if err := tx.Create(category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	slug, err := assignSlug(tx, domain.AggregateCategory, category.ID, category.Name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	category.Slug = slug
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryCreated, category); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionCreate, nil, category); err != nil {
+		tx.Rollback()
 		return err
 	}
-	return r.db.Create(category).Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah create
+	r.invalidateCache()
+	return nil
 }
 
 func (r *categoryRepository) IsCategoryNameUnique(name string) (bool, error) {
@@ -50,11 +84,14 @@ func (r *categoryRepository) IsCategoryNameUnique(name string) (bool, error) {
 	err := r.db.Model(&domain.Category{}).Where("name = ?", name).Count(&count).Error
 	return count == 0, err
 }
-func (r *categoryRepository) GetAllCategories() ([]domain.Category, error) {
+
+// GetAllCategories returns every category localized for locale, cached per locale.
+func (r *categoryRepository) GetAllCategories(locale string) ([]domain.Category, error) {
 	ctx := context.Background()
+	cacheKey := localizedKey(categoryCacheKey, locale)
 
 	// Cek cache
-	cachedData, err := r.redis.Get(ctx, categoryCacheKey).Result()
+	cachedData, err := r.redis.Get(ctx, cacheKey).Result()
 	if err == nil {
 		var categories []domain.Category
 		if err := json.Unmarshal([]byte(cachedData), &categories); err == nil {
@@ -67,10 +104,13 @@ func (r *categoryRepository) GetAllCategories() ([]domain.Category, error) {
 	if err := r.db.Find(&categories).Error; err != nil {
 		return nil, err
 	}
+	for i := range categories {
+		categories[i].Localize(locale)
+	}
 
 	// Simpan ke cache
 	data, _ := json.Marshal(categories)
-	_ = r.redis.Set(ctx, categoryCacheKey, data, 10*time.Minute).Err()
+	_ = r.redis.Set(ctx, cacheKey, data, 10*time.Minute).Err()
 
 	return categories, nil
 }
@@ -84,32 +124,389 @@ func (r *categoryRepository) GetCategoryByID(id uint) (*domain.Category, error)
 	return &category, nil
 }
 
-func (r *categoryRepository) UpdateCategory(category *domain.Category) error {
-	ctx := context.Background()
-
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, categoryCacheKey).Err(); err != nil {
+// UpdateCategory saves category when its Version still matches the stored one
+// (any version when zero) and increments the version.
+func (r *categoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var current domain.Category
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, category.ID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound
+		}
+		return err
+	}
+	if err := checkVersion(current.Version, category.Version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	category.Version = current.Version + 1
+	// Skema atribut dan terjemahan hanya berubah lewat endpoint masing-masing
+	category.AttributeSchema = current.AttributeSchema
+	category.Translations = current.Translations
+	if err := tx.Save(category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	// Slug lama tetap disimpan sebagai redirect
+	slug, err := assignSlug(tx, domain.AggregateCategory, category.ID, category.Name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	category.Slug = slug
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryUpdated, category); err != nil {
+		tx.Rollback()
 		return err
 	}
-	return r.db.Save(category).Error
+	if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionUpdate, &current, category); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah update
+	r.invalidateCache()
+	return nil
+}
+
+// PatchCategory updates only the changed columns when the version matches
+// (any version when zero). The version is bumped only if something changed.
+func (r *categoryRepository) PatchCategory(ctx context.Context, id uint, version uint, changes map[string]interface{}) (*domain.Category, error) {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var category domain.Category
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	if err := checkVersion(category.Version, version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(changes) == 0 {
+		tx.Rollback()
+		return &category, nil
+	}
+
+	before := category
+	changes["version"] = category.Version + 1
+	if err := tx.Model(&category).Updates(changes).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if name, ok := changes["name"].(string); ok {
+		if _, err := assignSlug(tx, domain.AggregateCategory, id, name); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.First(&category, id).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryUpdated, category); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionUpdate, &before, &category); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache setelah update
+	r.invalidateCache()
+	return &category, nil
 }
 
-func (r *categoryRepository) DeleteCategory(id uint) error {
+// DeleteCategory deletes the category when its version matches (any version when zero).
+func (r *categoryRepository) DeleteCategory(ctx context.Context, id uint, version uint) error {
 	var category domain.Category
 
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
 	// Periksa apakah data dengan ID ada
-	if err := r.db.First(&category, id).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("category not found")
+			return ErrCategoryNotFound
 		}
 		return err
 	}
-	ctx := context.Background()
-
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, categoryCacheKey).Err(); err != nil {
+	if err := checkVersion(category.Version, version); err != nil {
+		tx.Rollback()
 		return err
 	}
 	// Hapus data jika ditemukan
-	return r.db.Delete(&category).Error
+	if err := tx.Delete(&category).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := deleteSlugs(tx, domain.AggregateCategory, category.ID); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryDeleted, category); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionDelete, &category, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah delete
+	r.invalidateCache()
+	return nil
+}
+
+// invalidateCache menghapus cache segera setelah commit. Jika gagal, consumer
+// cache di relay outbox akan menghapusnya kembali.
+func (r *categoryRepository) invalidateCache() {
+	_ = r.redis.Del(context.Background(), CacheKeysFor(domain.AggregateCategory)...).Err()
+}
+
+// GetCategoryByName returns nil without error when no category has the name.
+func (r *categoryRepository) GetCategoryByName(name string) (*domain.Category, error) {
+	var category domain.Category
+	err := r.db.Where("name = ?", name).First(&category).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+// ApplyBatch runs the operations in one transaction and returns the resulting
+// category of each one. The cache is invalidated once after commit. A failing
+// operation rolls back the batch and is reported as *BatchItemError.
+func (r *categoryRepository) ApplyBatch(ctx context.Context, ops []domain.CategoryBatchOperation) ([]domain.Category, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	categories := make([]domain.Category, len(ops))
+	for i, op := range ops {
+		category, eventType, err := applyCategoryOperation(tx, op)
+		if err == nil {
+			err = writeOutbox(tx, domain.AggregateCategory, category.ID, eventType, category)
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, &BatchItemError{Index: i, Err: err}
+		}
+		categories[i] = category
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Satu kali hapus cache untuk seluruh batch
+	r.invalidateCache()
+	return categories, nil
+}
+
+// applyCategoryOperation menjalankan satu operasi batch dan mencatatnya di audit log
+func applyCategoryOperation(tx *gorm.DB, op domain.CategoryBatchOperation) (domain.Category, domain.EventType, error) {
+	var category domain.Category
+	if op.Op != domain.BatchOpCreate {
+		// Periksa apakah data dengan ID ada
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, op.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return category, "", ErrCategoryNotFound
+			}
+			return category, "", err
+		}
+		if err := checkVersion(category.Version, op.Version); err != nil {
+			return category, "", err
+		}
+	}
+
+	switch op.Op {
+	case domain.BatchOpCreate:
+		category = domain.Category{Name: op.Data.Name, Description: op.Data.Description, Version: 1}
+		if err := tx.Create(&category).Error; err != nil {
+			return category, "", err
+		}
+		slug, err := assignSlug(tx, domain.AggregateCategory, category.ID, category.Name)
+		if err != nil {
+			return category, "", err
+		}
+		category.Slug = slug
+		return category, domain.EventCategoryCreated, writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionCreate, nil, category)
+	case domain.BatchOpUpdate:
+		before := category
+		category.Name = op.Data.Name
+		category.Description = op.Data.Description
+		category.Version++
+		if err := tx.Save(&category).Error; err != nil {
+			return category, "", err
+		}
+		slug, err := assignSlug(tx, domain.AggregateCategory, category.ID, category.Name)
+		if err != nil {
+			return category, "", err
+		}
+		category.Slug = slug
+		return category, domain.EventCategoryUpdated, writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionUpdate, before, category)
+	default:
+		if err := deleteSlugs(tx, domain.AggregateCategory, category.ID); err != nil {
+			return category, "", err
+		}
+		if err := tx.Delete(&category).Error; err != nil {
+			return category, "", err
+		}
+		return category, domain.EventCategoryDeleted, writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionDelete, category, nil)
+	}
+}
+
+// SetAttributeSchema replaces the attribute schema of a category when the
+// version matches (any version when zero). Existing product values are not
+// rewritten; they are validated against the new schema on their next write.
+func (r *categoryRepository) SetAttributeSchema(ctx context.Context, id uint, version uint, schema []domain.AttributeDefinition) (*domain.Category, error) {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var category domain.Category
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	if err := checkVersion(category.Version, version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	before := category
+	category.AttributeSchema = schema
+	category.Version++
+	if err := tx.Select("attribute_schema", "version").Updates(&category).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryUpdated, category); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionUpdate, &before, &category); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache setelah update; produk ikut menampilkan kategorinya
+	r.invalidateCache()
+	return &category, nil
+}
+
+// SetTranslation sets or, when translation is nil, removes the translation
+// of a category for locale when the version matches (any version when zero).
+func (r *categoryRepository) SetTranslation(ctx context.Context, id uint, version uint, locale string, translation *domain.Translation) (*domain.Category, error) {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var category domain.Category
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound
+		}
+		return nil, err
+	}
+	if err := checkVersion(category.Version, version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	translations, err := withTranslation(category.Translations, locale, translation)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	before := category
+	category.Translations = translations
+	category.Version++
+	if err := tx.Select("translations", "version").Updates(&category).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryUpdated, category); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionUpdate, &before, &category); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache semua locale; produk ikut menampilkan kategorinya
+	r.invalidateCache()
+	return &category, nil
+}
+
+// FindSlug returns the current or old slug row of a category, or
+// ErrCategoryNotFound when no category ever had the slug.
+func (r *categoryRepository) FindSlug(slug string) (*domain.Slug, error) {
+	found, err := findSlug(r.db, domain.AggregateCategory, slug)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrCategoryNotFound
+	}
+	return found, nil
+}
+
+// BackfillSlugs gives a slug to categories created before slugs existed.
+func (r *categoryRepository) BackfillSlugs() (int, error) {
+	count, err := backfillSlugs(r.db, domain.AggregateCategory)
+	if count > 0 {
+		r.invalidateCache()
+	}
+	return count, err
 }
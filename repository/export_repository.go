@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+var ErrExportNotFound = errors.New("export not found")
+
+const exportKeyPrefix = "export:"
+
+// ExportTTL is how long a background export and its file are kept. The status
+// expires from Redis by itself; the file is removed by a scheduled job.
+const ExportTTL = 24 * time.Hour
+
+type ExportRepository interface {
+	SaveExport(export *domain.Export) error
+	GetExport(id string) (*domain.Export, error)
+}
+
+type exportRepository struct {
+	redis *redis.Client
+}
+
+func NewExportRepository(redis *redis.Client) ExportRepository {
+	return &exportRepository{redis}
+}
+
+func (r *exportRepository) SaveExport(export *domain.Export) error {
+	data, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+	return r.redis.Set(context.Background(), exportKeyPrefix+export.ID, data, ExportTTL).Err()
+}
+
+func (r *exportRepository) GetExport(id string) (*domain.Export, error) {
+	data, err := r.redis.Get(context.Background(), exportKeyPrefix+id).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrExportNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var export domain.Export
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, err
+	}
+	return &export, nil
+}
@@ -4,21 +4,37 @@ import (
 	"context"
 	"crud-clean-architecture/domain"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrInsufficientStock     = errors.New("insufficient variant stock")
+	ErrOrderNotFound         = errors.New("order not found")
+	ErrOrderAlreadyPaid      = errors.New("order is already paid")
+	ErrOrderCancelled        = errors.New("order is cancelled")
+	ErrPaymentExceedsBalance = errors.New("payment amount exceeds outstanding balance")
+	ErrOrderPaymentPending   = errors.New("order has a pending payment")
+	ErrOrderHasPayments      = errors.New("orders with received payments cannot be deleted")
 )
 
 type OrderRepository interface {
 	CreateOrder(order *domain.Order) error
 	GetAllOrders() ([]domain.Order, error)
 	GetOrderByID(id uint) (*domain.Order, error)
-	UpdateOrder(order *domain.Order) error
-	DeleteOrder(id uint) error
-	CreateOrderWithDetails(order *domain.Order) error
+	UpdateOrder(ctx context.Context, order *domain.Order) error
+	DeleteOrder(ctx context.Context, id uint) error
+	CreateOrderWithDetails(ctx context.Context, order *domain.Order) error
 	UpdateOrderInvoice(orderID uint, invoiceNumber string) error
+	UpdateOrderStatus(ctx context.Context, orderID uint, status domain.OrderStatus) error
+	GetStalePendingOrderIDs(before time.Time, limit int) ([]uint, error)
+	GetOrders(filter domain.OrderFilter) ([]domain.Order, error)
+	GetOrdersPage(filter domain.OrderFilter, afterID uint, limit int) ([]domain.Order, error)
+	CountOrders(filter domain.OrderFilter) (int64, error)
 }
 
 type orderRepository struct {
@@ -32,15 +48,9 @@ func NewOrderRepository(db *gorm.DB, redis *redis.Client) OrderRepository {
 
 const orderCacheKey = "order:all"
 
-func (r *orderRepository) CreateOrderWithDetails(order *domain.Order) error {
-	ctx := context.Background()
-
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, orderCacheKey).Err(); err != nil {
-		return err
-	}
+func (r *orderRepository) CreateOrderWithDetails(ctx context.Context, order *domain.Order) error {
 	// Mulai transaksi
-	tx := r.db.Begin()
+	tx := r.db.WithContext(ctx).Begin()
 	if tx.Error != nil {
 		return tx.Error
 	}
@@ -48,8 +58,8 @@ func (r *orderRepository) CreateOrderWithDetails(order *domain.Order) error {
 	originalDetails := order.Details
 	order.Details = nil
 
-	// Simpan data order
-	if err := tx.Create(&order).Error; err != nil {
+	// Simpan data order; relasi tidak ikut di-upsert dari input klien
+	if err := tx.Omit(clause.Associations).Create(&order).Error; err != nil {
 		tx.Rollback()
 		return err
 	}
@@ -60,19 +70,62 @@ func (r *orderRepository) CreateOrderWithDetails(order *domain.Order) error {
 	copy(details, order.Details)
 	// Simpan data order detail
 	for i := range details {
-		details[i].OrderID = order.ID                                       // Set OrderID untuk setiap detail
-		fmt.Printf("Saving order detail #%d: %+v\n", i+1, order.Details[i]) // Logging
+		details[i].OrderID = order.ID // Set OrderID untuk setiap detail
 
-		if err := tx.Create(&details[i]).Error; err != nil {
+		// Modifier dan komponen ikut disimpan, produk dan varian tidak
+		if err := tx.Omit("Product", "Variant").Create(&details[i]).Error; err != nil {
 			tx.Rollback()
 			return err
 		}
+		// Kurangi stok varian, termasuk varian komponen bundle
+		if details[i].VariantID != nil {
+			if err := decrementStock(tx, *details[i].VariantID, details[i].Quantity); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		for _, component := range details[i].Components {
+			if component.VariantID != nil {
+				if err := decrementStock(tx, *component.VariantID, component.Quantity); err != nil {
+					tx.Rollback()
+					return err
+				}
+			}
+		}
 	}
+	order.Details = details
+
+	// Generate kode invoice setelah ID order tersedia
+	order.InvoiceNumber = order.GenerateInvoiceNumber()
+	if err := tx.Model(order).Update("invoice_number", order.InvoiceNumber).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateOrder, order.ID, domain.EventOrderCreated, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, domain.AuditEntityOrder, order.ID, domain.AuditActionCreate, nil, order); err != nil {
+		tx.Rollback()
+		return err
+	}
+
 	// Commit transaksi jika semua berhasil
-	return tx.Commit().Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah create; stok varian ikut tampil di daftar produk
+	r.invalidateCache()
+	if hasVariants(details) {
+		_ = r.redis.Del(context.Background(), localizedKeys(productCacheKey)...).Err()
+	}
+	return nil
 }
 func (r *orderRepository) CreateOrder(order *domain.Order) error {
-	return r.db.Create(order).Error
+	return r.db.Omit(clause.Associations).Create(order).Error
 }
 
 func (r *orderRepository) GetAllOrders() ([]domain.Order, error) {
@@ -89,8 +142,8 @@ func (r *orderRepository) GetAllOrders() ([]domain.Order, error) {
 
 	// Jika cache tidak ada, fallback ke database
 	var orders []domain.Order
-	if err := r.db.Preload("Details").Preload("Details.Product").
-		Preload("Details.Product.Category").Find(&orders).Error; err != nil {
+	if err := r.db.Scopes(preloadOrderDetails).Preload("Details.Product.Category").
+		Preload("Payments").Find(&orders).Error; err != nil {
 		return nil, err
 	}
 
@@ -101,31 +154,361 @@ func (r *orderRepository) GetAllOrders() ([]domain.Order, error) {
 
 }
 
+// GetOrders returns the orders matching filter. Filtered lists are not cached.
+func (r *orderRepository) GetOrders(filter domain.OrderFilter) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Scopes(orderFilterScope(filter), preloadOrderDetails).Preload("Details.Product.Category").
+		Preload("Payments").Order("id").Find(&orders).Error
+	return orders, err
+}
+
+// GetOrdersPage returns up to limit orders with an ID greater than afterID so
+// callers can walk large result sets without holding them in memory.
+func (r *orderRepository) GetOrdersPage(filter domain.OrderFilter, afterID uint, limit int) ([]domain.Order, error) {
+	var orders []domain.Order
+	err := r.db.Scopes(orderFilterScope(filter), preloadOrderDetails).
+		Where("id > ?", afterID).Order("id").Limit(limit).Find(&orders).Error
+	return orders, err
+}
+
+func (r *orderRepository) CountOrders(filter domain.OrderFilter) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Order{}).Scopes(orderFilterScope(filter)).Count(&count).Error
+	return count, err
+}
+
+// preloadOrderDetails memuat detail order beserta produk, varian dan modifier yang dipilih
+func preloadOrderDetails(db *gorm.DB) *gorm.DB {
+	return db.Preload("Details").Preload("Details.Product").Preload("Details.Variant").Preload("Details.Modifiers").
+		Preload("Details.Components")
+}
+
+func orderFilterScope(filter domain.OrderFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.From != nil {
+			db = db.Where("order_date >= ?", *filter.From)
+		}
+		if filter.To != nil {
+			db = db.Where("order_date < ?", *filter.To)
+		}
+		if filter.Status != "" {
+			db = db.Where("status = ?", filter.Status)
+		}
+		return db
+	}
+}
+
 func (r *orderRepository) GetOrderByID(id uint) (*domain.Order, error) {
 	var order domain.Order
-	err := r.db.Preload("Details").First(&order, id).Error
-	return &order, err
+	err := r.db.Scopes(preloadOrderDetails).Preload("Payments").First(&order, id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return &order, nil
 }
 
-func (r *orderRepository) UpdateOrder(order *domain.Order) error {
-	ctx := context.Background()
+func (r *orderRepository) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockOrder(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Save(order).Error; err != nil {
+			return err
+		}
+		after, err := orderSnapshot(tx, order.ID)
+		if err != nil {
+			return err
+		}
+		if err := writeOutbox(tx, domain.AggregateOrder, order.ID, domain.EventOrderUpdated, after); err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityOrder, order.ID, domain.AuditActionUpdate, before, after)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Hapus cache setelah update
+	r.invalidateCache()
+	return nil
+}
 
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, orderCacheKey).Err(); err != nil {
+// DeleteOrder deletes the order with its details, modifiers and bundle
+// components in one transaction. Stock taken by an order that was not
+// cancelled (cancelling already returned it) is restored first.
+func (r *orderRepository) DeleteOrder(ctx context.Context, id uint) error {
+	restored := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockOrder(tx, id)
+		if err != nil {
+			return err
+		}
+		if err := deleteOrderPayments(tx, before); err != nil {
+			return err
+		}
+		if before.Status != domain.OrderStatusCancelled {
+			if restored, err = restoreVariantStock(tx, id); err != nil {
+				return err
+			}
+		}
+		if err := deleteOrderDetails(tx, id); err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.Order{}, id).Error; err != nil {
+			return err
+		}
+		if err := writeOutbox(tx, domain.AggregateOrder, id, domain.EventOrderDeleted, before); err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityOrder, id, domain.AuditActionDelete, before, nil)
+	})
+	if err != nil {
 		return err
 	}
-	return r.db.Save(order).Error
+
+	// Hapus cache setelah delete
+	r.invalidateCache()
+	if restored {
+		_ = r.redis.Del(context.Background(), localizedKeys(productCacheKey)...).Err()
+	}
+	return nil
 }
 
-func (r *orderRepository) DeleteOrder(id uint) error {
-	ctx := context.Background()
+// deleteOrderPayments menolak order yang sudah (sebagian) dibayar atau masih
+// menunggu pembayaran, lalu menghapus pembayaran gagal/kedaluwarsa/batal
+// beserta event-nya. Baris order sudah dikunci oleh pemanggil.
+func deleteOrderPayments(tx *gorm.DB, order *domain.Order) error {
+	if order.Status == domain.OrderStatusPaid || order.PaidAmount > 0 {
+		return ErrOrderHasPayments
+	}
+	var payments []domain.Payment
+	if err := tx.Where("order_id = ?", order.ID).Order("id").Find(&payments).Error; err != nil {
+		return err
+	}
+	for _, payment := range payments {
+		switch payment.Status {
+		case domain.PaymentStatusPaid:
+			return ErrOrderHasPayments
+		case domain.PaymentStatusPending:
+			return ErrOrderPaymentPending
+		}
+	}
+	for i := range payments {
+		if err := tx.Where("payment_id = ?", payments[i].ID).Delete(&domain.PaymentEvent{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&payments[i]).Error; err != nil {
+			return err
+		}
+		if err := writeAudit(tx, domain.AuditEntityPayment, payments[i].ID, domain.AuditActionDelete, &payments[i], nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, orderCacheKey).Err(); err != nil {
+// deleteOrderDetails menghapus detail order beserta modifier dan komponen bundle-nya
+func deleteOrderDetails(tx *gorm.DB, orderID uint) error {
+	var detailIDs []uint
+	if err := tx.Model(&domain.OrderDetail{}).Where("order_id = ?", orderID).Pluck("id", &detailIDs).Error; err != nil {
+		return err
+	}
+	if len(detailIDs) == 0 {
+		return nil
+	}
+	if err := tx.Where("order_detail_id IN ?", detailIDs).Delete(&domain.OrderDetailModifier{}).Error; err != nil {
 		return err
 	}
-	return r.db.Delete(&domain.Order{}, id).Error
+	if err := tx.Where("order_detail_id IN ?", detailIDs).Delete(&domain.OrderDetailComponent{}).Error; err != nil {
+		return err
+	}
+	return tx.Where("id IN ?", detailIDs).Delete(&domain.OrderDetail{}).Error
+}
+
+// lockOrder mengunci baris order lalu memuatnya beserta detailnya di dalam transaksi
+func lockOrder(tx *gorm.DB, id uint) (*domain.Order, error) {
+	var locked domain.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&locked, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+	return orderSnapshot(tx, id)
+}
+
+// orderSnapshot memuat order beserta detailnya di dalam transaksi untuk audit log.
+// Payment dicatat di audit log sebagai entitas sendiri.
+func orderSnapshot(tx *gorm.DB, id uint) (*domain.Order, error) {
+	var order domain.Order
+	if err := tx.Scopes(preloadOrderDetails).First(&order, id).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
 }
 func (r *orderRepository) UpdateOrderInvoice(orderID uint, invoiceNumber string) error {
 	return r.db.Model(&domain.Order{}).Where("id = ?", orderID).Update("invoice_number", invoiceNumber).Error
 }
+
+func (r *orderRepository) UpdateOrderStatus(ctx context.Context, orderID uint, status domain.OrderStatus) error {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	var order domain.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, orderID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrderNotFound
+		}
+		return err
+	}
+	before := order
+	restored := false
+	if status == domain.OrderStatusCancelled {
+		// Dicek setelah order terkunci; payment baru juga mengunci order yang sama
+		if err := checkCancellable(tx, &order); err != nil {
+			tx.Rollback()
+			return err
+		}
+		var err error
+		if restored, err = restoreVariantStock(tx, order.ID); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	order.Status = status
+	if err := tx.Model(&order).Update("status", status).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+
+	// Catat event di outbox dalam transaksi yang sama
+	if eventType, ok := orderStatusEvents[status]; ok {
+		if err := writeOutbox(tx, domain.AggregateOrder, order.ID, eventType, order); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	if err := writeAudit(tx, domain.AuditEntityOrder, order.ID, domain.AuditActionUpdate, &before, &order); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah update
+	r.invalidateCache()
+	if restored {
+		_ = r.redis.Del(context.Background(), localizedKeys(productCacheKey)...).Err()
+	}
+	return nil
+}
+
+// GetStalePendingOrderIDs returns pending orders placed before the given time
+// that have no pending or paid payment attached.
+func (r *orderRepository) GetStalePendingOrderIDs(before time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := r.db.Model(&domain.Order{}).
+		Where("status = ? AND order_date < ?", domain.OrderStatusPending, before).
+		Where("NOT EXISTS (SELECT 1 FROM payments WHERE payments.order_id = orders.id AND payments.status IN ?)",
+			[]domain.PaymentStatus{domain.PaymentStatusPending, domain.PaymentStatusPaid}).
+		Order("id").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
+}
+
+// checkCancellable menolak pembatalan order yang sudah lunas, sudah batal
+// atau masih punya payment pending
+func checkCancellable(tx *gorm.DB, order *domain.Order) error {
+	switch order.Status {
+	case domain.OrderStatusPaid:
+		return ErrOrderAlreadyPaid
+	case domain.OrderStatusCancelled:
+		return ErrOrderCancelled
+	}
+	var pending int64
+	if err := tx.Model(&domain.Payment{}).
+		Where("order_id = ? AND status = ?", order.ID, domain.PaymentStatusPending).
+		Count(&pending).Error; err != nil {
+		return err
+	}
+	if pending > 0 {
+		return ErrOrderPaymentPending
+	}
+	return nil
+}
+
+// decrementStock mengurangi stok varian; kondisi stock >= qty mencegah stok minus saat order bersamaan
+func decrementStock(tx *gorm.DB, variantID uint, quantity int) error {
+	result := tx.Model(&domain.ProductVariant{}).Where("id = ? AND stock >= ?", variantID, quantity).
+		Update("stock", gorm.Expr("stock - ?", quantity))
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrInsufficientStock
+	}
+	return nil
+}
+
+// restoreVariantStock mengembalikan stok varian (dan varian komponen bundle) dari order yang dibatalkan
+func restoreVariantStock(tx *gorm.DB, orderID uint) (bool, error) {
+	var details []domain.OrderDetail
+	if err := tx.Preload("Components").Where("order_id = ?", orderID).Find(&details).Error; err != nil {
+		return false, err
+	}
+
+	restored := false
+	restore := func(variantID *uint, quantity int) error {
+		if variantID == nil {
+			return nil
+		}
+		restored = true
+		return tx.Model(&domain.ProductVariant{}).Where("id = ?", *variantID).
+			Update("stock", gorm.Expr("stock + ?", quantity)).Error
+	}
+	for _, detail := range details {
+		if err := restore(detail.VariantID, detail.Quantity); err != nil {
+			return false, err
+		}
+		for _, component := range detail.Components {
+			if err := restore(component.VariantID, component.Quantity); err != nil {
+				return false, err
+			}
+		}
+	}
+	return restored, nil
+}
+
+func hasVariants(details []domain.OrderDetail) bool {
+	for _, detail := range details {
+		if detail.VariantID != nil {
+			return true
+		}
+		for _, component := range detail.Components {
+			if component.VariantID != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// orderStatusEvents memetakan status order ke event yang dicatat ke outbox
+var orderStatusEvents = map[domain.OrderStatus]domain.EventType{
+	domain.OrderStatusPaid:      domain.EventOrderPaid,
+	domain.OrderStatusCancelled: domain.EventOrderCancelled,
+}
+
+// invalidateCache menghapus cache segera setelah commit. Jika gagal, consumer
+// cache di relay outbox akan menghapusnya kembali.
+func (r *orderRepository) invalidateCache() {
+	_ = r.redis.Del(context.Background(), CacheKeysFor(domain.AggregateOrder)...).Err()
+}
@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type OutboxRepository interface {
+	ClaimPending(now time.Time, lease time.Duration, limit int) ([]domain.OutboxMessage, error)
+	UpdateMessage(message *domain.OutboxMessage) error
+	PurgePublished(before time.Time) (int64, error)
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db}
+}
+
+// writeOutbox menyimpan event ke tabel outbox; harus dipanggil dengan tx yang sama
+// dengan perubahan datanya supaya event tidak hilang atau terkirim tanpa datanya
+func writeOutbox(tx *gorm.DB, aggregateType string, aggregateID uint, eventType domain.EventType, payload interface{}) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	return tx.Create(&domain.OutboxMessage{
+		AggregateType: aggregateType,
+		AggregateID:   aggregateID,
+		EventType:     eventType,
+		Payload:       string(data),
+	}).Error
+}
+
+// ClaimPending returns up to limit messages that are neither published nor
+// dead and are due at now, in ID order. The rows are locked with SKIP LOCKED
+// only long enough to push their next_attempt_at past now by lease, so other
+// relay instances skip them while the consumers run outside the transaction.
+// A claim that is never settled with UpdateMessage expires after lease.
+func (r *outboxRepository) ClaimPending(now time.Time, lease time.Duration, limit int) ([]domain.OutboxMessage, error) {
+	var messages []domain.OutboxMessage
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("published_at IS NULL AND dead_at IS NULL AND (next_attempt_at IS NULL OR next_attempt_at <= ?)", now).
+			Order("id").Limit(limit).Find(&messages).Error; err != nil {
+			return err
+		}
+		if len(messages) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(messages))
+		for i := range messages {
+			ids[i] = messages[i].ID
+		}
+		return tx.Model(&domain.OutboxMessage{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return messages, nil
+}
+
+// UpdateMessage saves the outcome of publishing a claimed message.
+func (r *outboxRepository) UpdateMessage(message *domain.OutboxMessage) error {
+	return r.db.Model(message).Select("attempts", "last_error", "next_attempt_at", "published_at", "dead_at").Updates(message).Error
+}
+
+func (r *outboxRepository) PurgePublished(before time.Time) (int64, error) {
+	result := r.db.Where("published_at IS NOT NULL AND published_at < ?", before).Delete(&domain.OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
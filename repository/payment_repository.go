@@ -0,0 +1,234 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrPaymentNotFound       = errors.New("payment not found")
+	ErrDuplicatePaymentEvent = errors.New("payment event already processed")
+)
+
+type PaymentRepository interface {
+	CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Order, error)
+	UpdatePayment(ctx context.Context, payment *domain.Payment, update func(current *domain.Payment) error) (*domain.Order, error)
+	GetPaymentByID(id uint) (*domain.Payment, error)
+	GetPaymentByReference(provider, reference string) (*domain.Payment, error)
+	GetPaymentsByOrderID(orderID uint) ([]domain.Payment, error)
+	ApplyPaymentEvent(ctx context.Context, payment *domain.Payment, event *domain.PaymentEvent, apply func(current *domain.Payment) bool) (*domain.Order, error)
+}
+
+type paymentRepository struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewPaymentRepository(db *gorm.DB, redis *redis.Client) PaymentRepository {
+	return &paymentRepository{db, redis}
+}
+
+// CreatePayment menyimpan payment baru. Status order dan sisa tagihan dicek ulang
+// setelah order terkunci karena pengecekan di service bisa sudah basi.
+func (r *paymentRepository) CreatePayment(ctx context.Context, payment *domain.Payment) (*domain.Order, error) {
+	return r.savePaymentWithOrder(ctx, payment, func(tx *gorm.DB, order *domain.Order, payments []domain.Payment) error {
+		switch order.Status {
+		case domain.OrderStatusPaid:
+			return ErrOrderAlreadyPaid
+		case domain.OrderStatusCancelled:
+			return ErrOrderCancelled
+		}
+		if payment.Amount > order.OutstandingAmount(payments) {
+			return ErrPaymentExceedsBalance
+		}
+		if err := tx.Create(payment).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityPayment, payment.ID, domain.AuditActionCreate, nil, payment)
+	})
+}
+
+// UpdatePayment membaca ulang payment dengan lock lalu menyerahkannya ke update.
+// Error dari update membatalkan transaksi; payment diisi dengan kondisi terbaru.
+func (r *paymentRepository) UpdatePayment(ctx context.Context, payment *domain.Payment,
+	update func(current *domain.Payment) error) (*domain.Order, error) {
+	var current domain.Payment
+	order, err := r.savePaymentWithOrder(ctx, payment, func(tx *gorm.DB, order *domain.Order, payments []domain.Payment) error {
+		if err := lockPayment(tx, payment.ID, &current); err != nil {
+			return err
+		}
+		before := current
+		if err := update(&current); err != nil {
+			return err
+		}
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityPayment, current.ID, domain.AuditActionUpdate, &before, &current)
+	})
+	if err != nil {
+		return nil, err
+	}
+	*payment = current
+	return order, nil
+}
+
+// ApplyPaymentEvent mencatat event webhook dalam satu transaksi dengan perubahan payment.
+// Payment dibaca ulang dengan lock lalu diserahkan ke apply, yang mengubahnya dan
+// menentukan event.Applied. Event yang sama hanya diproses sekali; payment diisi
+// dengan kondisi terbaru setelah commit.
+func (r *paymentRepository) ApplyPaymentEvent(ctx context.Context, payment *domain.Payment, event *domain.PaymentEvent,
+	apply func(current *domain.Payment) bool) (*domain.Order, error) {
+	var current domain.Payment
+	order, err := r.savePaymentWithOrder(ctx, payment, func(tx *gorm.DB, order *domain.Order, payments []domain.Payment) error {
+		// Baris order sudah terkunci, jadi pengecekan duplikat ini aman dari race
+		var count int64
+		if err := tx.Model(&domain.PaymentEvent{}).
+			Where("provider = ? AND event_id = ?", event.Provider, event.EventID).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrDuplicatePaymentEvent
+		}
+
+		if err := lockPayment(tx, payment.ID, &current); err != nil {
+			return err
+		}
+		before := current
+		event.Applied = apply(&current)
+		event.PaymentID = current.ID
+		event.ProcessedAt = time.Now()
+		if err := tx.Create(event).Error; err != nil {
+			return err
+		}
+		if !event.Applied {
+			return nil
+		}
+		if err := tx.Save(&current).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityPayment, current.ID, domain.AuditActionUpdate, &before, &current)
+	})
+	if err != nil {
+		return nil, err
+	}
+	*payment = current
+	return order, nil
+}
+
+// lockPayment membaca ulang payment dengan lock di dalam transaksi
+func lockPayment(tx *gorm.DB, id uint, payment *domain.Payment) error {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(payment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPaymentNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// savePaymentWithOrder menyimpan payment dan menghitung ulang status order dalam satu transaksi.
+// save menerima order yang sudah terkunci beserta payment-nya saat itu dan
+// mencatat perubahan payment di audit log; perubahan order dicatat di sini.
+func (r *paymentRepository) savePaymentWithOrder(ctx context.Context, payment *domain.Payment,
+	save func(tx *gorm.DB, order *domain.Order, payments []domain.Payment) error) (*domain.Order, error) {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	// Kunci baris order agar pembayaran paralel tidak saling menimpa
+	var order domain.Order
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&order, payment.OrderID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	var payments []domain.Payment
+	if err := tx.Where("order_id = ?", order.ID).Find(&payments).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := save(tx, &order, payments); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Muat ulang payment agar perubahan dari save ikut dihitung
+	payments = nil
+	if err := tx.Where("order_id = ?", order.ID).Find(&payments).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	before := order
+	order.ApplyPayments(payments, time.Now())
+	if err := tx.Model(&order).Select("status", "paid_amount", "paid_at").Updates(&order).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Catat event order.paid di outbox saat pembayaran ini melunasi order
+	if before.Status != domain.OrderStatusPaid && order.Status == domain.OrderStatusPaid {
+		order.Payments = payments
+		if err := writeOutbox(tx, domain.AggregateOrder, order.ID, domain.EventOrderPaid, order); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		order.Payments = nil
+	}
+	if err := writeAudit(tx, domain.AuditEntityOrder, order.ID, domain.AuditActionUpdate, &before, &order); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache order setelah status pembayaran berubah
+	_ = r.redis.Del(context.Background(), CacheKeysFor(domain.AggregateOrder)...).Err()
+
+	order.Payments = payments
+	return &order, nil
+}
+
+func (r *paymentRepository) GetPaymentByID(id uint) (*domain.Payment, error) {
+	var payment domain.Payment
+	if err := r.db.First(&payment, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetPaymentByReference(provider, reference string) (*domain.Payment, error) {
+	var payment domain.Payment
+	err := r.db.Where("provider = ? AND provider_ref = ?", provider, reference).First(&payment).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPaymentNotFound
+		}
+		return nil, err
+	}
+	return &payment, nil
+}
+
+func (r *paymentRepository) GetPaymentsByOrderID(orderID uint) ([]domain.Payment, error) {
+	var payments []domain.Payment
+	err := r.db.Where("order_id = ?", orderID).Order("id").Find(&payments).Error
+	return payments, err
+}
@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SetAvailability replaces the availability schedule of the product; nil
+// makes it always available.
+func (r *productRepository) SetAvailability(ctx context.Context, productID uint, schedule *domain.AvailabilitySchedule) (*domain.Product, error) {
+	return r.changeChildren(ctx, productID, true, func(tx *gorm.DB) error {
+		return tx.Model(&domain.Product{ID: productID}).Select("availability").
+			Updates(&domain.Product{Availability: schedule}).Error
+	})
+}
+
+// ResolveAvailability stores the products excluded by filter.AvailableAt in
+// filter.UnavailableIDs, so the list, facet and export queries of one request
+// share a single evaluation of the schedules.
+func (r *productRepository) ResolveAvailability(filter *domain.ProductFilter) error {
+	if filter.AvailableAt == nil || filter.UnavailableIDs != nil {
+		return nil
+	}
+	ids, err := unavailableProductIDs(r.db, *filter.AvailableAt)
+	if err != nil {
+		return err
+	}
+	if ids == nil {
+		ids = []uint{}
+	}
+	filter.UnavailableIDs = ids
+	return nil
+}
+
+// unavailableProductIDs mengembalikan produk berjadwal yang tidak tersedia pada at.
+// Jadwal memakai zona waktu masing-masing produk sehingga dievaluasi di Go, bukan SQL.
+func unavailableProductIDs(db *gorm.DB, at time.Time) ([]uint, error) {
+	var products []domain.Product
+	if err := db.Select("id", "availability").Where("availability IS NOT NULL").Find(&products).Error; err != nil {
+		return nil, err
+	}
+	var ids []uint
+	for _, product := range products {
+		if !product.Availability.AvailableAt(at) {
+			ids = append(ids, product.ID)
+		}
+	}
+	return ids, nil
+}
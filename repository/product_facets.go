@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// productFacetsCacheKey menyimpan generasi cache facet. Setiap filter punya key
+// sendiri di bawah generasi itu dengan TTL masing-masing; menghapus key ini
+// (lewat CacheKeysFor) membuat semua facet lama tidak terpakai lagi dan
+// kedaluwarsa sendiri.
+const productFacetsCacheKey = "product:facets"
+
+const productFacetsCacheTTL = 10 * time.Minute
+
+const maxAttributeFacetValues = 50
+
+// GetFacets returns the facet counts for filter, cached per filter until the
+// catalog changes. Filters with available_at are not cached since they change
+// every minute.
+func (r *productRepository) GetFacets(filter domain.ProductFilter) (*domain.ProductFacets, error) {
+	if filter.AvailableAt != nil {
+		return r.countFacets(filter)
+	}
+	ctx := context.Background()
+
+	// Cek cache
+	generation, err := r.facetsGeneration(ctx)
+	key := productFacetsCacheKey + ":" + generation + ":" + filterHash(filter)
+	if err == nil {
+		if cachedData, err := r.redis.Get(ctx, key).Result(); err == nil {
+			var facets domain.ProductFacets
+			if err := json.Unmarshal([]byte(cachedData), &facets); err == nil {
+				return &facets, nil
+			}
+		}
+	}
+
+	// Jika cache tidak ada, hitung dari database
+	facets, err := r.countFacets(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	// Simpan ke cache hanya jika generasi terbaca, supaya tidak menulis ke generasi kosong
+	if generation != "" {
+		data, _ := json.Marshal(facets)
+		r.redis.Set(ctx, key, data, productFacetsCacheTTL)
+	}
+	return facets, nil
+}
+
+// facetsGeneration membaca generasi cache facet, atau membuat yang baru jika
+// key-nya sudah dihapus karena katalog berubah
+func (r *productRepository) facetsGeneration(ctx context.Context) (string, error) {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 36)
+	if err := r.redis.SetNX(ctx, productFacetsCacheKey, generation, 0).Err(); err != nil {
+		return "", err
+	}
+	return r.redis.Get(ctx, productFacetsCacheKey).Result()
+}
+
+func filterHash(filter domain.ProductFilter) string {
+	// json.Marshal mengurutkan key map sehingga filter yang sama menghasilkan hash yang sama
+	data, _ := json.Marshal(filter)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (r *productRepository) countFacets(filter domain.ProductFilter) (*domain.ProductFacets, error) {
+	facets := &domain.ProductFacets{}
+	if err := r.ResolveAvailability(&filter); err != nil {
+		return nil, err
+	}
+
+	// Kategori dihitung tanpa filter kategori itu sendiri
+	withoutCategory := filter
+	withoutCategory.CategoryID = 0
+	err := r.db.Model(&domain.Product{}).Scopes(productFilterScope(withoutCategory)).
+		Select("categories.id, categories.name, COUNT(products.id) AS count").
+		Joins("JOIN categories ON categories.id = products.category_id").
+		Group("categories.id").Order("categories.name").Scan(&facets.Categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if facets.PriceRanges, err = r.countPriceRanges(filter); err != nil {
+		return nil, err
+	}
+
+	tags, err := tagCounts(r.db, filter)
+	if err != nil {
+		return nil, err
+	}
+	facets.Tags = []domain.TagFacet{}
+	for _, tag := range tags {
+		if tag.ProductCount > 0 {
+			facets.Tags = append(facets.Tags, domain.TagFacet{ID: tag.ID, Name: tag.Name, Count: tag.ProductCount})
+		}
+	}
+
+	if facets.Attributes, err = r.countAttributes(filter); err != nil {
+		return nil, err
+	}
+	return facets, nil
+}
+
+// countPriceRanges menghitung produk per bucket PriceBucketBounds tanpa filter harga
+func (r *productRepository) countPriceRanges(filter domain.ProductFilter) ([]domain.PriceFacet, error) {
+	withoutPrice := filter
+	withoutPrice.MinPrice = nil
+	withoutPrice.MaxPrice = nil
+
+	bounds := domain.PriceBucketBounds
+	var cases strings.Builder
+	args := make([]interface{}, len(bounds))
+	cases.WriteString("CASE")
+	for i, bound := range bounds {
+		fmt.Fprintf(&cases, " WHEN price < ? THEN %d", i)
+		args[i] = bound
+	}
+	fmt.Fprintf(&cases, " ELSE %d END", len(bounds))
+
+	var rows []struct {
+		Bucket int
+		Count  int64
+	}
+	err := r.db.Model(&domain.Product{}).Scopes(productFilterScope(withoutPrice)).
+		Select(cases.String()+" AS bucket, COUNT(*) AS count", args...).
+		Group("bucket").Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	ranges := make([]domain.PriceFacet, len(bounds)+1)
+	for i := range ranges {
+		if i > 0 {
+			ranges[i].Min = bounds[i-1]
+		}
+		if i < len(bounds) {
+			ranges[i].Max = &bounds[i]
+		}
+	}
+	for _, row := range rows {
+		ranges[row.Bucket].Count = row.Count
+	}
+	return ranges, nil
+}
+
+// countAttributes menghitung nilai setiap atribut. Filter atribut lain tetap
+// berlaku, sedangkan filter atribut itu sendiri diabaikan.
+func (r *productRepository) countAttributes(filter domain.ProductFilter) ([]domain.AttributeFacet, error) {
+	withoutAttributes := filter
+	withoutAttributes.Attributes = nil
+
+	var products []domain.Product
+	err := r.db.Scopes(productFilterScope(withoutAttributes)).Select("id", "attributes").
+		Where("attributes IS NOT NULL").Find(&products).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := map[string]map[string]int64{}
+	for _, product := range products {
+		for name, value := range product.Attributes {
+			if value == nil || !matchesAttributes(product.Attributes, filter.Attributes, name) {
+				continue
+			}
+			if counts[name] == nil {
+				counts[name] = map[string]int64{}
+			}
+			counts[name][attributeFacetValue(value)]++
+		}
+	}
+
+	facets := []domain.AttributeFacet{}
+	for name, values := range counts {
+		facet := domain.AttributeFacet{Name: name}
+		for value, count := range values {
+			facet.Values = append(facet.Values, domain.AttributeValueFacet{Value: value, Count: count})
+		}
+		sort.Slice(facet.Values, func(i, j int) bool {
+			if facet.Values[i].Count != facet.Values[j].Count {
+				return facet.Values[i].Count > facet.Values[j].Count
+			}
+			return facet.Values[i].Value < facet.Values[j].Value
+		})
+		if len(facet.Values) > maxAttributeFacetValues {
+			facet.Values = facet.Values[:maxAttributeFacetValues]
+		}
+		facets = append(facets, facet)
+	}
+	sort.Slice(facets, func(i, j int) bool { return facets[i].Name < facets[j].Name })
+	return facets, nil
+}
+
+// matchesAttributes mengikuti aturan filter attr di productFilterScope untuk semua atribut kecuali skip
+func matchesAttributes(attributes map[string]interface{}, wanted map[string]string, skip string) bool {
+	for name, want := range wanted {
+		if name == skip {
+			continue
+		}
+		value, ok := attributes[name]
+		if !ok || value == nil {
+			return false
+		}
+		if number, isNumber := value.(float64); isNumber {
+			if parsed, err := strconv.ParseFloat(want, 64); err == nil && parsed == number {
+				continue
+			}
+		}
+		if attributeFacetValue(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// attributeFacetValue menulis nilai atribut dalam bentuk yang sama dengan JSON_UNQUOTE
+func attributeFacetValue(value interface{}) string {
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
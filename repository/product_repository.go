@@ -5,19 +5,70 @@ import (
 	"crud-clean-architecture/domain"
 	"encoding/json"
 	"errors"
+	"sort"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrProductNotFound       = errors.New("product not found")
+	ErrPriceNotFound         = errors.New("scheduled price not found")
+	ErrPriceAlreadyEffective = errors.New("price is already effective")
+	ErrVariantNotFound       = errors.New("variant not found")
+	ErrModifierGroupNotFound = errors.New("modifier group not found")
+	ErrProductInBundle       = errors.New("product is a component of a bundle")
+	ErrInvalidComponent      = errors.New("bundle component must be a simple product with its variant named")
+	ErrImageNotFound         = errors.New("image not found")
 )
 
 type ProductRepository interface {
-	CreateProduct(product *domain.Product) error
-	GetAllProducts() ([]domain.Product, error)
+	CreateProduct(ctx context.Context, product *domain.Product) error
+	GetAllProducts(locale string) ([]domain.Product, error)
 	GetProductByID(id uint) (*domain.Product, error)
-	UpdateProduct(product *domain.Product) error
-	DeleteProduct(id uint) error
+	UpdateProduct(ctx context.Context, product *domain.Product) error
+	DeleteProduct(ctx context.Context, id uint, version uint) ([]domain.ProductImage, error)
+	PatchProduct(ctx context.Context, id uint, version uint, changes map[string]interface{}) (*domain.Product, error)
 	IsProductNameUnique(name string, categori_id uint) (bool, error)
+	GetProducts(filter domain.ProductFilter) ([]domain.Product, error)
+	GetProductsPage(filter domain.ProductFilter, afterID uint, limit int) ([]domain.Product, error)
+	CountProducts(filter domain.ProductFilter) (int64, error)
+	GetProductByName(name string, categoryID uint) (*domain.Product, error)
+	ResolveAvailability(filter *domain.ProductFilter) error
+	ImportProducts(ctx context.Context, items []domain.ProductImportItem) error
+	ApplyBatch(ctx context.Context, ops []domain.ProductBatchOperation) ([]domain.Product, error)
+	GetPriceHistory(productID uint) ([]domain.ProductPrice, error)
+	GetPriceAt(productID uint, at time.Time) (*domain.ProductPrice, error)
+	SchedulePrice(ctx context.Context, price *domain.ProductPrice) error
+	DeleteScheduledPrice(ctx context.Context, productID, priceID uint) (*domain.ProductPrice, error)
+	ApplyDuePrices(ctx context.Context, now time.Time) (int, error)
+	GetVariants(productID uint) ([]domain.ProductVariant, error)
+	GetVariantByID(productID, variantID uint) (*domain.ProductVariant, error)
+	IsSKUUnique(sku string, excludeID uint) (bool, error)
+	CreateVariant(ctx context.Context, variant *domain.ProductVariant) error
+	UpdateVariant(ctx context.Context, variant *domain.ProductVariant) error
+	DeleteVariant(ctx context.Context, productID, variantID uint) error
+	GetModifierGroups(productID uint) ([]domain.ModifierGroup, error)
+	GetModifierGroupByID(productID, groupID uint) (*domain.ModifierGroup, error)
+	CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error
+	UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error
+	DeleteModifierGroup(ctx context.Context, productID, groupID uint) error
+	SetBundleComponents(ctx context.Context, productID uint, components []domain.BundleComponent) (*domain.Product, error)
+	GetImages(productID uint) ([]domain.ProductImage, error)
+	GetImageByID(productID, imageID uint) (*domain.ProductImage, error)
+	CreateImage(ctx context.Context, image *domain.ProductImage) error
+	DeleteImage(ctx context.Context, productID, imageID uint) (*domain.ProductImage, error)
+	ReorderImages(ctx context.Context, productID uint, imageIDs []uint) ([]domain.ProductImage, error)
+	GetFacets(filter domain.ProductFilter) (*domain.ProductFacets, error)
+	FindSlug(slug string) (*domain.Slug, error)
+	BackfillSlugs() (int, error)
+	SetTranslation(ctx context.Context, id uint, version uint, locale string, translation *domain.Translation) (*domain.Product, error)
+	SetAvailability(ctx context.Context, productID uint, schedule *domain.AvailabilitySchedule) (*domain.Product, error)
+	SetTags(ctx context.Context, productID uint, tagIDs []uint) (*domain.Product, error)
+	RemoveTag(ctx context.Context, productID, tagID uint) (*domain.Product, error)
 }
 
 type productRepository struct {
@@ -31,25 +82,58 @@ func NewProductRepository(db *gorm.DB, redis *redis.Client) ProductRepository {
 
 const productCacheKey = "product:all"
 
-func (r *productRepository) CreateProduct(product *domain.Product) error {
-	ctx := context.Background()
-
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, productCacheKey).Err(); err != nil {
+func (r *productRepository) CreateProduct(ctx context.Context, product *domain.Product) error {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	product.Version = 1
+	product.Type = domain.ProductTypeSimple
+	if err := tx.Create(product).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	slug, err := assignSlug(tx, domain.AggregateProduct, product.ID, product.Name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	product.Slug = slug
+	if err := recordPriceChange(tx, product.ID, product.Price); err != nil {
+		tx.Rollback()
+		return err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductCreated, product); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionCreate, nil, product); err != nil {
+		tx.Rollback()
 		return err
 	}
-	return r.db.Create(product).Error
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah create
+	r.invalidateCache()
+	return nil
 }
 func (r *productRepository) IsProductNameUnique(name string, categori_id uint) (bool, error) {
 	var count int64
 	err := r.db.Model(&domain.Product{}).Where("name = ?", name).Where("category_id = ?", categori_id).Count(&count).Error
 	return count == 0, err
 }
-func (r *productRepository) GetAllProducts() ([]domain.Product, error) {
+
+// GetAllProducts returns every product localized for locale, cached per locale.
+func (r *productRepository) GetAllProducts(locale string) ([]domain.Product, error) {
 	ctx := context.Background()
+	cacheKey := localizedKey(productCacheKey, locale)
 
 	// Cek cache
-	cachedData, err := r.redis.Get(ctx, productCacheKey).Result()
+	cachedData, err := r.redis.Get(ctx, cacheKey).Result()
 	if err == nil {
 		var products []domain.Product
 		if err := json.Unmarshal([]byte(cachedData), &products); err == nil {
@@ -59,51 +143,1318 @@ func (r *productRepository) GetAllProducts() ([]domain.Product, error) {
 
 	// Jika cache tidak ada, fallback ke database
 	var products []domain.Product
-	if err := r.db.Preload("Category").Find(&products).Error; err != nil {
+	if err := r.db.Scopes(preloadProductChildren).Find(&products).Error; err != nil {
 		return nil, err
 	}
+	for i := range products {
+		products[i].Localize(locale)
+	}
 
 	// Simpan ke cache
 	data, _ := json.Marshal(products)
-	_ = r.redis.Set(ctx, productCacheKey, data, 10*time.Minute).Err()
+	_ = r.redis.Set(ctx, cacheKey, data, 10*time.Minute).Err()
 
 	return products, nil
 }
 
+// GetProducts returns the products matching filter. Filtered lists are not cached.
+func (r *productRepository) GetProducts(filter domain.ProductFilter) ([]domain.Product, error) {
+	var products []domain.Product
+	err := r.db.Scopes(productFilterScope(filter), preloadProductChildren).Order("id").Find(&products).Error
+	return products, err
+}
+
+// GetProductsPage returns up to limit products with an ID greater than afterID.
+func (r *productRepository) GetProductsPage(filter domain.ProductFilter, afterID uint, limit int) ([]domain.Product, error) {
+	var products []domain.Product
+	err := r.db.Scopes(productFilterScope(filter), preloadProductChildren).
+		Where("id > ?", afterID).Order("id").Limit(limit).Find(&products).Error
+	return products, err
+}
+
+func (r *productRepository) CountProducts(filter domain.ProductFilter) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Product{}).Scopes(productFilterScope(filter)).Count(&count).Error
+	return count, err
+}
+
+// preloadProductChildren memuat kategori, varian dan modifier yang ikut tampil di response produk
+func preloadProductChildren(db *gorm.DB) *gorm.DB {
+	return db.Preload("Category").Preload("Variants").Preload("ModifierGroups").Preload("ModifierGroups.Options").
+		Preload("Components").Preload("Images", orderImages).Preload("Tags", orderTags)
+}
+
+func orderTags(db *gorm.DB) *gorm.DB {
+	return db.Order("tags.name")
+}
+
+func orderImages(db *gorm.DB) *gorm.DB {
+	return db.Order("position, id")
+}
+
+func productFilterScope(filter domain.ProductFilter) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		if filter.CategoryID != 0 {
+			db = db.Where("category_id = ?", filter.CategoryID)
+		}
+		if filter.MinPrice != nil {
+			db = db.Where("price >= ?", *filter.MinPrice)
+		}
+		if filter.MaxPrice != nil {
+			db = db.Where("price < ?", *filter.MaxPrice)
+		}
+		if len(filter.Tags) > 0 {
+			tagged := db.Session(&gorm.Session{NewDB: true}).Table("product_tags").Select("product_tags.product_id").
+				Joins("JOIN tags ON tags.id = product_tags.tag_id").Where("tags.name IN ?", filter.Tags)
+			// Mode all: produk harus memiliki semua tag yang diminta
+			if filter.TagMode != domain.TagModeAny {
+				tagged = tagged.Group("product_tags.product_id").Having("COUNT(DISTINCT product_tags.tag_id) = ?", len(filter.Tags))
+			}
+			db = db.Where("products.id IN (?)", tagged)
+		}
+		names := make([]string, 0, len(filter.Attributes))
+		for name := range filter.Attributes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			// Nama sudah divalidasi (huruf kecil, angka, underscore) sehingga aman dipakai sebagai JSON path
+			path := `$."` + name + `"`
+			value := filter.Attributes[name]
+			// Nilai angka dibandingkan secara numerik supaya 500 dan 500.0 sama
+			if number, err := strconv.ParseFloat(value, 64); err == nil {
+				db = db.Where("(JSON_UNQUOTE(JSON_EXTRACT(attributes, ?)) = ? OR JSON_EXTRACT(attributes, ?) = ?)",
+					path, value, path, number)
+			} else {
+				db = db.Where("JSON_UNQUOTE(JSON_EXTRACT(attributes, ?)) = ?", path, value)
+			}
+		}
+		if filter.AvailableAt != nil {
+			// Biasanya sudah di-resolve lewat ResolveAvailability; dihitung di sini hanya jika belum
+			unavailable := filter.UnavailableIDs
+			if unavailable == nil {
+				var err error
+				if unavailable, err = unavailableProductIDs(db.Session(&gorm.Session{NewDB: true}), *filter.AvailableAt); err != nil {
+					_ = db.AddError(err)
+					return db
+				}
+			}
+			if len(unavailable) > 0 {
+				db = db.Where("products.id NOT IN ?", unavailable)
+			}
+		}
+		return db
+	}
+}
+
 func (r *productRepository) GetProductByID(id uint) (*domain.Product, error) {
 	var product domain.Product
-	err := r.db.Preload("Category").First(&product, id).Error
+	err := r.db.Scopes(preloadProductChildren).First(&product, id).Error
 	if err != nil {
 		return nil, err
 	}
 	return &product, nil
 }
 
-func (r *productRepository) UpdateProduct(product *domain.Product) error {
-	ctx := context.Background()
-
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, productCacheKey).Err(); err != nil {
+// UpdateProduct saves product when its Version still matches the stored one
+// (any version when zero) and increments the version.
+func (r *productRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var current domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, product.ID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrProductNotFound
+		}
+		return err
+	}
+	if err := checkVersion(current.Version, product.Version); err != nil {
+		tx.Rollback()
+		return err
+	}
+	before, err := productSnapshot(tx, product.ID)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	product.Version = current.Version + 1
+	// Tipe hanya berubah lewat endpoint komponen bundle
+	product.Type = current.Type
+	// Terjemahan dan jadwal hanya berubah lewat endpoint masing-masing
+	product.Translations = current.Translations
+	product.Availability = current.Availability
+	if err := tx.Save(product).Error; err != nil {
+		tx.Rollback()
 		return err
 	}
-	return r.db.Save(product).Error
+	// Slug lama tetap disimpan sebagai redirect
+	slug, err := assignSlug(tx, domain.AggregateProduct, product.ID, product.Name)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	product.Slug = slug
+	if product.Price != current.Price {
+		if err := recordPriceChange(tx, product.ID, product.Price); err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	// Versi kategori ikut menentukan ETag produk
+	if err := tx.First(&product.Category, product.CategoryID).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductUpdated, product); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := auditProductUpdate(tx, before); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache setelah update
+	r.invalidateCache()
+	return nil
+}
+
+// PatchProduct updates only the changed columns when the version matches
+// (any version when zero). The version is bumped only if something changed.
+func (r *productRepository) PatchProduct(ctx context.Context, id uint, version uint, changes map[string]interface{}) (*domain.Product, error) {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if err := checkVersion(product.Version, version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if len(changes) == 0 {
+		tx.Rollback()
+		return &product, nil
+	}
+	before, err := productSnapshot(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	// Updates dengan map tidak melewati serializer, jadi atribut di-encode manual
+	if attributes, ok := changes["attributes"]; ok {
+		data, err := json.Marshal(attributes)
+		if err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+		changes["attributes"] = string(data)
+	}
+	changes["version"] = product.Version + 1
+	if err := tx.Model(&product).Updates(changes).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if name, ok := changes["name"].(string); ok {
+		if _, err := assignSlug(tx, domain.AggregateProduct, product.ID, name); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if price, ok := changes["price"].(float64); ok {
+		if err := recordPriceChange(tx, product.ID, price); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Preload("Category").First(&product, id).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductUpdated, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := auditProductUpdate(tx, before); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache setelah update
+	r.invalidateCache()
+	return &product, nil
 }
 
-func (r *productRepository) DeleteProduct(id uint) error {
+// DeleteProduct deletes the product when its version matches (any version when zero)
+// and returns its deleted images so the caller can remove their files.
+func (r *productRepository) DeleteProduct(ctx context.Context, id uint, version uint) ([]domain.ProductImage, error) {
 	var product domain.Product
+
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
 	// Periksa apakah data dengan ID ada
-	if err := r.db.First(&product, id).Error; err != nil {
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error; err != nil {
+		tx.Rollback()
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return errors.New("product not found")
+			return nil, ErrProductNotFound
 		}
+		return nil, err
+	}
+	if err := checkVersion(product.Version, version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	before, err := productSnapshot(tx, product.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Hapus data jika ditemukan, termasuk varian dan modifier
+	images, err := deleteProductChildren(tx, product.ID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Delete(&product).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductDeleted, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionDelete, before, nil); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache setelah delete
+	r.invalidateCache()
+	return images, nil
+}
+
+// GetProductByName returns nil without error when the category has no product with the name.
+func (r *productRepository) GetProductByName(name string, categoryID uint) (*domain.Product, error) {
+	var product domain.Product
+	err := r.db.Where("name = ? AND category_id = ?", name, categoryID).First(&product).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// ImportProducts writes the items in one transaction. Missing categories are
+// created by name first; items with a Product.ID update that product. IDs are
+// written back into items.
+func (r *productRepository) ImportProducts(ctx context.Context, items []domain.ProductImportItem) error {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+
+	categoryCreated := false
+	for i := range items {
+		item := &items[i]
+
+		if item.Product.CategoryID == 0 {
+			// Kategori bisa sudah dibuat oleh baris atau batch sebelumnya
+			var category domain.Category
+			err := tx.Where("name = ?", item.CategoryName).First(&category).Error
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				category.Name = item.CategoryName
+				category.Version = 1
+				if err := tx.Create(&category).Error; err != nil {
+					tx.Rollback()
+					return err
+				}
+				if category.Slug, err = assignSlug(tx, domain.AggregateCategory, category.ID, category.Name); err != nil {
+					tx.Rollback()
+					return err
+				}
+				if err := writeOutbox(tx, domain.AggregateCategory, category.ID, domain.EventCategoryCreated, category); err != nil {
+					tx.Rollback()
+					return err
+				}
+				if err := writeAudit(tx, domain.AggregateCategory, category.ID, domain.AuditActionCreate, nil, category); err != nil {
+					tx.Rollback()
+					return err
+				}
+				categoryCreated = true
+			} else if err != nil {
+				tx.Rollback()
+				return err
+			}
+			item.Product.CategoryID = category.ID
+		}
+
+		eventType := domain.EventProductCreated
+		priceChanged := true
+		var before *domain.Product
+		if item.Product.ID != 0 {
+			// Update hanya mengganti kolom dari CSV dan menaikkan versi
+			var current domain.Product
+			if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&current, item.Product.ID).Error; err != nil {
+				tx.Rollback()
+				return err
+			}
+			var err error
+			if before, err = productSnapshot(tx, current.ID); err != nil {
+				tx.Rollback()
+				return err
+			}
+			priceChanged = current.Price != item.Product.Price
+			current.Name = item.Product.Name
+			current.Price = item.Product.Price
+			current.CategoryID = item.Product.CategoryID
+			current.Version++
+			item.Product = current
+			eventType = domain.EventProductUpdated
+		} else {
+			item.Product.Version = 1
+			item.Product.Type = domain.ProductTypeSimple
+		}
+		if err := tx.Save(&item.Product).Error; err != nil {
+			tx.Rollback()
+			return err
+		}
+		slug, err := assignSlug(tx, domain.AggregateProduct, item.Product.ID, item.Product.Name)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+		item.Product.Slug = slug
+		if priceChanged {
+			if err := recordPriceChange(tx, item.Product.ID, item.Product.Price); err != nil {
+				tx.Rollback()
+				return err
+			}
+		}
+		if err := writeOutbox(tx, domain.AggregateProduct, item.Product.ID, eventType, item.Product); err != nil {
+			tx.Rollback()
+			return err
+		}
+		if before != nil {
+			err = auditProductUpdate(tx, before)
+		} else {
+			err = writeAudit(tx, domain.AggregateProduct, item.Product.ID, domain.AuditActionCreate, nil, item.Product)
+		}
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+
+	if err := tx.Commit().Error; err != nil {
 		return err
 	}
-	ctx := context.Background()
 
-	// Hapus cache setelah create
-	if err := r.redis.Del(ctx, productCacheKey).Err(); err != nil {
+	// Hapus cache setelah import; kategori baru juga membuat cache kategori basi
+	if categoryCreated {
+		_ = r.redis.Del(context.Background(), CacheKeysFor(domain.AggregateCategory)...).Err()
+	} else {
+		r.invalidateCache()
+	}
+	return nil
+}
+
+// ApplyBatch runs the operations in one transaction and returns the resulting
+// product of each one. The cache is invalidated once after commit. A failing
+// operation rolls back the batch and is reported as *BatchItemError.
+func (r *productRepository) ApplyBatch(ctx context.Context, ops []domain.ProductBatchOperation) ([]domain.Product, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	products := make([]domain.Product, len(ops))
+	for i, op := range ops {
+		product, eventType, err := applyProductOperation(tx, op)
+		if err == nil {
+			err = writeOutbox(tx, domain.AggregateProduct, product.ID, eventType, product)
+		}
+		if err != nil {
+			tx.Rollback()
+			return nil, &BatchItemError{Index: i, Err: err}
+		}
+		products[i] = product
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Satu kali hapus cache untuk seluruh batch
+	r.invalidateCache()
+	return products, nil
+}
+
+// applyProductOperation menjalankan satu operasi batch dan mencatatnya di audit log
+func applyProductOperation(tx *gorm.DB, op domain.ProductBatchOperation) (domain.Product, domain.EventType, error) {
+	var product domain.Product
+	var before *domain.Product
+	if op.Op != domain.BatchOpCreate {
+		// Periksa apakah data dengan ID ada
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, op.ID).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return product, "", ErrProductNotFound
+			}
+			return product, "", err
+		}
+		if err := checkVersion(product.Version, op.Version); err != nil {
+			return product, "", err
+		}
+		var err error
+		if before, err = productSnapshot(tx, product.ID); err != nil {
+			return product, "", err
+		}
+	}
+
+	switch op.Op {
+	case domain.BatchOpCreate:
+		product = domain.Product{Name: op.Data.Name, Description: op.Data.Description, Price: op.Data.Price,
+			CategoryID: op.Data.CategoryID, Attributes: op.Data.Attributes, Type: domain.ProductTypeSimple, Version: 1}
+		if err := tx.Create(&product).Error; err != nil {
+			return product, "", err
+		}
+		slug, err := assignSlug(tx, domain.AggregateProduct, product.ID, product.Name)
+		if err != nil {
+			return product, "", err
+		}
+		product.Slug = slug
+		if err := recordPriceChange(tx, product.ID, product.Price); err != nil {
+			return product, "", err
+		}
+		return product, domain.EventProductCreated, writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionCreate, nil, product)
+	case domain.BatchOpUpdate:
+		priceChanged := product.Price != op.Data.Price
+		product.Name = op.Data.Name
+		product.Description = op.Data.Description
+		product.Price = op.Data.Price
+		product.CategoryID = op.Data.CategoryID
+		product.Attributes = op.Data.Attributes
+		product.Version++
+		if err := tx.Save(&product).Error; err != nil {
+			return product, "", err
+		}
+		slug, err := assignSlug(tx, domain.AggregateProduct, product.ID, product.Name)
+		if err != nil {
+			return product, "", err
+		}
+		product.Slug = slug
+		if priceChanged {
+			if err := recordPriceChange(tx, product.ID, product.Price); err != nil {
+				return product, "", err
+			}
+		}
+		return product, domain.EventProductUpdated, auditProductUpdate(tx, before)
+	default:
+		// Gambar dikembalikan lewat product agar file-nya dihapus setelah commit
+		images, err := deleteProductChildren(tx, product.ID)
+		if err != nil {
+			return product, "", err
+		}
+		product.Images = images
+		if err := tx.Delete(&product).Error; err != nil {
+			return product, "", err
+		}
+		return product, domain.EventProductDeleted, writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionDelete, before, nil)
+	}
+}
+
+// GetPriceHistory returns every price of the product, scheduled ones first.
+func (r *productRepository) GetPriceHistory(productID uint) ([]domain.ProductPrice, error) {
+	var prices []domain.ProductPrice
+	err := r.db.Where("product_id = ?", productID).Order("effective_from DESC, id DESC").Find(&prices).Error
+	return prices, err
+}
+
+// GetPriceAt returns the price in effect at the given time, or nil when the
+// product has no price history (products created before it was recorded).
+func (r *productRepository) GetPriceAt(productID uint, at time.Time) (*domain.ProductPrice, error) {
+	var price domain.ProductPrice
+	err := r.db.Where("product_id = ? AND effective_from <= ?", productID, at).
+		Order("effective_from DESC, id DESC").First(&price).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+func (r *productRepository) SchedulePrice(ctx context.Context, price *domain.ProductPrice) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Product{}).Where("id = ?", price.ProductID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return ErrProductNotFound
+		}
+		price.Applied = false
+		if err := tx.Create(price).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductPrice, price.ID, domain.AuditActionCreate, nil, price)
+	})
+}
+
+// DeleteScheduledPrice cancels a price change that is not effective yet.
+func (r *productRepository) DeleteScheduledPrice(ctx context.Context, productID, priceID uint) (*domain.ProductPrice, error) {
+	var price domain.ProductPrice
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Kunci baris supaya scheduler tidak menerapkannya di antara pengecekan dan delete
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND product_id = ?", priceID, productID).First(&price).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPriceNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if price.Applied || !price.EffectiveFrom.After(time.Now()) {
+			return ErrPriceAlreadyEffective
+		}
+		if err := tx.Delete(&price).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductPrice, price.ID, domain.AuditActionDelete, price, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &price, nil
+}
+
+// ApplyDuePrices copies scheduled prices that became effective before now to
+// Product.Price. When several are due only the latest one wins, and a price
+// set directly after the schedule is kept. Each product is updated in its own
+// transaction with a version bump and a product.updated event. It returns
+// how many products changed.
+func (r *productRepository) ApplyDuePrices(ctx context.Context, now time.Time) (int, error) {
+	var productIDs []uint
+	err := r.db.Model(&domain.ProductPrice{}).Where("applied = ? AND effective_from <= ?", false, now).
+		Distinct().Pluck("product_id", &productIDs).Error
+	if err != nil {
+		return 0, err
+	}
+
+	applied := 0
+	for _, productID := range productIDs {
+		changed, err := r.applyDuePrice(ctx, productID, now)
+		if err != nil {
+			return applied, err
+		}
+		if changed {
+			applied++
+		}
+	}
+
+	if applied > 0 {
+		r.invalidateCache()
+	}
+	return applied, nil
+}
+
+func (r *productRepository) applyDuePrice(ctx context.Context, productID uint, now time.Time) (bool, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+
+	var product domain.Product
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Harga yang berlaku sekarang adalah entri terakhir yang sudah jatuh tempo
+	var latest domain.ProductPrice
+	if err := tx.Where("product_id = ? AND effective_from <= ?", productID, now).
+		Order("effective_from DESC, id DESC").First(&latest).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := tx.Model(&domain.ProductPrice{}).Where("product_id = ? AND applied = ? AND effective_from <= ?", productID, false, now).
+		Update("applied", true).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+
+	// Produk sudah dihapus atau harga terakhir diubah langsung: cukup tandai applied
+	if product.ID == 0 || latest.Applied || latest.Price == product.Price {
+		return false, tx.Commit().Error
+	}
+
+	before := product
+	product.Price = latest.Price
+	product.Version++
+	if err := tx.Model(&product).Updates(map[string]interface{}{"price": product.Price, "version": product.Version}).Error; err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductUpdated, product); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionUpdate, &before, &product); err != nil {
+		tx.Rollback()
+		return false, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *productRepository) GetVariants(productID uint) ([]domain.ProductVariant, error) {
+	var variants []domain.ProductVariant
+	err := r.db.Where("product_id = ?", productID).Order("id").Find(&variants).Error
+	return variants, err
+}
+
+func (r *productRepository) GetVariantByID(productID, variantID uint) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	err := r.db.Where("id = ? AND product_id = ?", variantID, productID).First(&variant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+// IsSKUUnique checks the SKU across all products, ignoring variant excludeID.
+func (r *productRepository) IsSKUUnique(sku string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.ProductVariant{}).Where("sku = ? AND id <> ?", sku, excludeID).Count(&count).Error
+	return count == 0, err
+}
+
+// CreateVariant adds the variant and bumps the parent product's version,
+// since the variants are part of the product representation.
+func (r *productRepository) CreateVariant(ctx context.Context, variant *domain.ProductVariant) error {
+	_, err := r.changeChildren(ctx, variant.ProductID, false, func(tx *gorm.DB) error {
+		// Komponen bundle tanpa varian tidak boleh tiba-tiba memerlukan variant_id
+		if err := checkNotComponent(tx, variant.ProductID); err != nil {
+			return err
+		}
+		if err := tx.Create(variant).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductVariant, variant.ID, domain.AuditActionCreate, nil, variant)
+	})
+	return err
+}
+
+func (r *productRepository) UpdateVariant(ctx context.Context, variant *domain.ProductVariant) error {
+	_, err := r.changeChildren(ctx, variant.ProductID, false, func(tx *gorm.DB) error {
+		before, err := findVariant(tx, variant.ProductID, variant.ID)
+		if err != nil {
+			return err
+		}
+		// Select supaya stock 0 dan attributes kosong tetap ikut di-update
+		if err := tx.Model(variant).Select("sku", "name", "price", "stock", "attributes", "updated_at").
+			Updates(variant).Error; err != nil {
+			return err
+		}
+		if err := tx.First(variant, variant.ID).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductVariant, variant.ID, domain.AuditActionUpdate, before, variant)
+	})
+	return err
+}
+
+func (r *productRepository) DeleteVariant(ctx context.Context, productID, variantID uint) error {
+	_, err := r.changeChildren(ctx, productID, false, func(tx *gorm.DB) error {
+		before, err := findVariant(tx, productID, variantID)
+		if err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&domain.BundleComponent{}).Where("variant_id = ?", variantID).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return ErrProductInBundle
+		}
+		if err := tx.Delete(before).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductVariant, variantID, domain.AuditActionDelete, before, nil)
+	})
+	return err
+}
+
+// findVariant memuat varian milik produk di dalam transaksi
+func findVariant(tx *gorm.DB, productID, variantID uint) (*domain.ProductVariant, error) {
+	var variant domain.ProductVariant
+	err := tx.Where("id = ? AND product_id = ?", variantID, productID).First(&variant).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrVariantNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &variant, nil
+}
+
+func (r *productRepository) GetModifierGroups(productID uint) ([]domain.ModifierGroup, error) {
+	var groups []domain.ModifierGroup
+	err := r.db.Preload("Options").Where("product_id = ?", productID).Order("id").Find(&groups).Error
+	return groups, err
+}
+
+func (r *productRepository) GetModifierGroupByID(productID, groupID uint) (*domain.ModifierGroup, error) {
+	var group domain.ModifierGroup
+	err := r.db.Preload("Options").Where("id = ? AND product_id = ?", groupID, productID).First(&group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrModifierGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// CreateModifierGroup creates the group together with its options.
+func (r *productRepository) CreateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	_, err := r.changeChildren(ctx, group.ProductID, false, func(tx *gorm.DB) error {
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityModifierGroup, group.ID, domain.AuditActionCreate, nil, group)
+	})
+	return err
+}
+
+// UpdateModifierGroup saves the group and replaces its options. Orders keep
+// a copy of the selected options, so recreating them is safe.
+func (r *productRepository) UpdateModifierGroup(ctx context.Context, group *domain.ModifierGroup) error {
+	_, err := r.changeChildren(ctx, group.ProductID, false, func(tx *gorm.DB) error {
+		before, err := findModifierGroup(tx, group.ProductID, group.ID)
+		if err != nil {
+			return err
+		}
+		group.CreatedAt = before.CreatedAt
+		if err := tx.Model(group).Select("name", "min_select", "max_select", "updated_at").Updates(group).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", group.ID).Delete(&domain.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		for i := range group.Options {
+			group.Options[i].ID = 0
+			group.Options[i].GroupID = group.ID
+		}
+		if err := tx.Create(&group.Options).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityModifierGroup, group.ID, domain.AuditActionUpdate, before, group)
+	})
+	return err
+}
+
+func (r *productRepository) DeleteModifierGroup(ctx context.Context, productID, groupID uint) error {
+	_, err := r.changeChildren(ctx, productID, false, func(tx *gorm.DB) error {
+		before, err := findModifierGroup(tx, productID, groupID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(before).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ?", groupID).Delete(&domain.ModifierOption{}).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityModifierGroup, groupID, domain.AuditActionDelete, before, nil)
+	})
+	return err
+}
+
+// findModifierGroup memuat modifier group milik produk beserta opsinya di dalam transaksi
+func findModifierGroup(tx *gorm.DB, productID, groupID uint) (*domain.ModifierGroup, error) {
+	var group domain.ModifierGroup
+	err := tx.Preload("Options").Where("id = ? AND product_id = ?", groupID, productID).First(&group).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrModifierGroupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &group, nil
+}
+
+// SetBundleComponents replaces the components of the product and makes it a
+// bundle; an empty list turns it back into a simple product. A product that is
+// itself a component of another bundle cannot become a bundle.
+func (r *productRepository) SetBundleComponents(ctx context.Context, productID uint, components []domain.BundleComponent) (*domain.Product, error) {
+	return r.changeChildren(ctx, productID, true, func(tx *gorm.DB) error {
+		if len(components) > 0 {
+			if err := checkNotComponent(tx, productID); err != nil {
+				return err
+			}
+			if err := lockComponents(tx, components); err != nil {
+				return err
+			}
+		}
+		if err := tx.Where("bundle_id = ?", productID).Delete(&domain.BundleComponent{}).Error; err != nil {
+			return err
+		}
+		productType := domain.ProductTypeSimple
+		if len(components) > 0 {
+			productType = domain.ProductTypeBundle
+			for i := range components {
+				components[i].ID = 0
+				components[i].BundleID = productID
+			}
+			if err := tx.Create(&components).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Model(&domain.Product{}).Where("id = ?", productID).Update("type", productType).Error
+	})
+}
+
+// checkNotComponent menolak perubahan yang tidak boleh dilakukan pada produk
+// yang menjadi komponen bundle lain (menjadi bundle, punya varian)
+func checkNotComponent(tx *gorm.DB, productID uint) error {
+	var count int64
+	if err := tx.Model(&domain.BundleComponent{}).Where("component_id = ?", productID).Count(&count).Error; err != nil {
 		return err
 	}
-	// Hapus data jika ditemukan
-	return r.db.Delete(&product).Error
+	if count > 0 {
+		return ErrProductInBundle
+	}
+	return nil
+}
+
+// lockComponents mengunci produk komponen lalu memeriksa ulang aturannya, supaya
+// komponen tidak menjadi bundle atau mendapat varian di antara validasi dan commit
+func lockComponents(tx *gorm.DB, components []domain.BundleComponent) error {
+	ids := make([]uint, len(components))
+	for i, component := range components {
+		ids[i] = component.ComponentID
+	}
+	var products []domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id IN ?", ids).Order("id").Find(&products).Error; err != nil {
+		return err
+	}
+	bundles := map[uint]bool{}
+	for _, product := range products {
+		bundles[product.ID] = product.Type == domain.ProductTypeBundle
+	}
+	var withVariants []uint
+	if err := tx.Model(&domain.ProductVariant{}).Where("product_id IN ?", ids).Distinct().Pluck("product_id", &withVariants).Error; err != nil {
+		return err
+	}
+	hasVariants := map[uint]bool{}
+	for _, id := range withVariants {
+		hasVariants[id] = true
+	}
+	for _, component := range components {
+		if bundles[component.ComponentID] || (component.VariantID == nil && hasVariants[component.ComponentID]) {
+			return ErrInvalidComponent
+		}
+	}
+	return nil
+}
+
+// FindSlug returns the current or old slug row of a product, or
+// ErrProductNotFound when no product ever had the slug.
+func (r *productRepository) FindSlug(slug string) (*domain.Slug, error) {
+	found, err := findSlug(r.db, domain.AggregateProduct, slug)
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		return nil, ErrProductNotFound
+	}
+	return found, nil
+}
+
+// BackfillSlugs gives a slug to products created before slugs existed.
+func (r *productRepository) BackfillSlugs() (int, error) {
+	count, err := backfillSlugs(r.db, domain.AggregateProduct)
+	if count > 0 {
+		r.invalidateCache()
+	}
+	return count, err
+}
+
+// SetTranslation sets or, when translation is nil, removes the translation
+// of a product for locale when the version matches (any version when zero).
+func (r *productRepository) SetTranslation(ctx context.Context, id uint, version uint, locale string, translation *domain.Translation) (*domain.Product, error) {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	// Kunci baris supaya pengecekan versi dan update atomik
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, id).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	if err := checkVersion(product.Version, version); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	before, err := productSnapshot(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	translations, err := withTranslation(product.Translations, locale, translation)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	product.Translations = translations
+	product.Version++
+	if err := tx.Select("translations", "version").Updates(&product).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Scopes(preloadProductChildren).First(&product, id).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductUpdated, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionUpdate, before, &product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache semua locale
+	r.invalidateCache()
+	return &product, nil
+}
+
+// SetTags replaces the tags of the product.
+func (r *productRepository) SetTags(ctx context.Context, productID uint, tagIDs []uint) (*domain.Product, error) {
+	return r.changeChildren(ctx, productID, true, func(tx *gorm.DB) error {
+		if err := tx.Where("product_id = ?", productID).Delete(&domain.ProductTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+		links := make([]domain.ProductTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			links[i] = domain.ProductTag{ProductID: productID, TagID: tagID}
+		}
+		return tx.Create(&links).Error
+	})
+}
+
+func (r *productRepository) RemoveTag(ctx context.Context, productID, tagID uint) (*domain.Product, error) {
+	return r.changeChildren(ctx, productID, true, func(tx *gorm.DB) error {
+		result := tx.Where("product_id = ? AND tag_id = ?", productID, tagID).Delete(&domain.ProductTag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrTagNotFound
+		}
+		return nil
+	})
+}
+
+func (r *productRepository) GetImages(productID uint) ([]domain.ProductImage, error) {
+	var images []domain.ProductImage
+	err := r.db.Scopes(orderImages).Where("product_id = ?", productID).Find(&images).Error
+	return images, err
+}
+
+func (r *productRepository) GetImageByID(productID, imageID uint) (*domain.ProductImage, error) {
+	var image domain.ProductImage
+	err := r.db.Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrImageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// CreateImage appends the image after the product's existing images.
+func (r *productRepository) CreateImage(ctx context.Context, image *domain.ProductImage) error {
+	_, err := r.changeChildren(ctx, image.ProductID, false, func(tx *gorm.DB) error {
+		var last struct{ Position *int }
+		if err := tx.Model(&domain.ProductImage{}).Select("MAX(position) AS position").
+			Where("product_id = ?", image.ProductID).Scan(&last).Error; err != nil {
+			return err
+		}
+		image.Position = 0
+		if last.Position != nil {
+			image.Position = *last.Position + 1
+		}
+		if err := tx.Create(image).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductImage, image.ID, domain.AuditActionCreate, nil, image)
+	})
+	return err
+}
+
+// DeleteImage deletes the image row and returns it so the caller can remove its files.
+func (r *productRepository) DeleteImage(ctx context.Context, productID, imageID uint) (*domain.ProductImage, error) {
+	var image domain.ProductImage
+	_, err := r.changeChildren(ctx, productID, false, func(tx *gorm.DB) error {
+		err := tx.Where("id = ? AND product_id = ?", imageID, productID).First(&image).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrImageNotFound
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&image).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityProductImage, image.ID, domain.AuditActionDelete, image, nil)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &image, nil
+}
+
+// ReorderImages sets each image's position to its index in imageIDs and
+// returns the images in their new order. The caller checks that imageIDs are
+// exactly the product's images.
+func (r *productRepository) ReorderImages(ctx context.Context, productID uint, imageIDs []uint) ([]domain.ProductImage, error) {
+	var images []domain.ProductImage
+	_, err := r.changeChildren(ctx, productID, false, func(tx *gorm.DB) error {
+		var before []domain.ProductImage
+		if err := tx.Where("product_id = ?", productID).Find(&before).Error; err != nil {
+			return err
+		}
+		for position, imageID := range imageIDs {
+			err := tx.Model(&domain.ProductImage{}).Where("id = ? AND product_id = ?", imageID, productID).
+				Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.Scopes(orderImages).Where("product_id = ?", productID).Find(&images).Error; err != nil {
+			return err
+		}
+		// Audit hanya mencatat gambar yang posisinya berubah
+		positions := map[uint]domain.ProductImage{}
+		for _, image := range before {
+			positions[image.ID] = image
+		}
+		for i := range images {
+			old := positions[images[i].ID]
+			if err := writeAudit(tx, domain.AuditEntityProductImage, images[i].ID, domain.AuditActionUpdate, &old, &images[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// deleteProductChildren menghapus varian, modifier group, opsi, komponen bundle dan gambar milik produk.
+// Produk yang masih menjadi komponen bundle lain tidak boleh dihapus. Baris gambar yang
+// dihapus dikembalikan agar file-nya bisa dihapus dari storage setelah commit.
+func deleteProductChildren(tx *gorm.DB, productID uint) ([]domain.ProductImage, error) {
+	var count int64
+	if err := tx.Model(&domain.BundleComponent{}).Where("component_id = ?", productID).Count(&count).Error; err != nil {
+		return nil, err
+	}
+	if count > 0 {
+		return nil, ErrProductInBundle
+	}
+	if err := tx.Where("product_id = ?", productID).Delete(&domain.ProductVariant{}).Error; err != nil {
+		return nil, err
+	}
+	groupIDs := tx.Model(&domain.ModifierGroup{}).Select("id").Where("product_id = ?", productID)
+	if err := tx.Where("group_id IN (?)", groupIDs).Delete(&domain.ModifierOption{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("product_id = ?", productID).Delete(&domain.ModifierGroup{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("bundle_id = ?", productID).Delete(&domain.BundleComponent{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("product_id = ?", productID).Delete(&domain.ProductTag{}).Error; err != nil {
+		return nil, err
+	}
+	if err := deleteSlugs(tx, domain.AggregateProduct, productID); err != nil {
+		return nil, err
+	}
+	var images []domain.ProductImage
+	if err := tx.Where("product_id = ?", productID).Find(&images).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("product_id = ?", productID).Delete(&domain.ProductImage{}).Error; err != nil {
+		return nil, err
+	}
+	return images, nil
+}
+
+// touchProducts menaikkan versi (ETag) produk yang ikut berubah karena data
+// turunannya berubah, lalu mencatat product.updated dengan data terbarunya
+func touchProducts(tx *gorm.DB, ids []uint) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	if err := tx.Model(&domain.Product{}).Where("id IN ?", ids).Update("version", gorm.Expr("version + 1")).Error; err != nil {
+		return err
+	}
+	var products []domain.Product
+	if err := tx.Scopes(preloadProductChildren).Where("id IN ?", ids).Order("id").Find(&products).Error; err != nil {
+		return err
+	}
+	for i := range products {
+		if err := writeOutbox(tx, domain.AggregateProduct, products[i].ID, domain.EventProductUpdated, products[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// changeChildren runs change on the product's variants, modifier groups,
+// images, tags or schedule with the product locked, then bumps its version,
+// records product.updated and returns the reloaded product. change records
+// the audit entries of the children it touches; with auditProduct the change
+// is recorded as an update of the product itself.
+func (r *productRepository) changeChildren(ctx context.Context, productID uint, auditProduct bool, change func(tx *gorm.DB) error) (*domain.Product, error) {
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+	var product domain.Product
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&product, productID).Error; err != nil {
+		tx.Rollback()
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProductNotFound
+		}
+		return nil, err
+	}
+	var before *domain.Product
+	if auditProduct {
+		var err error
+		if before, err = productSnapshot(tx, productID); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := change(tx); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	product.Version++
+	if err := tx.Model(&product).Update("version", product.Version).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if err := tx.Scopes(preloadProductChildren).First(&product, productID).Error; err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	// Catat event di outbox dalam transaksi yang sama
+	if err := writeOutbox(tx, domain.AggregateProduct, product.ID, domain.EventProductUpdated, product); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+	if auditProduct {
+		if err := writeAudit(tx, domain.AggregateProduct, product.ID, domain.AuditActionUpdate, before, &product); err != nil {
+			tx.Rollback()
+			return nil, err
+		}
+	}
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	// Hapus cache setelah varian atau modifier berubah
+	r.invalidateCache()
+	return &product, nil
+}
+
+// productSnapshot memuat produk beserta relasinya di dalam transaksi sebagai
+// kondisi sebelum atau sesudah perubahan untuk audit log
+func productSnapshot(tx *gorm.DB, id uint) (*domain.Product, error) {
+	var product domain.Product
+	if err := tx.Scopes(preloadProductChildren).First(&product, id).Error; err != nil {
+		return nil, err
+	}
+	return &product, nil
+}
+
+// auditProductUpdate membandingkan before dengan kondisi produk saat ini dan mencatatnya di audit log
+func auditProductUpdate(tx *gorm.DB, before *domain.Product) error {
+	after, err := productSnapshot(tx, before.ID)
+	if err != nil {
+		return err
+	}
+	return writeAudit(tx, domain.AggregateProduct, before.ID, domain.AuditActionUpdate, before, after)
+}
+
+// recordPriceChange stores a price set directly (create, update, import or
+// batch) as a history entry that is effective immediately.
+func recordPriceChange(tx *gorm.DB, productID uint, price float64) error {
+	return tx.Create(&domain.ProductPrice{ProductID: productID, Price: price, EffectiveFrom: time.Now(), Applied: true}).Error
+}
+
+// invalidateCache menghapus cache segera setelah commit. Jika gagal, consumer
+// cache di relay outbox akan menghapusnya kembali.
+func (r *productRepository) invalidateCache() {
+	_ = r.redis.Del(context.Background(), CacheKeysFor(domain.AggregateProduct)...).Err()
 }
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+type ReportRepository interface {
+	GetRevenueSeries(filter domain.ReportFilter) ([]domain.RevenuePoint, error)
+	GetTopProducts(filter domain.ReportFilter) ([]domain.ProductSales, error)
+	GetRevenueByCategory(filter domain.ReportFilter) ([]domain.CategorySales, error)
+	GetOrderValueSummary(filter domain.ReportFilter) (*domain.OrderValueSummary, error)
+}
+
+type reportRepository struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewReportRepository(db *gorm.DB, redis *redis.Client) ReportRepository {
+	return &reportRepository{db, redis}
+}
+
+const reportCacheTTL = 5 * time.Minute
+
+// GetRevenueSeries groups the revenue per period in SQL by joining the orders
+// to a derived table of the periods, so periods without orders are returned
+// with zero revenue. Period boundaries are computed in the requested timezone.
+func (r *reportRepository) GetRevenueSeries(filter domain.ReportFilter) ([]domain.RevenuePoint, error) {
+	var points []domain.RevenuePoint
+	err := r.cached("revenue", filter, &points, func() error {
+		points = []domain.RevenuePoint{}
+		buckets := domain.ReportBuckets(filter.From.In(filter.Location), filter.To.In(filter.Location), filter.Interval)
+		if len(buckets) == 0 {
+			return nil
+		}
+
+		selects := make([]string, len(buckets))
+		args := make([]interface{}, 0, len(buckets)*3)
+		for i, bucket := range buckets {
+			selects[i] = "SELECT ? AS period, ? AS starts_at, ? AS ends_at"
+			args = append(args, bucket.Period, bucket.Start, bucket.End)
+		}
+		err := r.db.Table("(?) AS buckets", gorm.Expr(strings.Join(selects, " UNION ALL "), args...)).
+			Joins("LEFT JOIN orders ON orders.order_date >= buckets.starts_at AND orders.order_date < buckets.ends_at AND orders.status <> ?",
+				domain.OrderStatusCancelled).
+			Select("buckets.period AS period, COUNT(orders.id) AS order_count, COALESCE(SUM(orders.total_price), 0) AS revenue").
+			Group("buckets.period, buckets.starts_at").
+			Order("buckets.starts_at").
+			Scan(&points).Error
+		if err != nil {
+			return err
+		}
+		for i := range points {
+			points[i].Revenue = domain.RoundMoney(points[i].Revenue)
+		}
+		return nil
+	})
+	return points, err
+}
+
+func (r *reportRepository) GetTopProducts(filter domain.ReportFilter) ([]domain.ProductSales, error) {
+	var products []domain.ProductSales
+	err := r.cached("top-products", filter, &products, func() error {
+		orderBy := "quantity DESC"
+		if filter.SortBy == "revenue" {
+			orderBy = "revenue DESC"
+		}
+		products = []domain.ProductSales{}
+		return r.details(filter).
+			Joins("JOIN products ON products.id = order_details.product_id").
+			Select("products.id AS product_id, products.name AS name, " +
+				"SUM(order_details.quantity) AS quantity, SUM(order_details.subtotal) AS revenue").
+			Group("products.id, products.name").
+			Order(orderBy).Limit(filter.Limit).
+			Scan(&products).Error
+	})
+	return products, err
+}
+
+func (r *reportRepository) GetRevenueByCategory(filter domain.ReportFilter) ([]domain.CategorySales, error) {
+	var categories []domain.CategorySales
+	err := r.cached("revenue-by-category", filter, &categories, func() error {
+		categories = []domain.CategorySales{}
+		return r.details(filter).
+			Joins("JOIN products ON products.id = order_details.product_id").
+			Joins("JOIN categories ON categories.id = products.category_id").
+			Select("categories.id AS category_id, categories.name AS name, " +
+				"SUM(order_details.quantity) AS quantity, SUM(order_details.subtotal) AS revenue").
+			Group("categories.id, categories.name").
+			Order("revenue DESC").
+			Scan(&categories).Error
+	})
+	return categories, err
+}
+
+func (r *reportRepository) GetOrderValueSummary(filter domain.ReportFilter) (*domain.OrderValueSummary, error) {
+	var summary domain.OrderValueSummary
+	err := r.cached("average-order-value", filter, &summary, func() error {
+		if err := r.orders(filter).
+			Select("COUNT(*) AS order_count, COALESCE(SUM(total_price), 0) AS revenue").
+			Scan(&summary).Error; err != nil {
+			return err
+		}
+		if summary.OrderCount > 0 {
+			summary.AverageOrderValue = domain.RoundMoney(summary.Revenue / float64(summary.OrderCount))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+// orders mengembalikan query order yang tidak dibatalkan dalam rentang filter
+func (r *reportRepository) orders(filter domain.ReportFilter) *gorm.DB {
+	return salesOrders(r.db, filter.From, filter.To)
+}
+
+// salesOrders adalah order yang dihitung sebagai penjualan pada [from, to):
+// semua order kecuali yang dibatalkan. Revenue-nya adalah SUM(total_price),
+// dipakai bersama oleh report dan ringkasan penjualan harian.
+func salesOrders(db *gorm.DB, from, to time.Time) *gorm.DB {
+	return db.Model(&domain.Order{}).
+		Where("order_date >= ? AND order_date < ? AND status <> ?", from, to, domain.OrderStatusCancelled)
+}
+
+// details mengembalikan baris penjualan dari order yang masuk filter. Detail
+// bundle diganti dengan komponennya beserta revenue yang dialokasikan, sehingga
+// order_details.product_id dan order_details.subtotal mengacu ke produk komponen.
+func (r *reportRepository) details(filter domain.ReportFilter) *gorm.DB {
+	simple := r.db.Model(&domain.OrderDetail{}).
+		Select("order_details.order_id, order_details.product_id, order_details.quantity, order_details.subtotal").
+		Where("NOT EXISTS (SELECT 1 FROM order_detail_components WHERE order_detail_components.order_detail_id = order_details.id)")
+	components := r.db.Model(&domain.OrderDetailComponent{}).
+		Joins("JOIN order_details ON order_details.id = order_detail_components.order_detail_id").
+		Select("order_details.order_id, order_detail_components.product_id, order_detail_components.quantity, " +
+			"order_detail_components.revenue AS subtotal")
+	return r.db.Table("(? UNION ALL ?) AS order_details", simple, components).
+		Joins("JOIN orders ON orders.id = order_details.order_id").
+		Where("orders.order_date >= ? AND orders.order_date < ? AND orders.status <> ?",
+			filter.From, filter.To, domain.OrderStatusCancelled)
+}
+
+// cached membaca hasil report dari Redis atau menjalankan load lalu menyimpannya
+func (r *reportRepository) cached(name string, filter domain.ReportFilter, dest interface{}, load func() error) error {
+	ctx := context.Background()
+	key := reportCacheKey(name, filter)
+
+	// Cek cache
+	if cachedData, err := r.redis.Get(ctx, key).Result(); err == nil {
+		if err := json.Unmarshal([]byte(cachedData), dest); err == nil {
+			return nil
+		}
+	}
+
+	// Jika cache tidak ada, hitung dari database
+	if err := load(); err != nil {
+		return err
+	}
+
+	// Simpan ke cache
+	data, _ := json.Marshal(dest)
+	_ = r.redis.Set(ctx, key, data, reportCacheTTL).Err()
+	return nil
+}
+
+func reportCacheKey(name string, filter domain.ReportFilter) string {
+	raw := fmt.Sprintf("%d|%d|%s|%s|%s|%d", filter.From.Unix(), filter.To.Unix(), filter.Location,
+		filter.Interval, filter.SortBy, filter.Limit)
+	sum := sha1.Sum([]byte(raw))
+	return "report:" + name + ":" + hex.EncodeToString(sum[:])
+}
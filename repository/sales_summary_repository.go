@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type SalesSummaryRepository interface {
+	BuildDailySummary(date time.Time) (*domain.DailySalesSummary, error)
+}
+
+type salesSummaryRepository struct {
+	db *gorm.DB
+}
+
+func NewSalesSummaryRepository(db *gorm.DB) SalesSummaryRepository {
+	return &salesSummaryRepository{db}
+}
+
+// BuildDailySummary aggregates the non-cancelled orders of date (local day) and
+// upserts the result, so running it twice for the same day is safe. Revenue is
+// the total price of those orders, the same figure the revenue report shows.
+func (r *salesSummaryRepository) BuildDailySummary(date time.Time) (*domain.DailySalesSummary, error) {
+	start := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, date.Location())
+	end := start.AddDate(0, 0, 1)
+
+	summary := domain.DailySalesSummary{Date: start}
+	err := salesOrders(r.db, start, end).
+		Select("COUNT(*) AS order_count, "+
+			"COALESCE(SUM(CASE WHEN status = ? THEN 1 ELSE 0 END), 0) AS paid_count, "+
+			"COALESCE(SUM(total_price), 0) AS revenue", domain.OrderStatusPaid).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.Model(&domain.OrderDetail{}).
+		Joins("JOIN orders ON orders.id = order_details.order_id").
+		Where("orders.order_date >= ? AND orders.order_date < ? AND orders.status <> ?", start, end, domain.OrderStatusCancelled).
+		Select("COALESCE(SUM(order_details.quantity), 0)").
+		Scan(&summary.ItemsSold).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// Upsert berdasarkan tanggal
+	err = r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "date"}},
+		DoUpdates: clause.AssignmentColumns([]string{"order_count", "paid_count", "revenue", "items_sold", "updated_at"}),
+	}).Create(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
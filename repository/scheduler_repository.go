@@ -0,0 +1,56 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SchedulerRepository interface {
+	CreateRun(run *domain.ScheduledJobRun) error
+	UpdateRun(run *domain.ScheduledJobRun) error
+	GetRunsByJob(jobName string, limit int) ([]domain.ScheduledJobRun, error)
+	GetLastRun(jobName string) (*domain.ScheduledJobRun, error)
+	PurgeRuns(before time.Time) (int64, error)
+}
+
+type schedulerRepository struct {
+	db *gorm.DB
+}
+
+func NewSchedulerRepository(db *gorm.DB) SchedulerRepository {
+	return &schedulerRepository{db}
+}
+
+func (r *schedulerRepository) CreateRun(run *domain.ScheduledJobRun) error {
+	return r.db.Create(run).Error
+}
+
+func (r *schedulerRepository) UpdateRun(run *domain.ScheduledJobRun) error {
+	return r.db.Save(run).Error
+}
+
+func (r *schedulerRepository) GetRunsByJob(jobName string, limit int) ([]domain.ScheduledJobRun, error) {
+	var runs []domain.ScheduledJobRun
+	err := r.db.Where("job_name = ?", jobName).Order("started_at desc").Limit(limit).Find(&runs).Error
+	return runs, err
+}
+
+func (r *schedulerRepository) GetLastRun(jobName string) (*domain.ScheduledJobRun, error) {
+	var run domain.ScheduledJobRun
+	err := r.db.Where("job_name = ?", jobName).Order("started_at desc").First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &run, nil
+}
+
+func (r *schedulerRepository) PurgeRuns(before time.Time) (int64, error) {
+	result := r.db.Where("started_at < ? AND status <> ?", before, domain.JobRunRunning).Delete(&domain.ScheduledJobRun{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,157 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/utils"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// assignSlug makes the slug derived from name the current slug of the entity
+// and writes it to the entity's slug column. An entity keeps its current slug
+// while the name still yields it, gets its own old slug back when renamed
+// back, and otherwise gets the first free slug among base, base-2, base-3...
+func assignSlug(tx *gorm.DB, entityType string, entityID uint, name string) (string, error) {
+	base := slugBase(entityType, name)
+
+	var taken []domain.Slug
+	err := tx.Where("entity_type = ? AND (slug = ? OR slug LIKE ?)", entityType, base, base+"-%").Find(&taken).Error
+	if err != nil {
+		return "", err
+	}
+	owners := map[string]domain.Slug{}
+	for _, slug := range taken {
+		owners[slug.Slug] = slug
+	}
+
+	slug := base
+	for n := 2; ; n++ {
+		owner, ok := owners[slug]
+		if !ok || owner.EntityID == entityID {
+			// Slug yang sedang dipakai dengan nama yang sama dipertahankan, termasuk varian -N
+			if current, found := currentSlug(taken, entityID); found && current != slug && isSuffixed(current, base) {
+				slug = current
+			}
+			break
+		}
+		slug = fmt.Sprintf("%s-%d", base, n)
+	}
+
+	if err := tx.Model(&domain.Slug{}).Where("entity_type = ? AND entity_id = ? AND slug <> ?", entityType, entityID, slug).
+		Update("current", false).Error; err != nil {
+		return "", err
+	}
+	if owner, ok := owners[slug]; ok {
+		if !owner.Current {
+			if err := tx.Model(&owner).Update("current", true).Error; err != nil {
+				return "", err
+			}
+		}
+	} else if err := tx.Create(&domain.Slug{EntityType: entityType, Slug: slug, EntityID: entityID, Current: true}).Error; err != nil {
+		return "", err
+	}
+
+	if err := tx.Table(slugTables[entityType]).Where("id = ?", entityID).Update("slug", slug).Error; err != nil {
+		return "", err
+	}
+	return slug, nil
+}
+
+// reservedSlugs adalah path statis di bawah /products dan /categories yang
+// akan tertukar dengan slug jika dipakai sebagai slug
+var reservedSlugs = map[string]bool{
+	"export": true,
+	"import": true,
+	"batch":  true,
+}
+
+// slugBase returns the slug name yields before de-duplication. Slugs that
+// would be read as an ID or collide with a static route are prefixed with
+// the entity type, e.g. product-123 and product-export.
+func slugBase(entityType, name string) string {
+	base := utils.Slugify(name)
+	if base == "" {
+		return entityType
+	}
+	if _, err := strconv.ParseUint(base, 10, 64); err == nil || reservedSlugs[base] {
+		return entityType + "-" + base
+	}
+	return base
+}
+
+// slugTables memetakan tipe entitas ke tabel yang menyimpan slug aktifnya
+var slugTables = map[string]string{
+	domain.AggregateCategory: "categories",
+	domain.AggregateProduct:  "products",
+}
+
+func currentSlug(slugs []domain.Slug, entityID uint) (string, bool) {
+	for _, slug := range slugs {
+		if slug.EntityID == entityID && slug.Current {
+			return slug.Slug, true
+		}
+	}
+	return "", false
+}
+
+// isSuffixed reports whether slug is base-N.
+func isSuffixed(slug, base string) bool {
+	suffix, ok := strings.CutPrefix(slug, base+"-")
+	if !ok || suffix == "" {
+		return false
+	}
+	for _, r := range suffix {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// findSlug returns the slug row of the entity type, or nil when the slug was never used.
+func findSlug(db *gorm.DB, entityType, slug string) (*domain.Slug, error) {
+	var found domain.Slug
+	err := db.Where("entity_type = ? AND slug = ?", entityType, slug).First(&found).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &found, nil
+}
+
+func deleteSlugs(tx *gorm.DB, entityType string, entityID uint) error {
+	return tx.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Delete(&domain.Slug{}).Error
+}
+
+// backfillSlugs memberi slug pada data lama yang dibuat sebelum kolom slug ada
+// atau yang slug-nya sudah menjadi slug terlarang
+func backfillSlugs(db *gorm.DB, entityType string) (int, error) {
+	var rows []struct {
+		ID   uint
+		Name string
+	}
+	reserved := make([]string, 0, len(reservedSlugs))
+	for slug := range reservedSlugs {
+		reserved = append(reserved, slug)
+	}
+	if err := db.Table(slugTables[entityType]).Select("id, name").Where("slug = '' OR slug IS NULL OR slug IN ?", reserved).Order("id").
+		Scan(&rows).Error; err != nil {
+		return 0, err
+	}
+	for _, row := range rows {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			_, err := assignSlug(tx, entityType, row.ID, row.Name)
+			return err
+		})
+		if err != nil {
+			return 0, err
+		}
+	}
+	return len(rows), nil
+}
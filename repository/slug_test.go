@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"testing"
+)
+
+func TestSlugBase(t *testing.T) {
+	tests := []struct {
+		entityType string
+		name       string
+		want       string
+	}{
+		{domain.AggregateProduct, "Es Kopi Susu", "es-kopi-susu"},
+		{domain.AggregateProduct, "!!!", "product"},
+		{domain.AggregateProduct, "123", "product-123"},
+		{domain.AggregateCategory, "2024", "category-2024"},
+		{domain.AggregateProduct, "Export", "product-export"},
+		{domain.AggregateProduct, "import", "product-import"},
+		{domain.AggregateCategory, "Batch", "category-batch"},
+		{domain.AggregateProduct, "Export Special", "export-special"},
+		{domain.AggregateProduct, "batch 2", "batch-2"},
+	}
+	for _, tt := range tests {
+		if got := slugBase(tt.entityType, tt.name); got != tt.want {
+			t.Errorf("slugBase(%q, %q) = %q, want %q", tt.entityType, tt.name, got, tt.want)
+		}
+	}
+}
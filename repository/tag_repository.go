@@ -0,0 +1,182 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrTagNotFound = errors.New("tag not found")
+
+type TagRepository interface {
+	CreateTag(ctx context.Context, tag *domain.Tag) error
+	GetTagCounts(filter domain.ProductFilter) ([]domain.TagCount, error)
+	GetTagByID(id uint) (*domain.Tag, error)
+	GetTagsByIDs(ids []uint) ([]domain.Tag, error)
+	IsTagNameUnique(name string, excludeID uint) (bool, error)
+	UpdateTag(ctx context.Context, tag *domain.Tag) error
+	DeleteTag(ctx context.Context, id uint) error
+}
+
+type tagRepository struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewTagRepository(db *gorm.DB, redis *redis.Client) TagRepository {
+	return &tagRepository{db, redis}
+}
+
+func (r *tagRepository) CreateTag(ctx context.Context, tag *domain.Tag) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(tag).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityTag, tag.ID, domain.AuditActionCreate, nil, tag)
+	})
+}
+
+// GetTagCounts returns every tag with the number of products matching filter
+// that have it, ordered by name. Tags without such products have a zero count.
+func (r *tagRepository) GetTagCounts(filter domain.ProductFilter) ([]domain.TagCount, error) {
+	return tagCounts(r.db, filter)
+}
+
+// tagCounts menghitung produk per tag; dipakai juga oleh facet produk
+func tagCounts(db *gorm.DB, filter domain.ProductFilter) ([]domain.TagCount, error) {
+	products := db.Model(&domain.Product{}).Select("products.id").Scopes(productFilterScope(filter))
+
+	var counts []domain.TagCount
+	err := db.Model(&domain.Tag{}).Select("tags.*, COUNT(product_tags.product_id) AS product_count").
+		Joins("LEFT JOIN product_tags ON product_tags.tag_id = tags.id AND product_tags.product_id IN (?)", products).
+		Group("tags.id").Order("tags.name").Scan(&counts).Error
+	return counts, err
+}
+
+func (r *tagRepository) GetTagByID(id uint) (*domain.Tag, error) {
+	var tag domain.Tag
+	if err := r.db.First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+func (r *tagRepository) GetTagsByIDs(ids []uint) ([]domain.Tag, error) {
+	var tags []domain.Tag
+	if len(ids) == 0 {
+		return tags, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&tags).Error
+	return tags, err
+}
+
+func (r *tagRepository) IsTagNameUnique(name string, excludeID uint) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.Tag{}).Where("name = ? AND id <> ?", name, excludeID).Count(&count).Error
+	return count == 0, err
+}
+
+// UpdateTag saves the tag name and reloads tag. Products with the tag get a
+// new version and a product.updated event, since their responses embed it.
+func (r *tagRepository) UpdateTag(ctx context.Context, tag *domain.Tag) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		before, err := lockTag(tx, tag.ID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Tag{}).Where("id = ?", tag.ID).Update("name", tag.Name).Error; err != nil {
+			return err
+		}
+		if err := tx.First(tag, tag.ID).Error; err != nil {
+			return err
+		}
+		productIDs, err := taggedProductIDs(tx, tag.ID)
+		if err != nil {
+			return err
+		}
+		if err := touchProducts(tx, productIDs); err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityTag, tag.ID, domain.AuditActionUpdate, before, tag)
+	})
+	if err != nil {
+		return err
+	}
+
+	// Nama tag ikut tampil di response produk
+	r.invalidateCache()
+	return nil
+}
+
+// DeleteTag removes the tag from every product and deletes it. Those
+// products get a new version and a product.updated event.
+func (r *tagRepository) DeleteTag(ctx context.Context, id uint) error {
+	// Mulai transaksi
+	tx := r.db.WithContext(ctx).Begin()
+	if tx.Error != nil {
+		return tx.Error
+	}
+	before, err := lockTag(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	productIDs, err := taggedProductIDs(tx, id)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Where("tag_id = ?", id).Delete(&domain.ProductTag{}).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := touchProducts(tx, productIDs); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Delete(before).Error; err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := writeAudit(tx, domain.AuditEntityTag, id, domain.AuditActionDelete, before, nil); err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return err
+	}
+
+	// Hapus cache produk karena tag sudah dilepas dari produknya
+	r.invalidateCache()
+	return nil
+}
+
+// lockTag memuat dan mengunci tag di dalam transaksi
+func lockTag(tx *gorm.DB, id uint) (*domain.Tag, error) {
+	var tag domain.Tag
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&tag, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTagNotFound
+		}
+		return nil, err
+	}
+	return &tag, nil
+}
+
+// taggedProductIDs mengembalikan ID produk yang memiliki tag
+func taggedProductIDs(tx *gorm.DB, tagID uint) ([]uint, error) {
+	var ids []uint
+	err := tx.Model(&domain.ProductTag{}).Where("tag_id = ?", tagID).Order("product_id").Pluck("product_id", &ids).Error
+	return ids, err
+}
+
+func (r *tagRepository) invalidateCache() {
+	_ = r.redis.Del(context.Background(), CacheKeysFor(domain.AggregateProduct)...).Err()
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"crud-clean-architecture/domain"
+	"errors"
+)
+
+var ErrTranslationNotFound = errors.New("translation not found")
+
+// withTranslation returns a copy of translations with locale set to
+// translation, or removed when translation is nil.
+func withTranslation(translations map[string]domain.Translation, locale string, translation *domain.Translation) (map[string]domain.Translation, error) {
+	updated := make(map[string]domain.Translation, len(translations)+1)
+	for key, value := range translations {
+		updated[key] = value
+	}
+	if translation == nil {
+		if _, ok := updated[locale]; !ok {
+			return nil, ErrTranslationNotFound
+		}
+		delete(updated, locale)
+		return updated, nil
+	}
+	updated[locale] = *translation
+	return updated, nil
+}
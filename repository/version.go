@@ -0,0 +1,14 @@
+package repository
+
+import "errors"
+
+var ErrVersionConflict = errors.New("resource was modified by another request")
+
+// checkVersion compares the locked row's version with the version the client
+// last saw. Zero means the client did not ask for a check (If-Match: *).
+func checkVersion(current, expected uint) error {
+	if expected != 0 && current != expected {
+		return ErrVersionConflict
+	}
+	return nil
+}
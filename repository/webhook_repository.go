@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrWebhookSubscriptionNotFound = errors.New("webhook subscription not found")
+	ErrWebhookDeliveryNotFound     = errors.New("webhook delivery not found")
+)
+
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	GetAllSubscriptions() ([]domain.WebhookSubscription, error)
+	GetSubscriptionByID(id uint) (*domain.WebhookSubscription, error)
+	GetSubscriptionsForEvent(eventType domain.EventType) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	CreateDeliveries(deliveries []domain.WebhookDelivery) (int, error)
+	ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error)
+	GetDeliveriesByStatus(status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error)
+	GetDeliveryByID(id uint) (*domain.WebhookDelivery, error)
+	UpdateDelivery(delivery *domain.WebhookDelivery) error
+	PurgeDelivered(before time.Time) (int64, error)
+}
+
+type webhookRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookRepository(db *gorm.DB) WebhookRepository {
+	return &webhookRepository{db}
+}
+
+func (r *webhookRepository) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(subscription).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityWebhookSubscription, subscription.ID, domain.AuditActionCreate, nil, subscription)
+	})
+}
+
+func (r *webhookRepository) GetAllSubscriptions() ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	err := r.db.Order("id").Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+func (r *webhookRepository) GetSubscriptionByID(id uint) (*domain.WebhookSubscription, error) {
+	var subscription domain.WebhookSubscription
+	if err := r.db.First(&subscription, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookSubscriptionNotFound
+		}
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+func (r *webhookRepository) GetSubscriptionsForEvent(eventType domain.EventType) ([]domain.WebhookSubscription, error) {
+	var subscriptions []domain.WebhookSubscription
+	if err := r.db.Where("active = ?", true).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+
+	// event_types disimpan sebagai JSON, jadi filter dilakukan di sini
+	matched := subscriptions[:0]
+	for _, subscription := range subscriptions {
+		if subscription.Subscribes(eventType) {
+			matched = append(matched, subscription)
+		}
+	}
+	return matched, nil
+}
+
+func (r *webhookRepository) DeleteSubscription(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var subscription domain.WebhookSubscription
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&subscription, id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrWebhookSubscriptionNotFound
+			}
+			return err
+		}
+		if err := tx.Delete(&subscription).Error; err != nil {
+			return err
+		}
+		return writeAudit(tx, domain.AuditEntityWebhookSubscription, id, domain.AuditActionDelete, &subscription, nil)
+	})
+}
+
+// CreateDeliveries inserts the deliveries and returns how many were new.
+// A delivery that already exists for the same subscription and event (the
+// outbox relay is at-least-once) is skipped by the unique index.
+func (r *webhookRepository) CreateDeliveries(deliveries []domain.WebhookDelivery) (int, error) {
+	created := 0
+	for i := range deliveries {
+		if err := r.db.Create(&deliveries[i]).Error; err != nil {
+			if isDuplicateKey(err) {
+				continue
+			}
+			return created, err
+		}
+		created++
+	}
+	return created, nil
+}
+
+// ClaimDueDeliveries locks up to limit due deliveries with SKIP LOCKED and
+// moves their next_attempt_at forward by lease, so other workers skip them
+// while they are sent. A worker that dies leaves them due again after the lease.
+func (r *webhookRepository) ClaimDueDeliveries(now time.Time, lease time.Duration, limit int) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", domain.WebhookDeliveryPending, now).
+			Order("next_attempt_at").Limit(limit).Find(&deliveries).Error; err != nil {
+			return err
+		}
+		if len(deliveries) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(deliveries))
+		for i := range deliveries {
+			ids[i] = deliveries[i].ID
+			deliveries[i].NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&domain.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", now.Add(lease)).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepository) GetDeliveriesByStatus(status domain.WebhookDeliveryStatus) ([]domain.WebhookDelivery, error) {
+	var deliveries []domain.WebhookDelivery
+	err := r.db.Where("status = ?", status).Order("id desc").Find(&deliveries).Error
+	return deliveries, err
+}
+
+func (r *webhookRepository) GetDeliveryByID(id uint) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	if err := r.db.First(&delivery, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookDeliveryNotFound
+		}
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+func (r *webhookRepository) UpdateDelivery(delivery *domain.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// isDuplicateKey cek error MySQL 1062 (duplicate entry pada unique index)
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
+
+func (r *webhookRepository) PurgeDelivered(before time.Time) (int64, error) {
+	result := r.db.Where("status = ? AND delivered_at < ?", domain.WebhookDeliveryDelivered, before).
+		Delete(&domain.WebhookDelivery{})
+	return result.RowsAffected, result.Error
+}
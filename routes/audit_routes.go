@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterAuditRoutes(r *gin.RouterGroup, handler *handler.AuditHandler) {
+	r.GET("/", handler.GetLogs)
+}
@@ -9,7 +9,12 @@ import (
 func RegisterCategoryRoutes(r *gin.RouterGroup, handler *handler.CategoryHandler) {
 	r.POST("/", handler.CreateCategory)
 	r.GET("/", handler.GetAllCategories)
+	r.POST("/batch", handler.ApplyBatch)
 	r.GET("/:id", handler.GetCategoryByID)
 	r.PUT("/:id", handler.UpdateCategory)
+	r.PATCH("/:id", handler.PatchCategory)
 	r.DELETE("/:id", handler.DeleteCategory)
+	r.PUT("/:id/attributes", handler.SetAttributeSchema)
+	r.PUT("/:id/translations/:locale", handler.SetTranslation)
+	r.DELETE("/:id/translations/:locale", handler.DeleteTranslation)
 }
@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterExportRoutes(r *gin.RouterGroup, handler *handler.ExportHandler) {
+	r.GET("/:id", handler.GetExport)
+	r.GET("/:id/download", handler.DownloadExport)
+}
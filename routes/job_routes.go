@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterJobRoutes(r *gin.RouterGroup, handler *handler.JobHandler) {
+	r.GET("/stats", handler.GetStats)
+	r.GET("/dead", handler.GetDeadJobs)
+	r.POST("/dead/:id/retry", handler.RetryDeadJob)
+	r.DELETE("/dead/:id", handler.DeleteDeadJob)
+}
@@ -6,9 +6,14 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterOrderRoutes(r *gin.RouterGroup, handler *handler.OrderHandler) {
+func RegisterOrderRoutes(r *gin.RouterGroup, handler *handler.OrderHandler, exportHandler *handler.ExportHandler) {
 	r.POST("/", handler.CreateOrder)
 	r.GET("/", handler.GetAllOrders)
+	r.GET("/export", exportHandler.ExportOrders)
 	r.GET("/:id", handler.GetOrderByID)
 	r.DELETE("/:id", handler.DeleteOrder)
+	r.POST("/:id/cancel", handler.CancelOrder)
+	r.POST("/:id/payments", handler.CreatePayment)
+	r.GET("/:id/payments", handler.GetOrderPayments)
+	r.PUT("/:id/payments/:payment_id/status", handler.UpdatePaymentStatus)
 }
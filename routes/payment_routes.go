@@ -0,0 +1,11 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterPaymentRoutes(r *gin.RouterGroup, handler *handler.PaymentHandler) {
+	r.POST("/webhooks/:provider", handler.ReceiveWebhook)
+}
@@ -6,10 +6,38 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
-func RegisterProductRoutes(r *gin.RouterGroup, handler *handler.ProductHandler) {
+func RegisterProductRoutes(r *gin.RouterGroup, handler *handler.ProductHandler, exportHandler *handler.ExportHandler,
+	importHandler *handler.ProductImportHandler, imageHandler *handler.ProductImageHandler) {
 	r.POST("/", handler.CreateProduct)
 	r.GET("/", handler.GetAllProducts)
+	r.POST("/batch", handler.ApplyBatch)
+	r.GET("/export", exportHandler.ExportProducts)
+	r.POST("/import", importHandler.ImportProducts)
 	r.GET("/:id", handler.GetProductByID)
 	r.PUT("/:id", handler.UpdateProduct)
+	r.PATCH("/:id", handler.PatchProduct)
 	r.DELETE("/:id", handler.DeleteProduct)
+	r.GET("/:id/prices", handler.GetPriceHistory)
+	r.POST("/:id/prices", handler.SchedulePrice)
+	r.DELETE("/:id/prices/:price_id", handler.CancelScheduledPrice)
+	r.GET("/:id/variants", handler.GetVariants)
+	r.POST("/:id/variants", handler.CreateVariant)
+	r.PUT("/:id/variants/:variant_id", handler.UpdateVariant)
+	r.DELETE("/:id/variants/:variant_id", handler.DeleteVariant)
+	r.GET("/:id/modifier-groups", handler.GetModifierGroups)
+	r.POST("/:id/modifier-groups", handler.CreateModifierGroup)
+	r.PUT("/:id/modifier-groups/:group_id", handler.UpdateModifierGroup)
+	r.DELETE("/:id/modifier-groups/:group_id", handler.DeleteModifierGroup)
+	r.PUT("/:id/components", handler.SetBundleComponents)
+	r.DELETE("/:id/components", handler.ClearBundleComponents)
+	r.PUT("/:id/tags", handler.SetTags)
+	r.DELETE("/:id/tags/:tag_id", handler.RemoveTag)
+	r.PUT("/:id/translations/:locale", handler.SetTranslation)
+	r.DELETE("/:id/translations/:locale", handler.DeleteTranslation)
+	r.PUT("/:id/availability", handler.SetAvailability)
+	r.DELETE("/:id/availability", handler.ClearAvailability)
+	r.GET("/:id/images", imageHandler.GetImages)
+	r.POST("/:id/images", imageHandler.UploadImage)
+	r.PUT("/:id/images/order", imageHandler.ReorderImages)
+	r.DELETE("/:id/images/:image_id", imageHandler.DeleteImage)
 }
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterReportRoutes(r *gin.RouterGroup, handler *handler.ReportHandler) {
+	r.GET("/revenue", handler.GetRevenue)
+	r.GET("/top-products", handler.GetTopProducts)
+	r.GET("/revenue-by-category", handler.GetRevenueByCategory)
+	r.GET("/average-order-value", handler.GetAverageOrderValue)
+}
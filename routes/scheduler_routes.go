@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterSchedulerRoutes(r *gin.RouterGroup, handler *handler.SchedulerHandler) {
+	r.GET("/jobs", handler.GetJobs)
+	r.GET("/jobs/:name/runs", handler.GetJobRuns)
+	r.POST("/jobs/:name/trigger", handler.TriggerJob)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterTagRoutes(r *gin.RouterGroup, handler *handler.TagHandler) {
+	r.POST("/", handler.CreateTag)
+	r.GET("/", handler.GetAllTags)
+	r.GET("/:id", handler.GetTagByID)
+	r.PUT("/:id", handler.UpdateTag)
+	r.DELETE("/:id", handler.DeleteTag)
+}
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"crud-clean-architecture/handler"
+
+	"github.com/gin-gonic/gin"
+)
+
+func RegisterWebhookRoutes(r *gin.RouterGroup, handler *handler.WebhookHandler) {
+	r.POST("/subscriptions", handler.CreateSubscription)
+	r.GET("/subscriptions", handler.GetAllSubscriptions)
+	r.DELETE("/subscriptions/:id", handler.DeleteSubscription)
+	r.GET("/dead-letters", handler.GetDeadLetters)
+	r.POST("/dead-letters/:id/retry", handler.RetryDelivery)
+}
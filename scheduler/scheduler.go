@@ -0,0 +1,273 @@
+package scheduler
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+)
+
+var (
+	ErrJobNotFound     = errors.New("scheduled job not found")
+	ErrJobRunning      = errors.New("scheduled job is already running")
+	ErrInvalidSchedule = errors.New("invalid cron expression")
+)
+
+// TaskFunc is the work of a scheduled job. The returned string is stored as run output.
+type TaskFunc func(ctx context.Context) (string, error)
+
+type job struct {
+	name        string
+	spec        string
+	description string
+	schedule    cron.Schedule
+	task        TaskFunc
+	lockTTL     time.Duration
+}
+
+// Scheduler runs registered jobs on standard 5-field cron expressions (plus
+// descriptors such as @daily). Each run takes a Redis lock so only one
+// instance executes a job at a time, and is recorded in MySQL.
+type Scheduler struct {
+	mu       sync.RWMutex
+	jobs     map[string]*job
+	redis    *redis.Client
+	repo     repository.SchedulerRepository
+	location *time.Location
+	instance string
+}
+
+func New(redis *redis.Client, repo repository.SchedulerRepository) *Scheduler {
+	hostname, _ := os.Hostname()
+	return &Scheduler{
+		jobs:     make(map[string]*job),
+		redis:    redis,
+		repo:     repo,
+		location: time.Local,
+		instance: fmt.Sprintf("%s-%d", hostname, os.Getpid()),
+	}
+}
+
+// Register adds a job. lockTTL must exceed the longest expected run; the lock
+// expires on its own if the instance dies mid-run.
+func (s *Scheduler) Register(name, spec, description string, lockTTL time.Duration, task TaskFunc) error {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return fmt.Errorf("%w %q for %s: %v", ErrInvalidSchedule, spec, name, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobs[name] = &job{
+		name:        name,
+		spec:        spec,
+		description: description,
+		schedule:    schedule,
+		task:        task,
+		lockTTL:     lockTTL,
+	}
+	return nil
+}
+
+// Start fires jobs on their schedule until ctx is cancelled.
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.RLock()
+	next := make(map[string]time.Time, len(s.jobs))
+	now := time.Now().In(s.location)
+	for name, j := range s.jobs {
+		next[name] = j.schedule.Next(now)
+	}
+	s.mu.RUnlock()
+
+	// Cron beresolusi menit; cek setiap detik cukup presisi
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case tick := <-ticker.C:
+			tick = tick.In(s.location)
+			s.mu.RLock()
+			for name, j := range s.jobs {
+				if tick.Before(next[name]) {
+					continue
+				}
+				fireAt := next[name]
+				next[name] = j.schedule.Next(tick)
+				go func(j *job) {
+					if _, err := s.run(ctx, j, fireAt); err != nil && !errors.Is(err, ErrJobRunning) {
+						log.Printf("scheduler: %s: %v", j.name, err)
+					}
+				}(j)
+			}
+			s.mu.RUnlock()
+		}
+	}
+}
+
+// Trigger runs a job now in the background and returns the started run.
+func (s *Scheduler) Trigger(name string) (*domain.ScheduledJobRun, error) {
+	s.mu.RLock()
+	j, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+
+	unlock, err := s.lock(context.Background(), j)
+	if err != nil {
+		return nil, err
+	}
+	run, err := s.startRun(j, domain.JobRunTriggerManual)
+	if err != nil {
+		unlock()
+		return nil, err
+	}
+	started := *run
+	go func() {
+		defer unlock()
+		s.execute(context.Background(), j, run)
+	}()
+	return &started, nil
+}
+
+// Jobs lists the registered jobs with their next fire time and last run.
+func (s *Scheduler) Jobs() ([]domain.ScheduledJob, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now().In(s.location)
+	jobs := make([]domain.ScheduledJob, 0, len(s.jobs))
+	for _, j := range s.jobs {
+		nextRun := j.schedule.Next(now)
+		lastRun, err := s.repo.GetLastRun(j.name)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, domain.ScheduledJob{
+			Name:        j.name,
+			Schedule:    j.spec,
+			Description: j.description,
+			NextRunAt:   &nextRun,
+			LastRun:     lastRun,
+		})
+	}
+	sort.Slice(jobs, func(a, b int) bool { return jobs[a].Name < jobs[b].Name })
+	return jobs, nil
+}
+
+// Runs returns the latest runs of a job.
+func (s *Scheduler) Runs(name string, limit int) ([]domain.ScheduledJobRun, error) {
+	s.mu.RLock()
+	_, ok := s.jobs[name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, ErrJobNotFound
+	}
+	return s.repo.GetRunsByJob(name, limit)
+}
+
+// run executes a scheduled firing. Every instance fires the same slots, so the
+// first one to claim fireAt runs it and the others skip.
+func (s *Scheduler) run(ctx context.Context, j *job, fireAt time.Time) (*domain.ScheduledJobRun, error) {
+	claimKey := fmt.Sprintf("scheduler:fired:%s:%d", j.name, fireAt.Unix())
+	claimed, err := s.redis.SetNX(ctx, claimKey, s.instance, 24*time.Hour).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrJobRunning
+	}
+
+	unlock, err := s.lock(ctx, j)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	run, err := s.startRun(j, domain.JobRunTriggerSchedule)
+	if err != nil {
+		return nil, err
+	}
+	s.execute(ctx, j, run)
+	return run, nil
+}
+
+func (s *Scheduler) startRun(j *job, trigger domain.JobRunTrigger) (*domain.ScheduledJobRun, error) {
+	run := &domain.ScheduledJobRun{
+		JobName:   j.name,
+		Trigger:   trigger,
+		Instance:  s.instance,
+		Status:    domain.JobRunRunning,
+		StartedAt: time.Now(),
+	}
+	if err := s.repo.CreateRun(run); err != nil {
+		return nil, err
+	}
+	return run, nil
+}
+
+func (s *Scheduler) execute(ctx context.Context, j *job, run *domain.ScheduledJobRun) {
+	ctx, cancel := context.WithTimeout(ctx, j.lockTTL)
+	defer cancel()
+
+	output, err := s.safeRun(ctx, j)
+
+	finishedAt := time.Now()
+	run.FinishedAt = &finishedAt
+	run.DurationMs = finishedAt.Sub(run.StartedAt).Milliseconds()
+	run.Output = output
+	run.Status = domain.JobRunSucceeded
+	if err != nil {
+		run.Status = domain.JobRunFailed
+		run.Error = err.Error()
+		log.Printf("scheduler: %s failed: %v", j.name, err)
+	}
+	if err := s.repo.UpdateRun(run); err != nil {
+		log.Printf("scheduler: failed to record run of %s: %v", j.name, err)
+	}
+}
+
+func (s *Scheduler) safeRun(ctx context.Context, j *job) (output string, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return j.task(ctx)
+}
+
+// unlockScript hanya menghapus lock milik instance ini
+var unlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[1]) == ARGV[1] then
+  return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+func (s *Scheduler) lock(ctx context.Context, j *job) (func(), error) {
+	key := "scheduler:lock:" + j.name
+	token := fmt.Sprintf("%s-%d", s.instance, time.Now().UnixNano())
+
+	ok, err := s.redis.SetNX(ctx, key, token, j.lockTTL).Result()
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrJobRunning
+	}
+	return func() {
+		_ = unlockScript.Run(context.Background(), s.redis, []string{key}, token).Err()
+	}, nil
+}
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"fmt"
+	"strings"
+)
+
+const maxAttributeStringLength = 255
+
+// validateAttributeSchema checks the rules of a schema that the binding tags
+// cannot express and converts it to attribute definitions.
+func validateAttributeSchema(form *domain.AttributeSchemaForm) ([]domain.AttributeDefinition, error) {
+	var schema []domain.AttributeDefinition
+	names := map[string]bool{}
+	for _, attribute := range form.Attributes {
+		if !domain.ValidAttributeName(attribute.Name) {
+			return nil, &ValidationError{Errors: map[string]string{"attributes": fmt.Sprintf(
+				"attribute name %q must start with a lowercase letter and contain only lowercase letters, digits and underscores", attribute.Name)}}
+		}
+		if names[attribute.Name] {
+			return nil, &ValidationError{Errors: map[string]string{"attributes": "attribute names must be unique"}}
+		}
+		names[attribute.Name] = true
+
+		if attribute.Type == domain.AttributeTypeEnum {
+			if len(attribute.Options) == 0 {
+				return nil, &ValidationError{Errors: map[string]string{"attributes": fmt.Sprintf("enum attribute %q must have options", attribute.Name)}}
+			}
+			options := map[string]bool{}
+			for _, option := range attribute.Options {
+				if options[option] {
+					return nil, &ValidationError{Errors: map[string]string{"attributes": fmt.Sprintf("options of %q must be unique", attribute.Name)}}
+				}
+				options[option] = true
+			}
+		} else if len(attribute.Options) > 0 {
+			return nil, &ValidationError{Errors: map[string]string{"attributes": fmt.Sprintf("only enum attributes can have options, %q is %s", attribute.Name, attribute.Type)}}
+		}
+
+		schema = append(schema, domain.AttributeDefinition{
+			Name:     attribute.Name,
+			Type:     attribute.Type,
+			Required: attribute.Required,
+			Options:  attribute.Options,
+		})
+	}
+	return schema, nil
+}
+
+// validateAttributes checks product attribute values against the schema of
+// their category. Null values are treated as absent and dropped. Errors are
+// keyed attributes.<name>.
+func validateAttributes(schema []domain.AttributeDefinition, values map[string]interface{}) (map[string]interface{}, map[string]string) {
+	errs := map[string]string{}
+	definitions := map[string]domain.AttributeDefinition{}
+	for _, definition := range schema {
+		definitions[definition.Name] = definition
+	}
+
+	var attributes map[string]interface{}
+	for name, value := range values {
+		if value == nil {
+			continue
+		}
+		definition, ok := definitions[name]
+		if !ok {
+			errs["attributes."+name] = fmt.Sprintf("%s is not an attribute of the category", name)
+			continue
+		}
+		if message := checkAttributeValue(definition, value); message != "" {
+			errs["attributes."+name] = message
+			continue
+		}
+		if attributes == nil {
+			attributes = map[string]interface{}{}
+		}
+		attributes[name] = value
+	}
+	for _, definition := range schema {
+		if _, ok := attributes[definition.Name]; definition.Required && !ok {
+			if _, invalid := errs["attributes."+definition.Name]; !invalid {
+				errs["attributes."+definition.Name] = fmt.Sprintf("%s is required", definition.Name)
+			}
+		}
+	}
+	return attributes, errs
+}
+
+// checkAttributeValue mengembalikan pesan error, kosong jika nilai sesuai tipe atribut
+func checkAttributeValue(definition domain.AttributeDefinition, value interface{}) string {
+	switch definition.Type {
+	case domain.AttributeTypeNumber:
+		if _, ok := value.(float64); !ok {
+			return fmt.Sprintf("%s must be a number", definition.Name)
+		}
+	case domain.AttributeTypeBoolean:
+		if _, ok := value.(bool); !ok {
+			return fmt.Sprintf("%s must be a boolean", definition.Name)
+		}
+	case domain.AttributeTypeEnum:
+		text, ok := value.(string)
+		if ok {
+			for _, option := range definition.Options {
+				if text == option {
+					return ""
+				}
+			}
+		}
+		return fmt.Sprintf("%s must be one of %s", definition.Name, strings.Join(definition.Options, ", "))
+	default:
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Sprintf("%s must be a string", definition.Name)
+		}
+		if len(text) > maxAttributeStringLength {
+			return fmt.Sprintf("%s exceeds maximum length of %d", definition.Name, maxAttributeStringLength)
+		}
+	}
+	return ""
+}
@@ -0,0 +1,54 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"time"
+)
+
+const defaultAuditLimit = 50
+
+// AuditService reads the audit log. Entries are written by the repositories
+// in the same transaction as the change they describe.
+type AuditService interface {
+	GetLogs(form *domain.AuditQueryForm) ([]domain.AuditLog, error)
+}
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo}
+}
+
+func (s *auditService) GetLogs(form *domain.AuditQueryForm) ([]domain.AuditLog, error) {
+	filter := domain.AuditFilter{
+		EntityType: form.EntityType,
+		EntityID:   form.EntityID,
+		Actor:      form.Actor,
+		Action:     form.Action,
+		RequestID:  form.RequestID,
+		BeforeID:   form.BeforeID,
+		Limit:      form.Limit,
+	}
+	if filter.Limit == 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if form.From != "" {
+		from, err := time.ParseInLocation("2006-01-02", form.From, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		filter.From = &from
+	}
+	if form.To != "" {
+		to, err := time.ParseInLocation("2006-01-02", form.To, time.Local)
+		if err != nil {
+			return nil, err
+		}
+		to = to.AddDate(0, 0, 1) // to inklusif
+		filter.To = &to
+	}
+	return s.auditRepo.GetLogs(filter)
+}
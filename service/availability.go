@@ -0,0 +1,70 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"fmt"
+	"time"
+)
+
+// validateAvailability checks the rules of a schedule that the binding tags
+// cannot express and converts it to the stored schedule with times as HH:MM.
+func validateAvailability(form *domain.AvailabilityForm) (*domain.AvailabilitySchedule, error) {
+	if _, err := time.LoadLocation(form.Timezone); err != nil {
+		return nil, &ValidationError{Errors: map[string]string{"timezone": "timezone must be a valid time zone"}}
+	}
+
+	schedule := &domain.AvailabilitySchedule{Timezone: form.Timezone}
+	errs := map[string]string{}
+	for i, window := range form.Windows {
+		field := fmt.Sprintf("windows[%d]", i)
+		if (window.StartTime == "") != (window.EndTime == "") {
+			errs[field] = "start_time and end_time must be set together"
+			continue
+		}
+		start, end := normalizeClock(window.StartTime), normalizeClock(window.EndTime)
+		if start != "" && start == end {
+			errs[field] = "end_time must differ from start_time"
+			continue
+		}
+		// Format YYYY-MM-DD bisa dibandingkan sebagai string
+		if window.StartDate != "" && window.EndDate != "" && window.EndDate < window.StartDate {
+			errs[field] = "end_date must not be before start_date"
+			continue
+		}
+		schedule.Windows = append(schedule.Windows, domain.AvailabilityWindow{
+			Days:      uniqueDays(window.Days),
+			StartTime: start,
+			EndTime:   end,
+			StartDate: window.StartDate,
+			EndDate:   window.EndDate,
+		})
+	}
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	return schedule, nil
+}
+
+// normalizeClock menulis ulang jam sebagai HH:MM, misalnya 9:00 menjadi 09:00
+func normalizeClock(clock string) string {
+	if clock == "" {
+		return ""
+	}
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return clock
+	}
+	return t.Format("15:04")
+}
+
+func uniqueDays(days []string) []string {
+	var unique []string
+	seen := map[string]bool{}
+	for _, day := range days {
+		if !seen[day] {
+			seen[day] = true
+			unique = append(unique, day)
+		}
+	}
+	return unique
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/utils"
+	"errors"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+var ErrBatchRejected = errors.New("batch was rejected, no operation was applied")
+
+// validateBatchOperation checks op with its binding rules plus the fields each
+// operation type needs.
+func validateBatchOperation(op interface{}, opType domain.BatchOp, id uint, hasData bool) map[string]string {
+	errs := map[string]string{}
+	if err := binding.Validator.ValidateStruct(op); err != nil {
+		errs = utils.FormatValidationErrors(err)
+	}
+	if opType != domain.BatchOpCreate && id == 0 {
+		errs["id"] = "id is required"
+	}
+	if opType != domain.BatchOpDelete && !hasData {
+		errs["data"] = "data is required"
+	}
+	return errs
+}
+
+// batchVacatedIDs returns the IDs updated or deleted by the batch. Their
+// stored names are replaced or removed, so a name check against the database
+// must not count them; clashes with the new names are checked within the batch.
+func batchVacatedIDs(n int, op func(i int) (domain.BatchOp, uint)) map[uint]bool {
+	vacated := map[uint]bool{}
+	for i := 0; i < n; i++ {
+		if opType, id := op(i); opType != domain.BatchOpCreate && id != 0 {
+			vacated[id] = true
+		}
+	}
+	return vacated
+}
+
+// rejectBatch marks the failing operations and every other one as rolled back.
+func rejectBatch(results []domain.BatchResult, failed map[int]map[string]string) ([]domain.BatchResult, error) {
+	for i := range results {
+		if errs, ok := failed[i]; ok {
+			results[i].Status = domain.BatchStatusFailed
+			results[i].Errors = errs
+		} else {
+			results[i].Status = domain.BatchStatusRolledBack
+		}
+	}
+	return results, ErrBatchRejected
+}
+
+// batchItemFailure converts a repository item error into the failed map of rejectBatch.
+func batchItemFailure(err error) (map[int]map[string]string, bool) {
+	var itemErr *repository.BatchItemError
+	if !errors.As(err, &itemErr) {
+		return nil, false
+	}
+	return map[int]map[string]string{itemErr.Index: {"error": itemErr.Err.Error()}}, true
+}
@@ -1,17 +1,24 @@
 package service
 
 import (
+	"context"
 	"crud-clean-architecture/domain"
 	"crud-clean-architecture/repository"
+	"fmt"
 )
 
 type CategoryService interface {
-	CreateCategory(category *domain.Category) error
-	GetAllCategories() ([]domain.Category, error)
+	CreateCategory(ctx context.Context, category *domain.Category) error
+	GetAllCategories(locale string) ([]domain.Category, error)
 	GetCategoryByID(id uint) (*domain.Category, error)
-	UpdateCategory(category *domain.Category) error
-	DeleteCategory(id uint) error
+	ResolveCategory(idOrSlug string) (uint, string, error)
+	UpdateCategory(ctx context.Context, category *domain.Category) error
+	DeleteCategory(ctx context.Context, id uint, version uint) error
+	PatchCategory(ctx context.Context, id uint, version uint, patch []byte) (*domain.Category, error)
 	IsCategoryNameUnique(name string) (bool, error)
+	ApplyBatch(ctx context.Context, ops []domain.CategoryBatchOperation) ([]domain.BatchResult, error)
+	SetAttributeSchema(ctx context.Context, id uint, version uint, form *domain.AttributeSchemaForm) (*domain.Category, error)
+	SetTranslation(ctx context.Context, id uint, version uint, locale string, form *domain.TranslationForm) (*domain.Category, error)
 }
 
 type categoryService struct {
@@ -22,25 +29,158 @@ func NewCategoryService(categoryRepo repository.CategoryRepository) CategoryServ
 	return &categoryService{categoryRepo}
 }
 
-func (s *categoryService) CreateCategory(category *domain.Category) error {
-	return s.categoryRepo.CreateCategory(category)
+func (s *categoryService) CreateCategory(ctx context.Context, category *domain.Category) error {
+	return s.categoryRepo.CreateCategory(ctx, category)
 }
 
 func (s *categoryService) IsCategoryNameUnique(name string) (bool, error) {
 	return s.categoryRepo.IsCategoryNameUnique(name)
 }
-func (s *categoryService) GetAllCategories() ([]domain.Category, error) {
-	return s.categoryRepo.GetAllCategories()
+func (s *categoryService) GetAllCategories(locale string) ([]domain.Category, error) {
+	return s.categoryRepo.GetAllCategories(locale)
 }
 
 func (s *categoryService) GetCategoryByID(id uint) (*domain.Category, error) {
 	return s.categoryRepo.GetCategoryByID(id)
 }
 
-func (s *categoryService) UpdateCategory(category *domain.Category) error {
-	return s.categoryRepo.UpdateCategory(category)
+// ResolveCategory returns the ID of the category named by an ID or slug, and
+// the current slug when idOrSlug is one of its old slugs.
+func (s *categoryService) ResolveCategory(idOrSlug string) (uint, string, error) {
+	return resolveSlug(idOrSlug, s.categoryRepo.FindSlug, func(id uint) (string, error) {
+		category, err := s.categoryRepo.GetCategoryByID(id)
+		if err != nil {
+			return "", repository.ErrCategoryNotFound
+		}
+		return category.Slug, nil
+	})
 }
 
-func (s *categoryService) DeleteCategory(id uint) error {
-	return s.categoryRepo.DeleteCategory(id)
+func (s *categoryService) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	// Nama harus unik; nama kategori itu sendiri boleh dipakai lagi
+	existing, err := s.categoryRepo.GetCategoryByName(category.Name)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != category.ID {
+		return &ValidationError{Errors: map[string]string{"name": "name must be unique"}}
+	}
+	return s.categoryRepo.UpdateCategory(ctx, category)
+}
+
+func (s *categoryService) DeleteCategory(ctx context.Context, id uint, version uint) error {
+	return s.categoryRepo.DeleteCategory(ctx, id, version)
+}
+
+// PatchCategory applies a JSON merge patch, validates the merged category with
+// the CategoryForm rules and saves only the columns that changed.
+func (s *categoryService) PatchCategory(ctx context.Context, id uint, version uint, patch []byte) (*domain.Category, error) {
+	current, err := s.categoryRepo.GetCategoryByID(id)
+	if err != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+
+	var merged domain.CategoryForm
+	form := domain.CategoryForm{Name: current.Name, Description: current.Description}
+	if err := applyMergePatch(form, patch, &merged); err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{}
+	if merged.Name != current.Name {
+		isUnique, err := s.categoryRepo.IsCategoryNameUnique(merged.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !isUnique {
+			return nil, &ValidationError{Errors: map[string]string{"name": "name must be unique"}}
+		}
+		changes["name"] = merged.Name
+	}
+	if merged.Description != current.Description {
+		changes["description"] = merged.Description
+	}
+
+	return s.categoryRepo.PatchCategory(ctx, id, version, changes)
+}
+
+// SetAttributeSchema replaces the attributes products of the category may
+// (or must) have.
+func (s *categoryService) SetAttributeSchema(ctx context.Context, id uint, version uint, form *domain.AttributeSchemaForm) (*domain.Category, error) {
+	if _, err := s.categoryRepo.GetCategoryByID(id); err != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+	schema, err := validateAttributeSchema(form)
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryRepo.SetAttributeSchema(ctx, id, version, schema)
+}
+
+// SetTranslation sets the name of the category in locale; a nil form removes
+// the translation.
+func (s *categoryService) SetTranslation(ctx context.Context, id uint, version uint, locale string, form *domain.TranslationForm) (*domain.Category, error) {
+	if _, err := s.categoryRepo.GetCategoryByID(id); err != nil {
+		return nil, repository.ErrCategoryNotFound
+	}
+	translation, err := checkTranslation(locale, form)
+	if err != nil {
+		return nil, err
+	}
+	return s.categoryRepo.SetTranslation(ctx, id, version, locale, translation)
+}
+
+// ApplyBatch validates every operation first and then applies them all in one
+// transaction. When any operation is invalid or fails, nothing is applied and
+// ErrBatchRejected is returned together with the per-item results.
+func (s *categoryService) ApplyBatch(ctx context.Context, ops []domain.CategoryBatchOperation) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(ops))
+	failed := map[int]map[string]string{}
+	claimed := map[string]int{}
+	vacated := batchVacatedIDs(len(ops), func(i int) (domain.BatchOp, uint) { return ops[i].Op, ops[i].ID })
+
+	for i, op := range ops {
+		results[i] = domain.BatchResult{Index: i, Op: op.Op, ID: op.ID}
+		errs := validateBatchOperation(&op, op.Op, op.ID, op.Data != nil)
+
+		// Keunikan nama dicek seperti pada POST /categories untuk create dan update,
+		// termasuk antar item di batch
+		if len(errs) == 0 && op.Op != domain.BatchOpDelete {
+			if index, ok := claimed[op.Data.Name]; ok {
+				errs["name"] = fmt.Sprintf("name is duplicated in operation %d", index)
+			} else {
+				claimed[op.Data.Name] = i
+				existing, err := s.categoryRepo.GetCategoryByName(op.Data.Name)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil && existing.ID != op.ID && !vacated[existing.ID] {
+					errs["name"] = "name must be unique"
+				}
+			}
+		}
+		if len(errs) > 0 {
+			failed[i] = errs
+		}
+	}
+	if len(failed) > 0 {
+		return rejectBatch(results, failed)
+	}
+
+	categories, err := s.categoryRepo.ApplyBatch(ctx, ops)
+	if err != nil {
+		if failed, ok := batchItemFailure(err); ok {
+			return rejectBatch(results, failed)
+		}
+		return nil, err
+	}
+
+	for i, category := range categories {
+		results[i].ID = category.ID
+		results[i].Status = domain.BatchStatusOK
+		if ops[i].Op != domain.BatchOpDelete {
+			results[i].Data = category
+		}
+	}
+	return results, nil
 }
@@ -0,0 +1,34 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"testing"
+)
+
+func (r *memoryCategoryRepository) UpdateCategory(ctx context.Context, category *domain.Category) error {
+	copied := *category
+	r.categories[category.ID] = &copied
+	return nil
+}
+
+func TestUpdateCategoryNameUniqueness(t *testing.T) {
+	tests := map[string]bool{"Minuman": true, "Minuman Dingin": true, "Makanan": false}
+	for name, wantUnique := range tests {
+		categoryRepo := newMemoryCategoryRepository(domain.Category{ID: 1, Name: "Minuman"}, domain.Category{ID: 2, Name: "Makanan"})
+		s := NewCategoryService(categoryRepo)
+
+		err := s.UpdateCategory(context.Background(), &domain.Category{ID: 1, Name: name})
+		if wantUnique {
+			if err != nil {
+				t.Errorf("%s: expected update to succeed, got %v", name, err)
+			}
+			continue
+		}
+		var validationErr *ValidationError
+		if !errors.As(err, &validationErr) || validationErr.Errors["name"] != "name must be unique" {
+			t.Errorf("%s: expected name must be unique, got %v", name, err)
+		}
+	}
+}
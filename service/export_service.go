@@ -0,0 +1,291 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/export"
+	"crud-clean-architecture/queue"
+	"crud-clean-architecture/repository"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// TypeExport is the queue job type that writes a background export to disk.
+const TypeExport = "export.run"
+
+const (
+	exportPageSize = 500
+	// Export dengan baris lebih dari batas ini otomatis dijalankan di background
+	exportSyncLimit = 10000
+)
+
+var ErrExportNotReady = errors.New("export is not ready yet")
+
+type ExportPayload struct {
+	ExportID string `json:"export_id"`
+}
+
+type ExportService interface {
+	ShouldRunAsync(exp *domain.Export) (bool, error)
+	Stream(ctx context.Context, w io.Writer, exp *domain.Export) (int, error)
+	StartExport(ctx context.Context, exp *domain.Export) error
+	RunExport(ctx context.Context, id string) error
+	GetExport(id string) (*domain.Export, error)
+	FilePath(exp *domain.Export) (string, error)
+	PurgeFiles(before time.Time) (int, error)
+}
+
+type exportService struct {
+	orderRepo   repository.OrderRepository
+	productRepo repository.ProductRepository
+	exportRepo  repository.ExportRepository
+	queue       *queue.Queue
+	dir         string
+}
+
+// NewExportService creates the export service; background exports are written to dir.
+func NewExportService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository,
+	exportRepo repository.ExportRepository, queue *queue.Queue, dir string) ExportService {
+	return &exportService{orderRepo, productRepo, exportRepo, queue, dir}
+}
+
+func (s *exportService) ShouldRunAsync(exp *domain.Export) (bool, error) {
+	var count int64
+	var err error
+	if exp.Type == domain.ExportTypeOrders {
+		count, err = s.orderRepo.CountOrders(exp.OrderFilter)
+	} else {
+		count, err = s.productRepo.CountProducts(exp.ProductFilter)
+	}
+	return count > exportSyncLimit, err
+}
+
+// Stream writes the export to w page by page and returns the number of data rows.
+func (s *exportService) Stream(ctx context.Context, w io.Writer, exp *domain.Export) (int, error) {
+	writer, err := export.NewWriter(w, exp.Format, string(exp.Type))
+	if err != nil {
+		return 0, err
+	}
+
+	var rows int
+	if exp.Type == domain.ExportTypeOrders {
+		rows, err = s.writeOrders(ctx, writer, exp.OrderFilter)
+	} else {
+		rows, err = s.writeProducts(ctx, writer, exp.ProductFilter)
+	}
+	if err != nil {
+		return rows, err
+	}
+	return rows, writer.Close()
+}
+
+// writeOrders menulis satu baris per item order; order tanpa item tetap ditulis satu baris
+func (s *exportService) writeOrders(ctx context.Context, w export.Writer, filter domain.OrderFilter) (int, error) {
+	if err := w.WriteRow("order_id", "invoice_number", "order_date", "status", "total_price", "paid_amount",
+		"product_id", "product_name", "quantity", "unit_price", "subtotal"); err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return rows, err
+		}
+		orders, err := s.orderRepo.GetOrdersPage(filter, afterID, exportPageSize)
+		if err != nil {
+			return rows, err
+		}
+
+		for _, order := range orders {
+			orderCells := []interface{}{order.ID, order.InvoiceNumber, order.OrderDate.Format(time.RFC3339),
+				string(order.Status), order.TotalPrice, order.PaidAmount}
+			if len(order.Details) == 0 {
+				if err := w.WriteRow(orderCells...); err != nil {
+					return rows, err
+				}
+				rows++
+				continue
+			}
+			for _, detail := range order.Details {
+				unitPrice := 0.0
+				if detail.Quantity > 0 {
+					unitPrice = domain.RoundMoney(detail.Subtotal / float64(detail.Quantity))
+				}
+				cells := append(orderCells[:len(orderCells):len(orderCells)],
+					detail.ProductID, detail.Product.Name, detail.Quantity, unitPrice, detail.Subtotal)
+				if err := w.WriteRow(cells...); err != nil {
+					return rows, err
+				}
+				rows++
+			}
+		}
+
+		if len(orders) < exportPageSize {
+			return rows, nil
+		}
+		afterID = orders[len(orders)-1].ID
+	}
+}
+
+func (s *exportService) writeProducts(ctx context.Context, w export.Writer, filter domain.ProductFilter) (int, error) {
+	if err := w.WriteRow("id", "name", "category_id", "category", "price"); err != nil {
+		return 0, err
+	}
+	// Jadwal ketersediaan dievaluasi sekali untuk semua halaman
+	if err := s.productRepo.ResolveAvailability(&filter); err != nil {
+		return 0, err
+	}
+
+	rows := 0
+	var afterID uint
+	for {
+		if err := ctx.Err(); err != nil {
+			return rows, err
+		}
+		products, err := s.productRepo.GetProductsPage(filter, afterID, exportPageSize)
+		if err != nil {
+			return rows, err
+		}
+
+		for _, product := range products {
+			if err := w.WriteRow(product.ID, product.Name, product.CategoryID, product.Category.Name, product.Price); err != nil {
+				return rows, err
+			}
+			rows++
+		}
+
+		if len(products) < exportPageSize {
+			return rows, nil
+		}
+		afterID = products[len(products)-1].ID
+	}
+}
+
+// StartExport stores the export as pending and queues it for the worker.
+func (s *exportService) StartExport(ctx context.Context, exp *domain.Export) error {
+	exp.ID = newExportID()
+	exp.Status = domain.ExportStatusPending
+	exp.FileName = ExportFileName(exp)
+	exp.CreatedAt = time.Now()
+	if err := s.exportRepo.SaveExport(exp); err != nil {
+		return err
+	}
+
+	_, err := s.queue.Enqueue(ctx, TypeExport, ExportPayload{ExportID: exp.ID})
+	return err
+}
+
+// RunExport writes a queued export to disk and records the outcome.
+func (s *exportService) RunExport(ctx context.Context, id string) error {
+	exp, err := s.exportRepo.GetExport(id)
+	if err != nil {
+		return err
+	}
+
+	rows, err := s.writeFile(ctx, exp)
+	if err != nil {
+		exp.Status = domain.ExportStatusFailed
+		exp.Error = err.Error()
+		_ = s.exportRepo.SaveExport(exp)
+		return err
+	}
+
+	now := time.Now()
+	exp.Status = domain.ExportStatusCompleted
+	exp.Rows = rows
+	exp.Error = ""
+	exp.CompletedAt = &now
+	return s.exportRepo.SaveExport(exp)
+}
+
+func (s *exportService) writeFile(ctx context.Context, exp *domain.Export) (int, error) {
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return 0, err
+	}
+
+	// Tulis ke file sementara lalu rename supaya download tidak pernah membaca file setengah jadi
+	path := s.path(exp)
+	tmp, err := os.CreateTemp(s.dir, exp.ID+"-*.tmp")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+
+	rows, err := s.Stream(ctx, tmp, exp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return rows, err
+	}
+	return rows, os.Rename(tmp.Name(), path)
+}
+
+func (s *exportService) GetExport(id string) (*domain.Export, error) {
+	return s.exportRepo.GetExport(id)
+}
+
+// FilePath returns the file of a completed export.
+func (s *exportService) FilePath(exp *domain.Export) (string, error) {
+	if exp.Status != domain.ExportStatusCompleted {
+		return "", ErrExportNotReady
+	}
+	path := s.path(exp)
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("export file: %w", err)
+	}
+	return path, nil
+}
+
+// PurgeFiles removes export files, including ones left behind by a failed
+// run, last modified before the given time.
+func (s *exportService) PurgeFiles(before time.Time) (int, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, entry := range entries {
+		// Hanya file yang ditulis export: <id>.csv, <id>.xlsx dan <id>-*.tmp
+		switch filepath.Ext(entry.Name()) {
+		case "." + export.FormatCSV, "." + export.FormatXLSX, ".tmp":
+		default:
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.Mode().IsRegular() || !info.ModTime().Before(before) {
+			continue
+		}
+		if err := os.Remove(filepath.Join(s.dir, entry.Name())); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
+
+func (s *exportService) path(exp *domain.Export) string {
+	return filepath.Join(s.dir, exp.ID+"."+exp.Format)
+}
+
+// ExportFileName is the download name, e.g. orders-20240131-150405.csv.
+func ExportFileName(exp *domain.Export) string {
+	return fmt.Sprintf("%s-%s.%s", exp.Type, time.Now().Format("20060102-150405"), exp.Format)
+}
+
+func newExportID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,60 @@
+package service
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestExportPurgeFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	old := now.Add(-25 * time.Hour)
+
+	files := map[string]time.Time{
+		"old.csv":         old,
+		"old.xlsx":        old,
+		"old-123.tmp":     old,
+		"fresh.csv":       now,
+		"notes.txt":       old, // bukan file export
+		"keep/nested.csv": old, // isi subfolder tidak disentuh
+	}
+	for name, modTime := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s := NewExportService(nil, nil, nil, nil, dir)
+	removed, err := s.PurgeFiles(now.Add(-24 * time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if removed != 3 {
+		t.Fatalf("expected 3 files removed, got %d", removed)
+	}
+	for name := range files {
+		_, err := os.Stat(filepath.Join(dir, name))
+		gone := os.IsNotExist(err)
+		wantGone := name == "old.csv" || name == "old.xlsx" || name == "old-123.tmp"
+		if gone != wantGone {
+			t.Errorf("%s: removed = %v, want %v", name, gone, wantGone)
+		}
+	}
+}
+
+func TestExportPurgeFilesMissingDir(t *testing.T) {
+	s := NewExportService(nil, nil, nil, nil, filepath.Join(t.TempDir(), "missing"))
+	removed, err := s.PurgeFiles(time.Now())
+	if err != nil || removed != 0 {
+		t.Fatalf("expected nothing removed without error, got %d, %v", removed, err)
+	}
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/queue"
+)
+
+type JobService interface {
+	GetStats() (*queue.Stats, error)
+	GetDeadJobs(limit int64) ([]queue.Job, error)
+	RetryDeadJob(id string) (*queue.Job, error)
+	DeleteDeadJob(id string) error
+}
+
+type jobService struct {
+	queue *queue.Queue
+}
+
+func NewJobService(queue *queue.Queue) JobService {
+	return &jobService{queue}
+}
+
+func (s *jobService) GetStats() (*queue.Stats, error) {
+	return s.queue.Stats(context.Background())
+}
+
+func (s *jobService) GetDeadJobs(limit int64) ([]queue.Job, error) {
+	return s.queue.DeadJobs(context.Background(), limit)
+}
+
+func (s *jobService) RetryDeadJob(id string) (*queue.Job, error) {
+	return s.queue.RetryDead(context.Background(), id)
+}
+
+func (s *jobService) DeleteDeadJob(id string) error {
+	return s.queue.DeleteDead(context.Background(), id)
+}
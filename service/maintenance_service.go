@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"fmt"
+	"log"
+	"time"
+)
+
+// MaintenanceService holds the recurring housekeeping tasks run by the scheduler.
+type MaintenanceService interface {
+	BuildDailySalesSummary(date time.Time) (string, error)
+	ExpireStaleOrders(ctx context.Context, maxAge time.Duration) (string, error)
+	PurgeOldRecords(retention time.Duration) (string, error)
+	PurgeExportFiles() (string, error)
+	WarmCache() (string, error)
+	ApplyScheduledPrices(ctx context.Context) (string, error)
+}
+
+type maintenanceService struct {
+	orderService    OrderService
+	productService  ProductService
+	categoryService CategoryService
+	exportService   ExportService
+	orderRepo       repository.OrderRepository
+	summaryRepo     repository.SalesSummaryRepository
+	outboxRepo      repository.OutboxRepository
+	webhookRepo     repository.WebhookRepository
+	schedulerRepo   repository.SchedulerRepository
+}
+
+func NewMaintenanceService(orderService OrderService, productService ProductService, categoryService CategoryService,
+	exportService ExportService, orderRepo repository.OrderRepository, summaryRepo repository.SalesSummaryRepository, outboxRepo repository.OutboxRepository,
+	webhookRepo repository.WebhookRepository, schedulerRepo repository.SchedulerRepository) MaintenanceService {
+	return &maintenanceService{orderService, productService, categoryService, exportService, orderRepo, summaryRepo, outboxRepo, webhookRepo, schedulerRepo}
+}
+
+func (s *maintenanceService) BuildDailySalesSummary(date time.Time) (string, error) {
+	summary, err := s.summaryRepo.BuildDailySummary(date)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s: %d orders, %d paid, revenue %.2f, %d items",
+		summary.Date.Format("2006-01-02"), summary.OrderCount, summary.PaidCount, summary.Revenue, summary.ItemsSold), nil
+}
+
+func (s *maintenanceService) ExpireStaleOrders(ctx context.Context, maxAge time.Duration) (string, error) {
+	ids, err := s.orderRepo.GetStalePendingOrderIDs(time.Now().Add(-maxAge), 500)
+	if err != nil {
+		return "", err
+	}
+
+	// Batalkan lewat orderService supaya event order.cancelled ikut tercatat
+	cancelled := 0
+	for _, id := range ids {
+		if _, err := s.orderService.CancelOrder(ctx, id); err != nil {
+			log.Printf("maintenance: failed to cancel stale order %d: %v", id, err)
+			continue
+		}
+		cancelled++
+	}
+	return fmt.Sprintf("cancelled %d of %d stale pending orders", cancelled, len(ids)), nil
+}
+
+// PurgeOldRecords removes bookkeeping rows that are finished and older than
+// retention: published outbox messages, delivered webhooks and job run history.
+//
+// It does not purge soft-deleted rows: categories, products, tags and orders
+// are hard-deleted (the deleted state is kept in the audit log), so there are
+// none. If an entity gains a deleted_at column, its purge belongs here.
+func (s *maintenanceService) PurgeOldRecords(retention time.Duration) (string, error) {
+	before := time.Now().Add(-retention)
+
+	outbox, err := s.outboxRepo.PurgePublished(before)
+	if err != nil {
+		return "", err
+	}
+	deliveries, err := s.webhookRepo.PurgeDelivered(before)
+	if err != nil {
+		return "", err
+	}
+	runs, err := s.schedulerRepo.PurgeRuns(before)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("purged %d outbox messages, %d webhook deliveries, %d job runs", outbox, deliveries, runs), nil
+}
+
+// PurgeExportFiles removes background export files whose download link has
+// expired.
+func (s *maintenanceService) PurgeExportFiles() (string, error) {
+	removed, err := s.exportService.PurgeFiles(time.Now().Add(-repository.ExportTTL))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("removed %d expired export files", removed), nil
+}
+
+// WarmCache reloads the cached list endpoints so the first request after an
+// invalidation does not hit the database.
+func (s *maintenanceService) WarmCache() (string, error) {
+	// Daftar di-cache per locale
+	var categories []domain.Category
+	var products []domain.Product
+	for _, locale := range domain.SupportedLocales {
+		var err error
+		if categories, err = s.categoryService.GetAllCategories(locale); err != nil {
+			return "", err
+		}
+		if products, err = s.productService.GetAllProducts(locale); err != nil {
+			return "", err
+		}
+	}
+	// Facet tanpa filter dipakai halaman awal katalog
+	if _, err := s.productService.GetFacets(domain.ProductFilter{}); err != nil {
+		return "", err
+	}
+	orders, err := s.orderService.GetAllOrders()
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("cached %d categories, %d products in %d locales, %d orders",
+		len(categories), len(products), len(domain.SupportedLocales), len(orders)), nil
+}
+
+func (s *maintenanceService) ApplyScheduledPrices(ctx context.Context) (string, error) {
+	applied, err := s.productService.ApplyScheduledPrices(ctx)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("applied scheduled prices to %d products", applied), nil
+}
@@ -1,73 +1,418 @@
 package service
 
 import (
+	"context"
 	"crud-clean-architecture/domain"
+	"crud-clean-architecture/payment"
 	"crud-clean-architecture/repository"
 	"errors"
 	"fmt"
 	"time"
 )
 
+var (
+	ErrOrderNotFound         = repository.ErrOrderNotFound
+	ErrOrderAlreadyPaid      = repository.ErrOrderAlreadyPaid
+	ErrOrderCancelled        = repository.ErrOrderCancelled
+	ErrPaymentExceedsBalance = repository.ErrPaymentExceedsBalance
+	ErrOrderPaymentPending   = repository.ErrOrderPaymentPending
+	ErrOrderHasPayments      = repository.ErrOrderHasPayments
+	ErrInsufficientTendered  = errors.New("tendered cash is less than the payment amount")
+	ErrInvalidPaymentStatus  = errors.New("payment status transition is not allowed")
+	ErrVariantRequired       = errors.New("variant_id is required for products with variants")
+	ErrVariantNotInProduct   = errors.New("variant does not belong to the product")
+	ErrInvalidModifiers      = errors.New("invalid modifiers")
+)
+
 type OrderService interface {
-	CreateOrder(order *domain.Order) error
+	CreateOrder(ctx context.Context, order *domain.Order) error
 	GetAllOrders() ([]domain.Order, error)
+	GetOrders(filter domain.OrderFilter) ([]domain.Order, error)
 	GetOrderByID(id uint) (*domain.Order, error)
-	UpdateOrder(order *domain.Order) error
-	DeleteOrder(id uint) error
+	UpdateOrder(ctx context.Context, order *domain.Order) error
+	DeleteOrder(ctx context.Context, id uint) error
+	CancelOrder(ctx context.Context, id uint) (*domain.Order, error)
+	CreatePayment(ctx context.Context, orderID uint, form *domain.PaymentForm) (*domain.Payment, error)
+	GetOrderPayments(orderID uint) ([]domain.Payment, error)
+	UpdatePaymentStatus(ctx context.Context, orderID, paymentID uint, status domain.PaymentStatus) (*domain.Payment, error)
 }
 
 type orderService struct {
 	orderRepo   repository.OrderRepository
 	productRepo repository.ProductRepository
+	paymentRepo repository.PaymentRepository
+	providers   *payment.Registry
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository) OrderService {
-	return &orderService{orderRepo, productRepo}
+func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository,
+	paymentRepo repository.PaymentRepository, providers *payment.Registry) OrderService {
+	return &orderService{orderRepo, productRepo, paymentRepo, providers}
 }
 
-func (s *orderService) CreateOrder(order *domain.Order) error {
+func (s *orderService) CreateOrder(ctx context.Context, order *domain.Order) error {
+	// Harga diambil dari riwayat harga yang berlaku pada waktu order dibuat
+	order.OrderDate = time.Now()
+	sanitizeOrderInput(order)
+
 	var totalPrice float64
+	unavailable := map[string]string{}
 	for i := range order.Details {
-		product, err := s.productRepo.GetProductByID(order.Details[i].ProductID)
+		detail := &order.Details[i]
+		product, err := s.productRepo.GetProductByID(detail.ProductID)
 		if err != nil {
 			return errors.New("product not found")
 		}
-		order.Details[i].Subtotal = product.Price * float64(order.Details[i].Quantity)
+		// Kumpulkan semua baris yang tidak tersedia supaya klien tahu sekaligus
+		if !product.Availability.AvailableAt(order.OrderDate) {
+			unavailable[fmt.Sprintf("details[%d]", i)] = fmt.Sprintf("%s is not available at this time", product.Name)
+			continue
+		}
+		// Bundle hanya bisa dijual jika semua komponennya juga tersedia
+		component, err := s.unavailableComponent(product, order.OrderDate)
+		if err != nil {
+			return err
+		}
+		if component != nil {
+			unavailable[fmt.Sprintf("details[%d]", i)] = fmt.Sprintf("%s is not available at this time", component.Name)
+			continue
+		}
+		price, err := s.unitPrice(product, detail, order.OrderDate)
+		if err != nil {
+			return err
+		}
+		modifierPrice, err := applyModifiers(product, detail)
+		if err != nil {
+			return err
+		}
+		detail.Subtotal = (price + modifierPrice) * float64(detail.Quantity)
+		if err := s.allocateBundle(product, detail); err != nil {
+			return err
+		}
 		totalPrice += order.Details[i].Subtotal
 	}
+	if len(unavailable) > 0 {
+		return &ValidationError{Errors: unavailable}
+	}
 
 	order.TotalPrice = totalPrice
-	order.OrderDate = time.Now()
 
-	// Simpan order untuk mendapatkan ID
-	err := s.orderRepo.CreateOrderWithDetails(order)
+	// Status pembayaran hanya boleh diubah lewat endpoint payment
+	order.Status = domain.OrderStatusPending
+	order.PaidAmount = 0
+	order.PaidAt = nil
+	order.Payments = nil
+
+	// Simpan order beserta detail dan kode invoice dalam satu transaksi
+	return s.orderRepo.CreateOrderWithDetails(ctx, order)
+}
+
+// sanitizeOrderInput keeps only the fields a client may set on a new order:
+// IDs and embedded products or variants from the request body are dropped so
+// they cannot be written along with the order.
+func sanitizeOrderInput(order *domain.Order) {
+	order.ID = 0
+	for i := range order.Details {
+		detail := &order.Details[i]
+		detail.ID, detail.OrderID = 0, 0
+		detail.Product, detail.Variant = domain.Product{}, nil
+		for j := range detail.Modifiers {
+			detail.Modifiers[j] = domain.OrderDetailModifier{OptionID: detail.Modifiers[j].OptionID}
+		}
+	}
+}
+
+// unitPrice returns the price of one item of the detail at the given time.
+// Variants have their own price; other products use the price history and
+// fall back to their current price when they have no history.
+func (s *orderService) unitPrice(product *domain.Product, detail *domain.OrderDetail, at time.Time) (float64, error) {
+	if detail.VariantID != nil {
+		for _, variant := range product.Variants {
+			if variant.ID == *detail.VariantID {
+				if variant.Stock < detail.Quantity {
+					return 0, repository.ErrInsufficientStock
+				}
+				return variant.Price, nil
+			}
+		}
+		return 0, ErrVariantNotInProduct
+	}
+	if len(product.Variants) > 0 {
+		return 0, ErrVariantRequired
+	}
+
+	price, err := s.productRepo.GetPriceAt(detail.ProductID, at)
 	if err != nil {
-		return err
+		return 0, err
 	}
+	if price == nil {
+		return product.Price, nil
+	}
+	return price.Price, nil
+}
+
+// unavailableComponent returns the first component of a bundle that is not
+// available at the given time, or nil when the whole bundle can be sold.
+func (s *orderService) unavailableComponent(product *domain.Product, at time.Time) (*domain.Product, error) {
+	if product.Type != domain.ProductTypeBundle {
+		return nil, nil
+	}
+	for _, component := range product.Components {
+		componentProduct, err := s.productRepo.GetProductByID(component.ComponentID)
+		if err != nil {
+			return nil, errors.New("product not found")
+		}
+		if !componentProduct.Availability.AvailableAt(at) {
+			return componentProduct, nil
+		}
+	}
+	return nil, nil
+}
+
+// allocateBundle fills the components of a bundle detail and splits its
+// subtotal between them in proportion to their list price, so reports can
+// attribute the revenue to the component products. The last component takes
+// the rounding remainder so the parts add up to the subtotal.
+func (s *orderService) allocateBundle(product *domain.Product, detail *domain.OrderDetail) error {
+	detail.Components = nil
+	if product.Type != domain.ProductTypeBundle {
+		return nil
+	}
+
+	weights := make([]float64, len(product.Components))
+	var totalWeight float64
+	for i, component := range product.Components {
+		componentProduct, err := s.productRepo.GetProductByID(component.ComponentID)
+		if err != nil {
+			return errors.New("product not found")
+		}
+		price := componentProduct.Price
+		if component.VariantID != nil {
+			for _, variant := range componentProduct.Variants {
+				if variant.ID == *component.VariantID {
+					price = variant.Price
+				}
+			}
+		}
+		weights[i] = price * float64(component.Quantity)
+		totalWeight += weights[i]
 
-	// Generate kode invoice
-	invoiceNumber := s.generateInvoiceNumber(order.ID, order.OrderDate)
+		detail.Components = append(detail.Components, domain.OrderDetailComponent{
+			ProductID: component.ComponentID,
+			VariantID: component.VariantID,
+			Quantity:  component.Quantity * detail.Quantity,
+		})
+	}
 
-	// Perbarui invoice number di database
-	return s.orderRepo.UpdateOrderInvoice(order.ID, invoiceNumber)
+	allocated := 0.0
+	for i := range detail.Components {
+		if i == len(detail.Components)-1 {
+			detail.Components[i].Revenue = domain.RoundMoney(detail.Subtotal - allocated)
+			break
+		}
+		share := 1 / float64(len(weights))
+		if totalWeight > 0 {
+			share = weights[i] / totalWeight
+		}
+		detail.Components[i].Revenue = domain.RoundMoney(detail.Subtotal * share)
+		allocated += detail.Components[i].Revenue
+	}
+	return nil
+}
+
+// applyModifiers checks the selected options against the product's modifier
+// groups (known option, no duplicates, min/max per group), fills in their
+// names and price and returns the price added to one item.
+func applyModifiers(product *domain.Product, detail *domain.OrderDetail) (float64, error) {
+	type optionRef struct {
+		group  *domain.ModifierGroup
+		option domain.ModifierOption
+	}
+	options := map[uint]optionRef{}
+	for i := range product.ModifierGroups {
+		group := &product.ModifierGroups[i]
+		for _, option := range group.Options {
+			options[option.ID] = optionRef{group, option}
+		}
+	}
+
+	var total float64
+	selected := map[uint]int{}
+	seen := map[uint]bool{}
+	for i := range detail.Modifiers {
+		modifier := &detail.Modifiers[i]
+		ref, ok := options[modifier.OptionID]
+		if !ok {
+			return 0, fmt.Errorf("%w: option %d is not available for product %d", ErrInvalidModifiers, modifier.OptionID, product.ID)
+		}
+		if seen[modifier.OptionID] {
+			return 0, fmt.Errorf("%w: option %d is selected more than once", ErrInvalidModifiers, modifier.OptionID)
+		}
+		seen[modifier.OptionID] = true
+		selected[ref.group.ID]++
+
+		// Nama dan harga disalin dari menu saat order dibuat
+		modifier.ID = 0
+		modifier.GroupName = ref.group.Name
+		modifier.OptionName = ref.option.Name
+		modifier.PriceDelta = ref.option.PriceDelta
+		total += ref.option.PriceDelta
+	}
+
+	for _, group := range product.ModifierGroups {
+		count := selected[group.ID]
+		if count < group.MinSelect || count > group.MaxSelect {
+			return 0, fmt.Errorf("%w: %s requires between %d and %d selections, got %d",
+				ErrInvalidModifiers, group.Name, group.MinSelect, group.MaxSelect, count)
+		}
+	}
+	return total, nil
 }
 
 func (s *orderService) GetAllOrders() ([]domain.Order, error) {
 	return s.orderRepo.GetAllOrders()
 }
 
+// GetOrders serves unfiltered requests from the cached list.
+func (s *orderService) GetOrders(filter domain.OrderFilter) ([]domain.Order, error) {
+	if filter.IsEmpty() {
+		return s.orderRepo.GetAllOrders()
+	}
+	return s.orderRepo.GetOrders(filter)
+}
+
 func (s *orderService) GetOrderByID(id uint) (*domain.Order, error) {
 	return s.orderRepo.GetOrderByID(id)
 }
 
-func (s *orderService) UpdateOrder(order *domain.Order) error {
-	return s.orderRepo.UpdateOrder(order)
+func (s *orderService) UpdateOrder(ctx context.Context, order *domain.Order) error {
+	return s.orderRepo.UpdateOrder(ctx, order)
+}
+
+func (s *orderService) DeleteOrder(ctx context.Context, id uint) error {
+	return s.orderRepo.DeleteOrder(ctx, id)
+}
+
+// CancelOrder cancels the order and restores its stock. Paid and cancelled
+// orders and orders with a pending payment are rejected while the order is locked.
+func (s *orderService) CancelOrder(ctx context.Context, id uint) (*domain.Order, error) {
+	order, err := s.orderRepo.GetOrderByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orderRepo.UpdateOrderStatus(ctx, order.ID, domain.OrderStatusCancelled); err != nil {
+		return nil, err
+	}
+	order.Status = domain.OrderStatusCancelled
+	return order, nil
+}
+
+func (s *orderService) CreatePayment(ctx context.Context, orderID uint, form *domain.PaymentForm) (*domain.Payment, error) {
+	order, err := s.orderRepo.GetOrderByID(orderID)
+	if err != nil {
+		return nil, err
+	}
+	switch order.Status {
+	case domain.OrderStatusPaid:
+		return nil, ErrOrderAlreadyPaid
+	case domain.OrderStatusCancelled:
+		return nil, ErrOrderCancelled
+	}
+
+	// Hitung sisa tagihan; tanpa amount berarti melunasi seluruh sisa
+	outstanding := order.OutstandingAmount(order.Payments)
+	amount := domain.RoundMoney(form.Amount)
+	if amount == 0 {
+		amount = outstanding
+	}
+	if amount <= 0 || amount > outstanding {
+		return nil, ErrPaymentExceedsBalance
+	}
+
+	pay := &domain.Payment{
+		OrderID: order.ID,
+		Method:  form.Method,
+		Amount:  amount,
+	}
+
+	// Hitung kembalian untuk pembayaran tunai
+	if form.Method == domain.PaymentMethodCash {
+		tendered := domain.RoundMoney(form.Tendered)
+		if tendered == 0 {
+			tendered = amount
+		}
+		if tendered < amount {
+			return nil, ErrInsufficientTendered
+		}
+		pay.Tendered = tendered
+		pay.Change = domain.RoundMoney(tendered - amount)
+	}
+
+	provider, err := s.providers.Get(form.Method)
+	if err != nil {
+		return nil, err
+	}
+	intent, err := provider.CreateIntent(ctx, payment.IntentRequest{
+		OrderID:       order.ID,
+		InvoiceNumber: order.InvoiceNumber,
+		Method:        form.Method,
+		Amount:        amount,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("payment provider %s: %w", provider.Name(), err)
+	}
+
+	pay.Provider = provider.Name()
+	pay.ProviderRef = intent.Reference
+	pay.Status = intent.Status
+	pay.Instructions = intent.Instructions
+	pay.PaymentURL = intent.PaymentURL
+	pay.ExpiresAt = intent.ExpiresAt
+	if pay.Status == domain.PaymentStatusPaid {
+		now := time.Now()
+		pay.PaidAt = &now
+	}
+
+	if _, err := s.paymentRepo.CreatePayment(ctx, pay); err != nil {
+		return nil, err
+	}
+	return pay, nil
 }
 
-func (s *orderService) DeleteOrder(id uint) error {
-	return s.orderRepo.DeleteOrder(id)
+func (s *orderService) GetOrderPayments(orderID uint) ([]domain.Payment, error) {
+	if _, err := s.orderRepo.GetOrderByID(orderID); err != nil {
+		return nil, err
+	}
+	return s.paymentRepo.GetPaymentsByOrderID(orderID)
 }
-func (s *orderService) generateInvoiceNumber(orderID uint, orderDate time.Time) string {
-	datePart := orderDate.Format("20060102") // Format tanggal menjadi YYYYMMDD
-	return fmt.Sprintf("INV-%s-%d", datePart, orderID)
+
+// UpdatePaymentStatus moves the payment to status. The transition is checked
+// against the payment as locked by the repository.
+func (s *orderService) UpdatePaymentStatus(ctx context.Context, orderID, paymentID uint, status domain.PaymentStatus) (*domain.Payment, error) {
+	if _, err := s.orderRepo.GetOrderByID(orderID); err != nil {
+		return nil, err
+	}
+	pay, err := s.paymentRepo.GetPaymentByID(paymentID)
+	if err != nil {
+		return nil, err
+	}
+	if pay.OrderID != orderID {
+		return nil, repository.ErrPaymentNotFound
+	}
+
+	_, err = s.paymentRepo.UpdatePayment(ctx, pay, func(current *domain.Payment) error {
+		if !current.Status.CanTransitionTo(status) {
+			return ErrInvalidPaymentStatus
+		}
+		current.Status = status
+		if status == domain.PaymentStatusPaid {
+			now := time.Now()
+			current.PaidAt = &now
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return pay, nil
 }
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"testing"
+)
+
+func TestCreateOrderChecksBundleComponents(t *testing.T) {
+	closed := &domain.AvailabilitySchedule{Timezone: "UTC", Windows: []domain.AvailabilityWindow{
+		{StartDate: "2000-01-01", EndDate: "2000-01-01"},
+	}}
+	productRepo := newMemoryProductRepository(
+		domain.Product{ID: 1, Name: "Paket Sarapan", Type: domain.ProductTypeBundle, Price: 30000,
+			Components: []domain.BundleComponent{{ComponentID: 2, Quantity: 1}, {ComponentID: 3, Quantity: 1}}},
+		domain.Product{ID: 2, Name: "Kopi", Type: domain.ProductTypeSimple, Price: 10000},
+		domain.Product{ID: 3, Name: "Nasi Goreng", Type: domain.ProductTypeSimple, Price: 25000, Availability: closed},
+	)
+	s := NewOrderService(nil, productRepo, nil, nil)
+
+	order := &domain.Order{Details: []domain.OrderDetail{{ProductID: 1, Quantity: 1}}}
+	err := s.CreateOrder(context.Background(), order)
+
+	var validation *ValidationError
+	if !errors.As(err, &validation) {
+		t.Fatalf("expected ValidationError, got %v", err)
+	}
+	if got := validation.Errors["details[0]"]; got != "Nasi Goreng is not available at this time" {
+		t.Fatalf("unexpected error message %q", got)
+	}
+}
+
+func TestSanitizeOrderInput(t *testing.T) {
+	variantID := uint(7)
+	order := &domain.Order{ID: 99, Details: []domain.OrderDetail{{
+		ID:        5,
+		OrderID:   99,
+		ProductID: 1,
+		VariantID: &variantID,
+		Quantity:  2,
+		Product:   domain.Product{ID: 1, Name: "Kopi", Price: 1},
+		Variant:   &domain.ProductVariant{ID: 7, Stock: 1000},
+		Modifiers: []domain.OrderDetailModifier{{ID: 3, OrderDetailID: 5, OptionID: 4, PriceDelta: -5000}},
+	}}}
+
+	sanitizeOrderInput(order)
+
+	detail := order.Details[0]
+	if order.ID != 0 || detail.ID != 0 || detail.OrderID != 0 {
+		t.Fatalf("expected IDs from the request to be dropped, got %+v", order)
+	}
+	if detail.Product.ID != 0 || detail.Variant != nil {
+		t.Fatalf("expected embedded product and variant to be dropped, got %+v", detail)
+	}
+	if detail.ProductID != 1 || detail.VariantID == nil || *detail.VariantID != 7 || detail.Quantity != 2 {
+		t.Fatalf("expected the requested item to be kept, got %+v", detail)
+	}
+	if want := (domain.OrderDetailModifier{OptionID: 4}); detail.Modifiers[0] != want {
+		t.Fatalf("expected only the option ID of the modifier, got %+v", detail.Modifiers[0])
+	}
+}
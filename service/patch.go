@@ -0,0 +1,47 @@
+package service
+
+import (
+	"bytes"
+	"crud-clean-architecture/utils"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+var ErrInvalidPatch = errors.New("invalid merge patch document")
+
+// ValidationError carries the field errors of a patched resource.
+type ValidationError struct {
+	Errors map[string]string
+}
+
+func (e *ValidationError) Error() string {
+	return "validation error"
+}
+
+// applyMergePatch merges patch into the JSON form of current and decodes the
+// result into form, which is then validated with its binding rules. Members
+// that are not part of the form are rejected.
+func applyMergePatch(current interface{}, patch []byte, form interface{}) error {
+	target, err := json.Marshal(current)
+	if err != nil {
+		return err
+	}
+	merged, err := utils.MergePatch(target, patch)
+	if err != nil {
+		return ErrInvalidPatch
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(merged))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(form); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidPatch, err)
+	}
+
+	if err := binding.Validator.ValidateStruct(form); err != nil {
+		return &ValidationError{Errors: utils.FormatValidationErrors(err)}
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/audit"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/payment"
+	"crud-clean-architecture/repository"
+	"log"
+	"time"
+)
+
+type PaymentService interface {
+	HandleWebhook(ctx context.Context, providerName string, body []byte, signature string) (*domain.PaymentEvent, error)
+}
+
+type paymentService struct {
+	paymentRepo repository.PaymentRepository
+	providers   *payment.Registry
+}
+
+func NewPaymentService(paymentRepo repository.PaymentRepository, providers *payment.Registry) PaymentService {
+	return &paymentService{paymentRepo, providers}
+}
+
+func (s *paymentService) HandleWebhook(ctx context.Context, providerName string, body []byte, signature string) (*domain.PaymentEvent, error) {
+	provider, err := s.providers.ByName(providerName)
+	if err != nil {
+		return nil, err
+	}
+	webhookProvider, ok := provider.(payment.WebhookProvider)
+	if !ok {
+		return nil, payment.ErrWebhookNotSupported
+	}
+
+	// Verifikasi signature dan terjemahkan status gateway
+	webhookEvent, err := webhookProvider.ParseWebhook(body, signature)
+	if err != nil {
+		return nil, err
+	}
+
+	pay, err := s.paymentRepo.GetPaymentByReference(provider.Name(), webhookEvent.Reference)
+	if err != nil {
+		return nil, err
+	}
+
+	event := &domain.PaymentEvent{
+		Provider:  provider.Name(),
+		EventID:   webhookEvent.EventID,
+		RawStatus: webhookEvent.RawStatus,
+		Status:    webhookEvent.Status,
+		Payload:   string(body),
+	}
+
+	// Perubahan dari webhook dicatat di audit log atas nama provider
+	ctx = audit.WithActor(ctx, "provider:"+provider.Name())
+	// Transisi dinilai terhadap payment yang sudah dikunci di dalam transaksi
+	_, err = s.paymentRepo.ApplyPaymentEvent(ctx, pay, event, func(current *domain.Payment) bool {
+		return applyWebhookEvent(current, webhookEvent, time.Now())
+	})
+	if err != nil {
+		return nil, err
+	}
+	return event, nil
+}
+
+// applyWebhookEvent memindahkan payment ke status dari gateway jika transisinya sah
+// dan nominalnya cocok, lalu melaporkan apakah payment berubah.
+func applyWebhookEvent(pay *domain.Payment, webhookEvent *payment.WebhookEvent, now time.Time) bool {
+	if !pay.Status.CanTransitionTo(webhookEvent.Status) {
+		return false
+	}
+	// Jangan tandai lunas jika nominal dari gateway tidak sama dengan tagihan
+	if webhookEvent.Status == domain.PaymentStatusPaid && webhookEvent.Amount > 0 &&
+		domain.RoundMoney(webhookEvent.Amount) != pay.Amount {
+		log.Printf("payment %d: webhook amount %.2f does not match %.2f, event %s ignored",
+			pay.ID, webhookEvent.Amount, pay.Amount, webhookEvent.EventID)
+		return false
+	}
+
+	pay.Status = webhookEvent.Status
+	if pay.Status == domain.PaymentStatusPaid {
+		pay.PaidAt = &now
+	}
+	return true
+}
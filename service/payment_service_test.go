@@ -0,0 +1,194 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/audit"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/payment"
+	"crud-clean-architecture/repository"
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+const testWebhookSecret = "test-secret"
+
+// memoryPaymentRepository menyimpan payment di memori dan meniru kontrak
+// ApplyPaymentEvent: duplikat ditolak dan apply menerima kondisi terbaru.
+// Setiap perubahan yang disimpan dicatat bersama actor dari context.
+type memoryPaymentRepository struct {
+	repository.PaymentRepository
+	payments    map[uint]*domain.Payment
+	events      map[string]bool
+	saves       []string
+	beforeApply func()
+}
+
+func newMemoryPaymentRepository(payments ...domain.Payment) *memoryPaymentRepository {
+	repo := &memoryPaymentRepository{payments: map[uint]*domain.Payment{}, events: map[string]bool{}}
+	for i := range payments {
+		repo.payments[payments[i].ID] = &payments[i]
+	}
+	return repo
+}
+
+func (r *memoryPaymentRepository) GetPaymentByReference(provider, reference string) (*domain.Payment, error) {
+	for _, pay := range r.payments {
+		if pay.Provider == provider && pay.ProviderRef == reference {
+			copied := *pay
+			return &copied, nil
+		}
+	}
+	return nil, repository.ErrPaymentNotFound
+}
+
+func (r *memoryPaymentRepository) ApplyPaymentEvent(ctx context.Context, pay *domain.Payment, event *domain.PaymentEvent,
+	apply func(current *domain.Payment) bool) (*domain.Order, error) {
+	if r.beforeApply != nil {
+		r.beforeApply()
+	}
+	key := event.Provider + ":" + event.EventID
+	if r.events[key] {
+		return nil, repository.ErrDuplicatePaymentEvent
+	}
+	current := *r.payments[pay.ID]
+	event.Applied = apply(&current)
+	event.PaymentID = current.ID
+	r.events[key] = true
+	if event.Applied {
+		*r.payments[pay.ID] = current
+		r.saves = append(r.saves, audit.FromContext(ctx).Actor)
+	}
+	*pay = current
+	return &domain.Order{ID: current.OrderID}, nil
+}
+
+func newWebhookTestService(t *testing.T, payments ...domain.Payment) (PaymentService, *memoryPaymentRepository) {
+	t.Helper()
+	provider := payment.NewFakeProvider("fake", domain.PaymentStatusPending)
+	provider.SetWebhookSecret(testWebhookSecret)
+	providers := payment.NewRegistry()
+	providers.Register(domain.PaymentMethodQRIS, provider)
+
+	repo := newMemoryPaymentRepository(payments...)
+	return NewPaymentService(repo, providers), repo
+}
+
+func webhookBody(t *testing.T, eventID, status string, amount float64) []byte {
+	t.Helper()
+	body, err := json.Marshal(payment.FakeWebhookPayload{
+		EventID:           eventID,
+		Reference:         "ref-1",
+		TransactionStatus: status,
+		Amount:            amount,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func pendingPayment() domain.Payment {
+	return domain.Payment{ID: 1, OrderID: 7, Provider: "fake", ProviderRef: "ref-1",
+		Amount: 50000, Status: domain.PaymentStatusPending}
+}
+
+func TestHandleWebhookRejectsInvalidSignature(t *testing.T) {
+	svc, repo := newWebhookTestService(t, pendingPayment())
+	body := webhookBody(t, "evt-1", "settlement", 50000)
+
+	for name, signature := range map[string]string{
+		"missing":      "",
+		"wrong secret": payment.Sign("other-secret", body),
+		"other body":   payment.Sign(testWebhookSecret, webhookBody(t, "evt-1", "settlement", 1)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.HandleWebhook(context.Background(), "fake", body, signature)
+			if !errors.Is(err, payment.ErrInvalidSignature) {
+				t.Fatalf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+	if repo.payments[1].Status != domain.PaymentStatusPending || len(repo.events) != 0 || len(repo.saves) != 0 {
+		t.Fatalf("rejected webhook must not change anything: status=%s events=%d saves=%d",
+			repo.payments[1].Status, len(repo.events), len(repo.saves))
+	}
+}
+
+func TestHandleWebhookAppliesEventOnce(t *testing.T) {
+	svc, repo := newWebhookTestService(t, pendingPayment())
+	body := webhookBody(t, "evt-1", "settlement", 50000)
+	signature := payment.Sign(testWebhookSecret, body)
+
+	event, err := svc.HandleWebhook(context.Background(), "fake", body, signature)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !event.Applied || repo.payments[1].Status != domain.PaymentStatusPaid || repo.payments[1].PaidAt == nil {
+		t.Fatalf("expected payment to be paid, got applied=%v status=%s", event.Applied, repo.payments[1].Status)
+	}
+
+	_, err = svc.HandleWebhook(context.Background(), "fake", body, signature)
+	if !errors.Is(err, repository.ErrDuplicatePaymentEvent) {
+		t.Fatalf("expected ErrDuplicatePaymentEvent, got %v", err)
+	}
+	if len(repo.saves) != 1 || repo.saves[0] != "provider:fake" {
+		t.Fatalf("expected one save by provider:fake, got %v", repo.saves)
+	}
+}
+
+func TestHandleWebhookIgnoresIllegalTransitions(t *testing.T) {
+	tests := []struct {
+		name    string
+		current domain.PaymentStatus
+		status  string
+		amount  float64
+	}{
+		{"paid payment cannot fail", domain.PaymentStatusPaid, "deny", 0},
+		{"expired payment cannot be paid", domain.PaymentStatusExpired, "settlement", 50000},
+		{"pending stays pending", domain.PaymentStatusPending, "pending", 0},
+		{"amount mismatch", domain.PaymentStatusPending, "settlement", 40000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			current := pendingPayment()
+			current.Status = tt.current
+			svc, repo := newWebhookTestService(t, current)
+			body := webhookBody(t, "evt-1", tt.status, tt.amount)
+
+			event, err := svc.HandleWebhook(context.Background(), "fake", body, payment.Sign(testWebhookSecret, body))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if event.Applied {
+				t.Fatal("event must not be applied")
+			}
+			if repo.payments[1].Status != tt.current {
+				t.Fatalf("status changed from %s to %s", tt.current, repo.payments[1].Status)
+			}
+			if !repo.events["fake:evt-1"] {
+				t.Fatal("ignored event must still be recorded")
+			}
+			if len(repo.saves) != 0 {
+				t.Fatalf("expected no save, got %v", repo.saves)
+			}
+		})
+	}
+}
+
+func TestHandleWebhookUsesLockedPayment(t *testing.T) {
+	svc, repo := newWebhookTestService(t, pendingPayment())
+	// Payment dibatalkan di antara pembacaan awal dan transaksi
+	repo.beforeApply = func() {
+		repo.payments[1].Status = domain.PaymentStatusCancelled
+	}
+
+	body := webhookBody(t, "evt-1", "settlement", 50000)
+	event, err := svc.HandleWebhook(context.Background(), "fake", body, payment.Sign(testWebhookSecret, body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if event.Applied || repo.payments[1].Status != domain.PaymentStatusCancelled {
+		t.Fatalf("cancelled payment must not be paid, got applied=%v status=%s", event.Applied, repo.payments[1].Status)
+	}
+}
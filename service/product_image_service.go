@@ -0,0 +1,148 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/imaging"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/storage"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log"
+)
+
+const (
+	thumbnailSize    = 320
+	maxProductImages = 20
+)
+
+type ProductImageService interface {
+	GetImages(productID uint) ([]domain.ProductImage, error)
+	Upload(ctx context.Context, productID uint, data []byte) (*domain.ProductImage, error)
+	DeleteImage(ctx context.Context, productID, imageID uint) error
+	ReorderImages(ctx context.Context, productID uint, imageIDs []uint) ([]domain.ProductImage, error)
+}
+
+type productImageService struct {
+	productRepo repository.ProductRepository
+	storage     storage.Storage
+}
+
+func NewProductImageService(productRepo repository.ProductRepository, storage storage.Storage) ProductImageService {
+	return &productImageService{productRepo, storage}
+}
+
+func (s *productImageService) GetImages(productID uint) ([]domain.ProductImage, error) {
+	if _, err := s.productRepo.GetProductByID(productID); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	return s.productRepo.GetImages(productID)
+}
+
+// Upload validates the image, stores it with a thumbnail and appends it to
+// the product's images. The files are removed again if saving the row fails.
+func (s *productImageService) Upload(ctx context.Context, productID uint, data []byte) (*domain.ProductImage, error) {
+	product, err := s.productRepo.GetProductByID(productID)
+	if err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	if len(product.Images) >= maxProductImages {
+		return nil, &ValidationError{Errors: map[string]string{"file": fmt.Sprintf("a product can have at most %d images", maxProductImages)}}
+	}
+
+	img, err := imaging.Decode(data)
+	if err != nil {
+		return nil, &ValidationError{Errors: map[string]string{"file": err.Error()}}
+	}
+	thumbnail, thumbnailType, thumbnailExt, err := imaging.EncodeThumbnail(imaging.Thumbnail(img.Image, thumbnailSize), img.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	name, err := randomName()
+	if err != nil {
+		return nil, err
+	}
+	image := &domain.ProductImage{
+		ProductID:    productID,
+		StorageKey:   fmt.Sprintf("products/%d/%s%s", productID, name, img.Extension),
+		ThumbnailKey: fmt.Sprintf("products/%d/%s_thumb%s", productID, name, thumbnailExt),
+		ContentType:  img.ContentType,
+		Width:        img.Image.Bounds().Dx(),
+		Height:       img.Image.Bounds().Dy(),
+		Size:         int64(len(data)),
+	}
+	image.URL = s.storage.URL(image.StorageKey)
+	image.ThumbnailURL = s.storage.URL(image.ThumbnailKey)
+
+	if err := s.storage.Put(ctx, image.StorageKey, data, img.ContentType); err != nil {
+		return nil, err
+	}
+	if err := s.storage.Put(ctx, image.ThumbnailKey, thumbnail, thumbnailType); err != nil {
+		s.removeFiles(image)
+		return nil, err
+	}
+	if err := s.productRepo.CreateImage(ctx, image); err != nil {
+		s.removeFiles(image)
+		return nil, err
+	}
+	return image, nil
+}
+
+func (s *productImageService) DeleteImage(ctx context.Context, productID, imageID uint) error {
+	image, err := s.productRepo.DeleteImage(ctx, productID, imageID)
+	if err != nil {
+		return err
+	}
+	s.removeFiles(image)
+	return nil
+}
+
+// ReorderImages expects every image ID of the product exactly once.
+func (s *productImageService) ReorderImages(ctx context.Context, productID uint, imageIDs []uint) ([]domain.ProductImage, error) {
+	images, err := s.GetImages(productID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := map[uint]bool{}
+	for _, image := range images {
+		existing[image.ID] = true
+	}
+	seen := map[uint]bool{}
+	for _, id := range imageIDs {
+		if !existing[id] || seen[id] {
+			return nil, &ValidationError{Errors: map[string]string{"image_ids": "image_ids must list every image of the product exactly once"}}
+		}
+		seen[id] = true
+	}
+	if len(seen) != len(existing) {
+		return nil, &ValidationError{Errors: map[string]string{"image_ids": "image_ids must list every image of the product exactly once"}}
+	}
+
+	return s.productRepo.ReorderImages(ctx, productID, imageIDs)
+}
+
+func (s *productImageService) removeFiles(image *domain.ProductImage) {
+	removeImageFiles(s.storage, *image)
+}
+
+// removeImageFiles menghapus file gambar; kegagalan hanya dicatat karena data sudah konsisten
+func removeImageFiles(files storage.Storage, images ...domain.ProductImage) {
+	for _, image := range images {
+		for _, key := range []string{image.StorageKey, image.ThumbnailKey} {
+			if err := files.Delete(context.Background(), key); err != nil {
+				log.Printf("product image: failed to delete %s: %v", key, err)
+			}
+		}
+	}
+}
+
+func randomName() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,285 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/utils"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	maxImportRows          = 5000
+	defaultImportBatchSize = 100
+)
+
+var (
+	ErrInvalidImportFile = errors.New("CSV must have a header row with name, price and category columns")
+	ErrImportTooLarge    = errors.New("CSV must not have more than 5000 rows")
+)
+
+type ProductImportService interface {
+	ParseCSV(r io.Reader) ([]domain.ProductImportRow, error)
+	Import(ctx context.Context, rows []domain.ProductImportRow, form *domain.ProductImportForm) (*domain.ProductImportReport, error)
+}
+
+type productImportService struct {
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+}
+
+func NewProductImportService(productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository) ProductImportService {
+	return &productImportService{productRepo, categoryRepo}
+}
+
+// ParseCSV reads the rows of a CSV with a name, price and category header.
+// Column order does not matter and unknown columns are ignored.
+func (s *productImportService) ParseCSV(r io.Reader) ([]domain.ProductImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, ErrInvalidImportFile
+	}
+	columns := map[string]int{}
+	for i, column := range header {
+		column = strings.TrimPrefix(column, "\ufeff") // BOM dari Excel
+		columns[strings.ToLower(strings.TrimSpace(column))] = i
+	}
+	for _, required := range []string{"name", "price", "category"} {
+		if _, ok := columns[required]; !ok {
+			return nil, ErrInvalidImportFile
+		}
+	}
+
+	field := func(record []string, column string) string {
+		if i := columns[column]; i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var rows []domain.ProductImportRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return rows, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(rows) == maxImportRows {
+			return nil, ErrImportTooLarge
+		}
+
+		line, _ := reader.FieldPos(0)
+		rows = append(rows, domain.ProductImportRow{
+			Line:     line,
+			Name:     field(record, "name"),
+			Price:    field(record, "price"),
+			Category: field(record, "category"),
+		})
+	}
+}
+
+// Import validates every row with the same rules as POST /products and then
+// writes the valid rows according to form. Nothing is written on dry run, and
+// in atomic mode nothing is written unless every row is valid.
+func (s *productImportService) Import(ctx context.Context, rows []domain.ProductImportRow, form *domain.ProductImportForm) (*domain.ProductImportReport, error) {
+	report := &domain.ProductImportReport{
+		DryRun: form.DryRun,
+		Mode:   form.Mode,
+		Commit: form.Commit,
+		Total:  len(rows),
+		Rows:   make([]domain.ProductImportResult, len(rows)),
+	}
+	if report.Mode == "" {
+		report.Mode = domain.ProductImportCreate
+	}
+	if report.Commit == "" {
+		report.Commit = domain.ProductImportAtomic
+	}
+
+	items, indexes, err := s.validate(rows, report)
+	if err != nil {
+		return nil, err
+	}
+	report.Valid = len(items)
+	report.Invalid = len(rows) - len(items)
+
+	if form.DryRun || len(items) == 0 {
+		return report, nil
+	}
+	if report.Commit == domain.ProductImportAtomic {
+		if report.Invalid > 0 {
+			return report, nil
+		}
+		if err := s.productRepo.ImportProducts(ctx, items); err != nil {
+			return nil, err
+		}
+		s.applyResults(report, items, indexes)
+		return report, nil
+	}
+
+	batchSize := form.BatchSize
+	if batchSize == 0 {
+		batchSize = defaultImportBatchSize
+	}
+	for start := 0; start < len(items); start += batchSize {
+		end := min(start+batchSize, len(items))
+
+		// Salin batch supaya ID dari transaksi yang di-rollback tidak bocor ke batch berikutnya
+		batch := append([]domain.ProductImportItem(nil), items[start:end]...)
+		if err := s.productRepo.ImportProducts(ctx, batch); err != nil {
+			for _, index := range indexes[start:end] {
+				report.Rows[index].Action = domain.ProductImportActionSkip
+				report.Rows[index].Errors = map[string]string{"row": err.Error()}
+			}
+			report.Failed += end - start
+			continue
+		}
+		s.applyResults(report, batch, indexes[start:end])
+	}
+	return report, nil
+}
+
+// validate fills report.Rows and returns the valid items with the index of
+// their row in the report.
+func (s *productImportService) validate(rows []domain.ProductImportRow, report *domain.ProductImportReport) ([]domain.ProductImportItem, []int, error) {
+	categories := map[string]*domain.Category{}
+	seen := map[string]int{}
+	existingProducts := map[uint]*domain.Product{}
+
+	var items []domain.ProductImportItem
+	var indexes []int
+	for i, row := range rows {
+		result := domain.ProductImportResult{Line: row.Line, Name: row.Name, Category: row.Category}
+		errs := map[string]string{}
+
+		var price float64
+		if row.Price != "" {
+			// Inf dan NaN lolos ParseFloat maupun validasi gt=0, jadi ditolak di sini
+			parsed, err := strconv.ParseFloat(row.Price, 64)
+			if err != nil || math.IsInf(parsed, 0) || math.IsNaN(parsed) {
+				errs["price"] = "price must be a number"
+			} else {
+				price = parsed
+			}
+		}
+
+		// Kategori divalidasi dengan aturan CategoryForm, lalu di-resolve berdasarkan nama
+		var categoryID uint
+		var schema []domain.AttributeDefinition
+		if err := binding.Validator.ValidateStruct(&domain.CategoryForm{Name: row.Category}); err != nil {
+			for _, message := range utils.FormatValidationErrors(err) {
+				errs["category"] = "category" + strings.TrimPrefix(message, "name")
+			}
+		} else {
+			category, ok := categories[row.Category]
+			if !ok {
+				found, err := s.categoryRepo.GetCategoryByName(row.Category)
+				if err != nil {
+					return nil, nil, err
+				}
+				categories[row.Category] = found
+				category = found
+			}
+			if category != nil {
+				categoryID = category.ID
+				schema = category.AttributeSchema
+			}
+		}
+
+		productForm := domain.ProductForm{Name: row.Name, Price: price, CategoryID: categoryID}
+		if err := binding.Validator.ValidateStruct(&productForm); err != nil {
+			for field, message := range utils.FormatValidationErrors(err) {
+				if _, exists := errs[field]; !exists {
+					errs[field] = message
+				}
+			}
+		}
+		// category_id diturunkan dari kolom category, errornya sudah dilaporkan di sana
+		delete(errs, "category_id")
+
+		product := domain.Product{Name: row.Name, Price: price, CategoryID: categoryID}
+		if len(errs) == 0 {
+			key := strings.ToLower(row.Category) + "\x00" + strings.ToLower(row.Name)
+			if line, ok := seen[key]; ok {
+				errs["name"] = fmt.Sprintf("name is duplicated in the file (line %d)", line)
+			} else {
+				seen[key] = row.Line
+			}
+		}
+		if len(errs) == 0 && categoryID != 0 {
+			if report.Mode == domain.ProductImportUpsert {
+				existing, err := s.productRepo.GetProductByName(row.Name, categoryID)
+				if err != nil {
+					return nil, nil, err
+				}
+				if existing != nil {
+					product.ID = existing.ID
+					existingProducts[existing.ID] = existing
+				}
+			} else {
+				isUnique, err := s.productRepo.IsProductNameUnique(row.Name, categoryID)
+				if err != nil {
+					return nil, nil, err
+				}
+				if !isUnique {
+					errs["name"] = "name must be unique"
+				}
+			}
+		}
+		// CSV tidak memuat atribut; produk baru dan produk yang dipindah kategori harus tetap valid
+		if len(errs) == 0 && categoryID != 0 {
+			var values map[string]interface{}
+			if existing, ok := existingProducts[product.ID]; ok {
+				values = existing.Attributes
+			}
+			_, attributeErrs := validateAttributes(schema, values)
+			for field, message := range attributeErrs {
+				errs[field] = message
+			}
+		}
+
+		switch {
+		case len(errs) > 0:
+			result.Action = domain.ProductImportActionSkip
+			result.Errors = errs
+		case product.ID != 0:
+			result.Action = domain.ProductImportActionUpdate
+			result.ProductID = product.ID
+		default:
+			result.Action = domain.ProductImportActionCreate
+		}
+		report.Rows[i] = result
+
+		if len(errs) == 0 {
+			items = append(items, domain.ProductImportItem{Line: row.Line, Product: product, CategoryName: row.Category})
+			indexes = append(indexes, i)
+		}
+	}
+	return items, indexes, nil
+}
+
+func (s *productImportService) applyResults(report *domain.ProductImportReport, items []domain.ProductImportItem, indexes []int) {
+	for i, item := range items {
+		result := &report.Rows[indexes[i]]
+		result.ProductID = item.Product.ID
+		if result.Action == domain.ProductImportActionUpdate {
+			report.Updated++
+		} else {
+			report.Created++
+		}
+	}
+}
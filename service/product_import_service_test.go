@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"errors"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// TestMain memakai nama field JSON untuk error validasi, sama seperti main.go
+func TestMain(m *testing.M) {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		v.RegisterTagNameFunc(func(field reflect.StructField) string {
+			return field.Tag.Get("json")
+		})
+	}
+	os.Exit(m.Run())
+}
+
+// importProductRepository mencatat setiap pemanggilan ImportProducts; error di
+// importErrs dipakai berurutan per pemanggilan
+type importProductRepository struct {
+	*memoryProductRepository
+	imports    [][]domain.ProductImportItem
+	importErrs []error
+	nextID     uint
+}
+
+func (r *importProductRepository) ImportProducts(ctx context.Context, items []domain.ProductImportItem) error {
+	call := len(r.imports)
+	r.imports = append(r.imports, items)
+	if call < len(r.importErrs) && r.importErrs[call] != nil {
+		return r.importErrs[call]
+	}
+	for i := range items {
+		if items[i].Product.ID == 0 {
+			r.nextID++
+			items[i].Product.ID = r.nextID
+		}
+	}
+	return nil
+}
+
+func newImportTestService(importErrs ...error) (ProductImportService, *importProductRepository) {
+	productRepo := &importProductRepository{
+		memoryProductRepository: newMemoryProductRepository(domain.Product{ID: 1, Name: "Kopi", CategoryID: 1, Price: 10000}),
+		importErrs:              importErrs,
+		nextID:                  100,
+	}
+	categoryRepo := newMemoryCategoryRepository(domain.Category{ID: 1, Name: "Minuman"})
+	return NewProductImportService(productRepo, categoryRepo), productRepo
+}
+
+func TestParseCSV(t *testing.T) {
+	s, _ := newImportTestService()
+	input := "\ufeffCategory, Name ,price,notes\nMinuman,Teh,8000,dingin\nMakanan, Roti \n"
+
+	rows, err := s.ParseCSV(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []domain.ProductImportRow{
+		{Line: 2, Name: "Teh", Price: "8000", Category: "Minuman"},
+		{Line: 3, Name: "Roti", Price: "", Category: "Makanan"},
+	}
+	if len(rows) != len(want) {
+		t.Fatalf("expected %d rows, got %+v", len(want), rows)
+	}
+	for i := range want {
+		if rows[i] != want[i] {
+			t.Errorf("row %d = %+v, want %+v", i, rows[i], want[i])
+		}
+	}
+}
+
+func TestParseCSVInvalidFile(t *testing.T) {
+	s, _ := newImportTestService()
+	for _, input := range []string{"", "name,price\nTeh,8000\n"} {
+		if _, err := s.ParseCSV(strings.NewReader(input)); !errors.Is(err, ErrInvalidImportFile) {
+			t.Errorf("ParseCSV(%q) error = %v, want ErrInvalidImportFile", input, err)
+		}
+	}
+
+	tooLarge := "name,price,category\n" + strings.Repeat("Teh,8000,Minuman\n", maxImportRows+1)
+	if _, err := s.ParseCSV(strings.NewReader(tooLarge)); !errors.Is(err, ErrImportTooLarge) {
+		t.Errorf("expected ErrImportTooLarge, got %v", err)
+	}
+}
+
+func TestImportValidatesPrice(t *testing.T) {
+	tests := []struct {
+		price string
+		valid bool
+	}{
+		{"8000", true},
+		{"8000.50", true},
+		{"", false},
+		{"0", false},
+		{"-1", false},
+		{"abc", false},
+		{"NaN", false},
+		{"Inf", false},
+		{"+Inf", false},
+		{"-Inf", false},
+		{"1e400", false},
+	}
+	for _, tt := range tests {
+		s, _ := newImportTestService()
+		rows := []domain.ProductImportRow{{Line: 2, Name: "Teh", Price: tt.price, Category: "Minuman"}}
+
+		report, err := s.Import(context.Background(), rows, &domain.ProductImportForm{DryRun: true})
+		if err != nil {
+			t.Fatal(err)
+		}
+		result := report.Rows[0]
+		if tt.valid != (result.Action == domain.ProductImportActionCreate) {
+			t.Errorf("price %q: expected valid = %v, got %+v", tt.price, tt.valid, result)
+		}
+		if !tt.valid && result.Errors["price"] == "" {
+			t.Errorf("price %q: expected a price error, got %+v", tt.price, result.Errors)
+		}
+	}
+}
+
+func TestImportValidatesRows(t *testing.T) {
+	s, _ := newImportTestService()
+	rows := []domain.ProductImportRow{
+		{Line: 2, Name: "Teh", Price: "8000", Category: "Minuman"},
+		{Line: 3, Name: "teh", Price: "9000", Category: "minuman"},
+		{Line: 4, Name: "Kopi", Price: "12000", Category: "Minuman"},
+		{Line: 5, Name: "Roti", Price: "15000", Category: ""},
+		{Line: 6, Name: "", Price: "15000", Category: "Makanan"},
+		{Line: 7, Name: "Roti", Price: "15000", Category: "Makanan"},
+	}
+
+	report, err := s.Import(context.Background(), rows, &domain.ProductImportForm{DryRun: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		action domain.ProductImportAction
+		field  string
+	}{
+		{domain.ProductImportActionCreate, ""},
+		{domain.ProductImportActionSkip, "name"}, // duplikat di file
+		{domain.ProductImportActionSkip, "name"}, // sudah ada di database
+		{domain.ProductImportActionSkip, "category"},
+		{domain.ProductImportActionSkip, "name"},
+		{domain.ProductImportActionCreate, ""}, // kategori baru dibuat saat import
+	}
+	for i, w := range want {
+		result := report.Rows[i]
+		if result.Action != w.action || (w.field != "" && result.Errors[w.field] == "") {
+			t.Errorf("line %d: expected %s with %q error, got %+v", rows[i].Line, w.action, w.field, result)
+		}
+	}
+	if report.Valid != 2 || report.Invalid != 4 {
+		t.Fatalf("expected 2 valid and 4 invalid rows, got %d and %d", report.Valid, report.Invalid)
+	}
+}
+
+func TestImportUpsertUpdatesExistingProduct(t *testing.T) {
+	s, productRepo := newImportTestService()
+	rows := []domain.ProductImportRow{{Line: 2, Name: "Kopi", Price: "12000", Category: "Minuman"}}
+
+	report, err := s.Import(context.Background(), rows, &domain.ProductImportForm{Mode: domain.ProductImportUpsert})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Updated != 1 || report.Rows[0].ProductID != 1 {
+		t.Fatalf("expected product 1 to be updated, got %+v", report)
+	}
+	if len(productRepo.imports) != 1 || productRepo.imports[0][0].Product.Price != 12000 {
+		t.Fatalf("expected the new price to be written, got %+v", productRepo.imports)
+	}
+}
+
+func TestImportAtomic(t *testing.T) {
+	valid := []domain.ProductImportRow{
+		{Line: 2, Name: "Teh", Price: "8000", Category: "Minuman"},
+		{Line: 3, Name: "Susu", Price: "9000", Category: "Minuman"},
+	}
+
+	s, productRepo := newImportTestService()
+	report, err := s.Import(context.Background(), valid, &domain.ProductImportForm{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Commit != domain.ProductImportAtomic || report.Created != 2 || len(productRepo.imports) != 1 {
+		t.Fatalf("expected both rows in one transaction, got %+v", report)
+	}
+	if report.Rows[0].ProductID == 0 || report.Rows[1].ProductID == 0 {
+		t.Fatalf("expected product IDs in the report, got %+v", report.Rows)
+	}
+
+	s, productRepo = newImportTestService()
+	withInvalid := append(append([]domain.ProductImportRow(nil), valid...),
+		domain.ProductImportRow{Line: 4, Name: "Air", Price: "Inf", Category: "Minuman"})
+	report, err = s.Import(context.Background(), withInvalid, &domain.ProductImportForm{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(productRepo.imports) != 0 || report.Created != 0 || report.Invalid != 1 {
+		t.Fatalf("expected nothing written when a row is invalid, got %+v", report)
+	}
+
+	s, _ = newImportTestService(errors.New("deadlock"))
+	if _, err := s.Import(context.Background(), valid, &domain.ProductImportForm{}); err == nil {
+		t.Fatal("expected the repository error to be returned")
+	}
+}
+
+func TestImportBatch(t *testing.T) {
+	s, productRepo := newImportTestService(nil, errors.New("deadlock"))
+	rows := []domain.ProductImportRow{
+		{Line: 2, Name: "Teh", Price: "8000", Category: "Minuman"},
+		{Line: 3, Name: "Susu", Price: "NaN", Category: "Minuman"},
+		{Line: 4, Name: "Jus", Price: "9000", Category: "Minuman"},
+		{Line: 5, Name: "Soda", Price: "7000", Category: "Minuman"},
+		{Line: 6, Name: "Air", Price: "3000", Category: "Minuman"},
+	}
+
+	form := &domain.ProductImportForm{Commit: domain.ProductImportBatch, BatchSize: 2}
+	report, err := s.Import(context.Background(), rows, form)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(productRepo.imports) != 2 {
+		t.Fatalf("expected 2 batches, got %d", len(productRepo.imports))
+	}
+	if report.Created != 2 || report.Failed != 2 || report.Invalid != 1 {
+		t.Fatalf("expected 2 created, 2 failed and 1 invalid, got %+v", report)
+	}
+
+	wantActions := []domain.ProductImportAction{
+		domain.ProductImportActionCreate,
+		domain.ProductImportActionSkip,
+		domain.ProductImportActionCreate,
+		domain.ProductImportActionSkip,
+		domain.ProductImportActionSkip,
+	}
+	for i, want := range wantActions {
+		if report.Rows[i].Action != want {
+			t.Errorf("line %d: action = %s, want %s", rows[i].Line, report.Rows[i].Action, want)
+		}
+	}
+	// Batch kedua gagal: barisnya dilewati dengan error dari repository
+	for _, i := range []int{3, 4} {
+		if report.Rows[i].Errors["row"] != "deadlock" || report.Rows[i].ProductID != 0 {
+			t.Errorf("line %d: expected the batch error, got %+v", rows[i].Line, report.Rows[i])
+		}
+	}
+}
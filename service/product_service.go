@@ -1,47 +1,577 @@
 package service
 
 import (
+	"context"
 	"crud-clean-architecture/domain"
 	"crud-clean-architecture/repository"
+	"crud-clean-architecture/storage"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
 )
 
+var ErrPriceNotInFuture = errors.New("effective_from must be in the future")
+
 type ProductService interface {
-	CreateProduct(product *domain.Product) error
-	GetAllProducts() ([]domain.Product, error)
+	CreateProduct(ctx context.Context, product *domain.Product) error
+	GetAllProducts(locale string) ([]domain.Product, error)
+	GetProducts(filter domain.ProductFilter, locale string) ([]domain.Product, error)
+	GetFacets(filter domain.ProductFilter) (*domain.ProductFacets, error)
+	ResolveAvailability(filter *domain.ProductFilter) error
+	ApplyBatch(ctx context.Context, ops []domain.ProductBatchOperation) ([]domain.BatchResult, error)
 	GetProductByID(id uint) (*domain.Product, error)
-	UpdateProduct(product *domain.Product) error
-	DeleteProduct(id uint) error
+	ResolveProduct(idOrSlug string) (uint, string, error)
+	UpdateProduct(ctx context.Context, product *domain.Product) error
+	DeleteProduct(ctx context.Context, id uint, version uint) error
+	PatchProduct(ctx context.Context, id uint, version uint, patch []byte) (*domain.Product, error)
 	IsProductNameUnique(name string, categori_id uint) (bool, error)
+	GetPriceHistory(id uint) ([]domain.ProductPrice, error)
+	SchedulePrice(ctx context.Context, id uint, form *domain.ProductPriceForm) (*domain.ProductPrice, error)
+	CancelScheduledPrice(ctx context.Context, id, priceID uint) error
+	ApplyScheduledPrices(ctx context.Context) (int, error)
+	GetVariants(id uint) ([]domain.ProductVariant, error)
+	CreateVariant(ctx context.Context, id uint, form *domain.ProductVariantForm) (*domain.ProductVariant, error)
+	UpdateVariant(ctx context.Context, id, variantID uint, form *domain.ProductVariantForm) (*domain.ProductVariant, error)
+	DeleteVariant(ctx context.Context, id, variantID uint) error
+	GetModifierGroups(id uint) ([]domain.ModifierGroup, error)
+	CreateModifierGroup(ctx context.Context, id uint, form *domain.ModifierGroupForm) (*domain.ModifierGroup, error)
+	UpdateModifierGroup(ctx context.Context, id, groupID uint, form *domain.ModifierGroupForm) (*domain.ModifierGroup, error)
+	DeleteModifierGroup(ctx context.Context, id, groupID uint) error
+	SetBundleComponents(ctx context.Context, id uint, components []domain.BundleComponentForm) (*domain.Product, error)
+	SetTags(ctx context.Context, id uint, tagIDs []uint) (*domain.Product, error)
+	RemoveTag(ctx context.Context, id, tagID uint) (*domain.Product, error)
+	SetTranslation(ctx context.Context, id uint, version uint, locale string, form *domain.TranslationForm) (*domain.Product, error)
+	SetAvailability(ctx context.Context, id uint, form *domain.AvailabilityForm) (*domain.Product, error)
 }
 
 type productService struct {
-	productRepo repository.ProductRepository
+	productRepo  repository.ProductRepository
+	categoryRepo repository.CategoryRepository
+	tagRepo      repository.TagRepository
+	storage      storage.Storage
+}
+
+func NewProductService(productRepo repository.ProductRepository, categoryRepo repository.CategoryRepository,
+	tagRepo repository.TagRepository, storage storage.Storage) ProductService {
+	return &productService{productRepo, categoryRepo, tagRepo, storage}
+}
+
+func (s *productService) CreateProduct(ctx context.Context, product *domain.Product) error {
+	attributes, err := s.checkAttributes(product.CategoryID, product.Attributes)
+	if err != nil {
+		return err
+	}
+	product.Attributes = attributes
+	return s.productRepo.CreateProduct(ctx, product)
 }
 
-func NewProductService(productRepo repository.ProductRepository) ProductService {
-	return &productService{productRepo}
+// ResolveProduct returns the ID of the product named by an ID or slug, and
+// the current slug when idOrSlug is one of its old slugs.
+func (s *productService) ResolveProduct(idOrSlug string) (uint, string, error) {
+	return resolveSlug(idOrSlug, s.productRepo.FindSlug, func(id uint) (string, error) {
+		product, err := s.productRepo.GetProductByID(id)
+		if err != nil {
+			return "", repository.ErrProductNotFound
+		}
+		return product.Slug, nil
+	})
 }
 
-func (s *productService) CreateProduct(product *domain.Product) error {
-	return s.productRepo.CreateProduct(product)
+func (s *productService) GetAllProducts(locale string) ([]domain.Product, error) {
+	return s.productRepo.GetAllProducts(locale)
 }
 
-func (s *productService) GetAllProducts() ([]domain.Product, error) {
-	return s.productRepo.GetAllProducts()
+// PatchProduct applies a JSON merge patch, validates the merged product with
+// the ProductForm rules and saves only the columns that changed.
+func (s *productService) PatchProduct(ctx context.Context, id uint, version uint, patch []byte) (*domain.Product, error) {
+	current, err := s.productRepo.GetProductByID(id)
+	if err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+
+	form := domain.ProductForm{Name: current.Name, Price: current.Price, CategoryID: current.CategoryID,
+		Description: current.Description, Attributes: current.Attributes}
+	var merged domain.ProductForm
+	if err := applyMergePatch(form, patch, &merged); err != nil {
+		return nil, err
+	}
+
+	changes := map[string]interface{}{}
+	if merged.Name != current.Name {
+		changes["name"] = merged.Name
+	}
+	if merged.Description != current.Description {
+		changes["description"] = merged.Description
+	}
+	if merged.Price != current.Price {
+		changes["price"] = merged.Price
+	}
+	if merged.CategoryID != current.CategoryID {
+		changes["category_id"] = merged.CategoryID
+	}
+	// Atribut divalidasi ulang saat nilainya atau kategorinya berubah
+	if changes["category_id"] != nil || !reflect.DeepEqual(merged.Attributes, current.Attributes) {
+		attributes, err := s.checkAttributes(merged.CategoryID, merged.Attributes)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(attributes, current.Attributes) {
+			changes["attributes"] = attributes
+		}
+	}
+
+	// Nama harus tetap unik di kategori tujuan, sama seperti saat create
+	if changes["name"] != nil || changes["category_id"] != nil {
+		isUnique, err := s.productRepo.IsProductNameUnique(merged.Name, merged.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+		if !isUnique {
+			return nil, &ValidationError{Errors: map[string]string{"name": "name must be unique"}}
+		}
+	}
+
+	return s.productRepo.PatchProduct(ctx, id, version, changes)
+}
+
+// GetProducts serves unfiltered requests from the cached list and localizes
+// the products for locale.
+func (s *productService) GetProducts(filter domain.ProductFilter, locale string) ([]domain.Product, error) {
+	if filter.IsEmpty() {
+		return s.productRepo.GetAllProducts(locale)
+	}
+	products, err := s.productRepo.GetProducts(filter)
+	if err != nil {
+		return nil, err
+	}
+	for i := range products {
+		products[i].Localize(locale)
+	}
+	return products, nil
+}
+
+// GetFacets returns the counts per category, price range, tag and attribute
+// value for the product list filter.
+func (s *productService) GetFacets(filter domain.ProductFilter) (*domain.ProductFacets, error) {
+	return s.productRepo.GetFacets(filter)
+}
+
+// ResolveAvailability evaluates the availability schedules for filter once, so
+// the list and facet queries of the same request reuse the result.
+func (s *productService) ResolveAvailability(filter *domain.ProductFilter) error {
+	return s.productRepo.ResolveAvailability(filter)
 }
 
 func (s *productService) GetProductByID(id uint) (*domain.Product, error) {
 	return s.productRepo.GetProductByID(id)
 }
 
-func (s *productService) UpdateProduct(product *domain.Product) error {
-	return s.productRepo.UpdateProduct(product)
+func (s *productService) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	if _, err := s.productRepo.GetProductByID(product.ID); err != nil {
+		return repository.ErrProductNotFound
+	}
+	// Nama harus unik di kategori tujuan; nama produk itu sendiri boleh dipakai lagi
+	existing, err := s.productRepo.GetProductByName(product.Name, product.CategoryID)
+	if err != nil {
+		return err
+	}
+	if existing != nil && existing.ID != product.ID {
+		return &ValidationError{Errors: map[string]string{"name": "name must be unique"}}
+	}
+	attributes, err := s.checkAttributes(product.CategoryID, product.Attributes)
+	if err != nil {
+		return err
+	}
+	product.Attributes = attributes
+	return s.productRepo.UpdateProduct(ctx, product)
 }
 
-func (s *productService) DeleteProduct(id uint) error {
-	return s.productRepo.DeleteProduct(id)
+func (s *productService) DeleteProduct(ctx context.Context, id uint, version uint) error {
+	images, err := s.productRepo.DeleteProduct(ctx, id, version)
+	if err != nil {
+		return err
+	}
+	removeImageFiles(s.storage, images...)
+	return nil
 }
 
 func (s *productService) IsProductNameUnique(name string, categori_id uint) (bool, error) {
 	return s.productRepo.IsProductNameUnique(name, categori_id)
 }
+
+// ApplyBatch validates every operation first and then applies them all in one
+// transaction. When any operation is invalid or fails, nothing is applied and
+// ErrBatchRejected is returned together with the per-item results.
+func (s *productService) ApplyBatch(ctx context.Context, ops []domain.ProductBatchOperation) ([]domain.BatchResult, error) {
+	results := make([]domain.BatchResult, len(ops))
+	failed := map[int]map[string]string{}
+	claimed := map[string]int{}
+	vacated := batchVacatedIDs(len(ops), func(i int) (domain.BatchOp, uint) { return ops[i].Op, ops[i].ID })
+
+	for i, op := range ops {
+		results[i] = domain.BatchResult{Index: i, Op: op.Op, ID: op.ID}
+		errs := validateBatchOperation(&op, op.Op, op.ID, op.Data != nil)
+		if len(errs) == 0 && op.Op != domain.BatchOpDelete {
+			attributes, err := s.checkAttributes(op.Data.CategoryID, op.Data.Attributes)
+			var validationErr *ValidationError
+			if errors.As(err, &validationErr) {
+				errs = validationErr.Errors
+			} else if err != nil {
+				return nil, err
+			}
+			op.Data.Attributes = attributes
+		}
+
+		// Keunikan nama dicek seperti pada POST /products untuk create dan update,
+		// termasuk antar item di batch. Produk yang diubah atau dihapus di batch yang
+		// sama tidak lagi memegang namanya yang lama.
+		if len(errs) == 0 && op.Op != domain.BatchOpDelete {
+			key := fmt.Sprintf("%d:%s", op.Data.CategoryID, op.Data.Name)
+			if index, ok := claimed[key]; ok {
+				errs["name"] = fmt.Sprintf("name is duplicated in operation %d", index)
+			} else {
+				claimed[key] = i
+				existing, err := s.productRepo.GetProductByName(op.Data.Name, op.Data.CategoryID)
+				if err != nil {
+					return nil, err
+				}
+				if existing != nil && existing.ID != op.ID && !vacated[existing.ID] {
+					errs["name"] = "name must be unique"
+				}
+			}
+		}
+		if len(errs) > 0 {
+			failed[i] = errs
+		}
+	}
+	if len(failed) > 0 {
+		return rejectBatch(results, failed)
+	}
+
+	products, err := s.productRepo.ApplyBatch(ctx, ops)
+	if err != nil {
+		if failed, ok := batchItemFailure(err); ok {
+			return rejectBatch(results, failed)
+		}
+		return nil, err
+	}
+
+	for i, product := range products {
+		results[i].ID = product.ID
+		results[i].Status = domain.BatchStatusOK
+		if ops[i].Op != domain.BatchOpDelete {
+			results[i].Data = product
+		} else {
+			removeImageFiles(s.storage, product.Images...)
+		}
+	}
+	return results, nil
+}
+
+// checkAttributes validates attribute values against the schema of the
+// category and returns them without null values.
+func (s *productService) checkAttributes(categoryID uint, values map[string]interface{}) (map[string]interface{}, error) {
+	category, err := s.categoryRepo.GetCategoryByID(categoryID)
+	if err != nil {
+		return nil, &ValidationError{Errors: map[string]string{"category_id": repository.ErrCategoryNotFound.Error()}}
+	}
+
+	attributes, errs := validateAttributes(category.AttributeSchema, values)
+	if len(errs) > 0 {
+		return nil, &ValidationError{Errors: errs}
+	}
+	return attributes, nil
+}
+
+func (s *productService) GetPriceHistory(id uint) ([]domain.ProductPrice, error) {
+	if _, err := s.productRepo.GetProductByID(id); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	return s.productRepo.GetPriceHistory(id)
+}
+
+// SchedulePrice stores a future price; it is applied to the product by
+// ApplyScheduledPrices and used for orders placed after effective_from.
+func (s *productService) SchedulePrice(ctx context.Context, id uint, form *domain.ProductPriceForm) (*domain.ProductPrice, error) {
+	effectiveFrom, err := time.Parse(time.RFC3339, form.EffectiveFrom)
+	if err != nil {
+		return nil, err
+	}
+	if !effectiveFrom.After(time.Now()) {
+		return nil, ErrPriceNotInFuture
+	}
+
+	price := &domain.ProductPrice{ProductID: id, Price: form.Price, EffectiveFrom: effectiveFrom}
+	if err := s.productRepo.SchedulePrice(ctx, price); err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+func (s *productService) CancelScheduledPrice(ctx context.Context, id, priceID uint) error {
+	_, err := s.productRepo.DeleteScheduledPrice(ctx, id, priceID)
+	return err
+}
+
+// ApplyScheduledPrices copies due scheduled prices to the products and
+// returns how many products changed.
+func (s *productService) ApplyScheduledPrices(ctx context.Context) (int, error) {
+	return s.productRepo.ApplyDuePrices(ctx, time.Now())
+}
+
+func (s *productService) GetVariants(id uint) ([]domain.ProductVariant, error) {
+	if _, err := s.productRepo.GetProductByID(id); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	return s.productRepo.GetVariants(id)
+}
+
+func (s *productService) CreateVariant(ctx context.Context, id uint, form *domain.ProductVariantForm) (*domain.ProductVariant, error) {
+	product, err := s.productRepo.GetProductByID(id)
+	if err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	if product.Type == domain.ProductTypeBundle {
+		return nil, &ValidationError{Errors: map[string]string{"product": "bundle products cannot have variants"}}
+	}
+	if err := s.checkSKU(form.SKU, 0); err != nil {
+		return nil, err
+	}
+	variant := &domain.ProductVariant{
+		ProductID:  id,
+		SKU:        form.SKU,
+		Name:       form.Name,
+		Price:      form.Price,
+		Stock:      form.Stock,
+		Attributes: form.Attributes,
+	}
+	if err := s.productRepo.CreateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+func (s *productService) UpdateVariant(ctx context.Context, id, variantID uint, form *domain.ProductVariantForm) (*domain.ProductVariant, error) {
+	if _, err := s.productRepo.GetVariantByID(id, variantID); err != nil {
+		return nil, err
+	}
+	if err := s.checkSKU(form.SKU, variantID); err != nil {
+		return nil, err
+	}
+
+	variant := &domain.ProductVariant{
+		ID:         variantID,
+		ProductID:  id,
+		SKU:        form.SKU,
+		Name:       form.Name,
+		Price:      form.Price,
+		Stock:      form.Stock,
+		Attributes: form.Attributes,
+	}
+	if err := s.productRepo.UpdateVariant(ctx, variant); err != nil {
+		return nil, err
+	}
+	return variant, nil
+}
+
+func (s *productService) DeleteVariant(ctx context.Context, id, variantID uint) error {
+	return s.productRepo.DeleteVariant(ctx, id, variantID)
+}
+
+// checkSKU memastikan SKU unik di seluruh produk
+func (s *productService) checkSKU(sku string, variantID uint) error {
+	isUnique, err := s.productRepo.IsSKUUnique(sku, variantID)
+	if err != nil {
+		return err
+	}
+	if !isUnique {
+		return &ValidationError{Errors: map[string]string{"sku": "sku must be unique"}}
+	}
+	return nil
+}
+
+func (s *productService) GetModifierGroups(id uint) ([]domain.ModifierGroup, error) {
+	if _, err := s.productRepo.GetProductByID(id); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	return s.productRepo.GetModifierGroups(id)
+}
+
+func (s *productService) CreateModifierGroup(ctx context.Context, id uint, form *domain.ModifierGroupForm) (*domain.ModifierGroup, error) {
+	if err := validateModifierGroup(form); err != nil {
+		return nil, err
+	}
+	group := modifierGroupFromForm(form)
+	group.ProductID = id
+	if err := s.productRepo.CreateModifierGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *productService) UpdateModifierGroup(ctx context.Context, id, groupID uint, form *domain.ModifierGroupForm) (*domain.ModifierGroup, error) {
+	if err := validateModifierGroup(form); err != nil {
+		return nil, err
+	}
+	group := modifierGroupFromForm(form)
+	group.ID = groupID
+	group.ProductID = id
+	if err := s.productRepo.UpdateModifierGroup(ctx, group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+func (s *productService) DeleteModifierGroup(ctx context.Context, id, groupID uint) error {
+	return s.productRepo.DeleteModifierGroup(ctx, id, groupID)
+}
+
+// validateModifierGroup checks the rules the binding tags cannot express.
+func validateModifierGroup(form *domain.ModifierGroupForm) error {
+	if form.MinSelect > len(form.Options) {
+		return &ValidationError{Errors: map[string]string{"min_select": "min_select cannot exceed the number of options"}}
+	}
+	names := map[string]bool{}
+	for _, option := range form.Options {
+		if names[option.Name] {
+			return &ValidationError{Errors: map[string]string{"options": "option names must be unique"}}
+		}
+		names[option.Name] = true
+	}
+	return nil
+}
+
+func modifierGroupFromForm(form *domain.ModifierGroupForm) *domain.ModifierGroup {
+	group := &domain.ModifierGroup{Name: form.Name, MinSelect: form.MinSelect, MaxSelect: form.MaxSelect}
+	for _, option := range form.Options {
+		group.Options = append(group.Options, domain.ModifierOption{Name: option.Name, PriceDelta: option.PriceDelta})
+	}
+	return group
+}
+
+// SetBundleComponents replaces the components of a bundle. Components must be
+// simple products, name a variant when they have variants and appear once.
+// An empty list turns the bundle back into a simple product.
+func (s *productService) SetBundleComponents(ctx context.Context, id uint, components []domain.BundleComponentForm) (*domain.Product, error) {
+	product, err := s.productRepo.GetProductByID(id)
+	if err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	if len(components) > 0 && len(product.Variants) > 0 {
+		return nil, &ValidationError{Errors: map[string]string{"components": "products with variants cannot be bundles"}}
+	}
+
+	bundle := make([]domain.BundleComponent, len(components))
+	seen := map[string]bool{}
+	for i, form := range components {
+		field := fmt.Sprintf("components[%d]", i)
+		if form.ProductID == id {
+			return nil, &ValidationError{Errors: map[string]string{field: "a bundle cannot contain itself"}}
+		}
+		component, err := s.productRepo.GetProductByID(form.ProductID)
+		if err != nil {
+			return nil, &ValidationError{Errors: map[string]string{field: "product not found"}}
+		}
+		if component.Type == domain.ProductTypeBundle {
+			return nil, &ValidationError{Errors: map[string]string{field: "a bundle cannot contain another bundle"}}
+		}
+		if err := checkComponentVariant(component, form.VariantID); err != nil {
+			return nil, &ValidationError{Errors: map[string]string{field: err.Error()}}
+		}
+
+		key := fmt.Sprint(form.ProductID)
+		if form.VariantID != nil {
+			key += fmt.Sprintf(":%d", *form.VariantID)
+		}
+		if seen[key] {
+			return nil, &ValidationError{Errors: map[string]string{field: "component is listed more than once"}}
+		}
+		seen[key] = true
+		bundle[i] = domain.BundleComponent{ComponentID: form.ProductID, VariantID: form.VariantID, Quantity: form.Quantity}
+	}
+
+	return s.productRepo.SetBundleComponents(ctx, id, bundle)
+}
+
+// SetTags replaces the tags of a product. Duplicate IDs are ignored.
+func (s *productService) SetTags(ctx context.Context, id uint, tagIDs []uint) (*domain.Product, error) {
+	if _, err := s.productRepo.GetProductByID(id); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+
+	var unique []uint
+	seen := map[uint]bool{}
+	for _, tagID := range tagIDs {
+		if !seen[tagID] {
+			seen[tagID] = true
+			unique = append(unique, tagID)
+		}
+	}
+	tags, err := s.tagRepo.GetTagsByIDs(unique)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) != len(unique) {
+		found := map[uint]bool{}
+		for _, tag := range tags {
+			found[tag.ID] = true
+		}
+		for _, tagID := range unique {
+			if !found[tagID] {
+				return nil, &ValidationError{Errors: map[string]string{"tag_ids": fmt.Sprintf("tag %d not found", tagID)}}
+			}
+		}
+	}
+
+	return s.productRepo.SetTags(ctx, id, unique)
+}
+
+func (s *productService) RemoveTag(ctx context.Context, id, tagID uint) (*domain.Product, error) {
+	return s.productRepo.RemoveTag(ctx, id, tagID)
+}
+
+// SetAvailability replaces the availability schedule of a product; a nil
+// form makes it always available.
+func (s *productService) SetAvailability(ctx context.Context, id uint, form *domain.AvailabilityForm) (*domain.Product, error) {
+	if _, err := s.productRepo.GetProductByID(id); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+
+	var schedule *domain.AvailabilitySchedule
+	if form != nil {
+		var err error
+		if schedule, err = validateAvailability(form); err != nil {
+			return nil, err
+		}
+	}
+	return s.productRepo.SetAvailability(ctx, id, schedule)
+}
+
+func checkComponentVariant(component *domain.Product, variantID *uint) error {
+	if variantID == nil {
+		if len(component.Variants) > 0 {
+			return errors.New("variant_id is required for products with variants")
+		}
+		return nil
+	}
+	for _, variant := range component.Variants {
+		if variant.ID == *variantID {
+			return nil
+		}
+	}
+	return errors.New("variant does not belong to the product")
+}
+
+// SetTranslation sets the name of the product in locale; a nil form removes
+// the translation.
+func (s *productService) SetTranslation(ctx context.Context, id uint, version uint, locale string, form *domain.TranslationForm) (*domain.Product, error) {
+	if _, err := s.productRepo.GetProductByID(id); err != nil {
+		return nil, repository.ErrProductNotFound
+	}
+	translation, err := checkTranslation(locale, form)
+	if err != nil {
+		return nil, err
+	}
+	return s.productRepo.SetTranslation(ctx, id, version, locale, translation)
+}
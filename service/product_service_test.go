@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"errors"
+	"testing"
+)
+
+// memoryProductRepository menyimpan produk di memori untuk pengecekan nama,
+// harga dan ketersediaan; ApplyBatch hanya mencatat batch yang diterima.
+type memoryProductRepository struct {
+	repository.ProductRepository
+	products map[uint]*domain.Product
+	batches  [][]domain.ProductBatchOperation
+}
+
+func newMemoryProductRepository(products ...domain.Product) *memoryProductRepository {
+	repo := &memoryProductRepository{products: map[uint]*domain.Product{}}
+	for i := range products {
+		repo.products[products[i].ID] = &products[i]
+	}
+	return repo
+}
+
+func (r *memoryProductRepository) GetProductByID(id uint) (*domain.Product, error) {
+	product, ok := r.products[id]
+	if !ok {
+		return nil, repository.ErrProductNotFound
+	}
+	copied := *product
+	return &copied, nil
+}
+
+func (r *memoryProductRepository) GetProductByName(name string, categoryID uint) (*domain.Product, error) {
+	for _, product := range r.products {
+		if product.Name == name && product.CategoryID == categoryID {
+			copied := *product
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryProductRepository) IsProductNameUnique(name string, categoryID uint) (bool, error) {
+	product, err := r.GetProductByName(name, categoryID)
+	return product == nil, err
+}
+
+func (r *memoryProductRepository) ApplyBatch(ctx context.Context, ops []domain.ProductBatchOperation) ([]domain.Product, error) {
+	r.batches = append(r.batches, ops)
+	products := make([]domain.Product, len(ops))
+	for i, op := range ops {
+		products[i].ID = op.ID
+	}
+	return products, nil
+}
+
+func (r *memoryProductRepository) UpdateProduct(ctx context.Context, product *domain.Product) error {
+	copied := *product
+	r.products[product.ID] = &copied
+	return nil
+}
+
+// memoryCategoryRepository menyimpan kategori di memori
+type memoryCategoryRepository struct {
+	repository.CategoryRepository
+	categories map[uint]*domain.Category
+}
+
+func newMemoryCategoryRepository(categories ...domain.Category) *memoryCategoryRepository {
+	repo := &memoryCategoryRepository{categories: map[uint]*domain.Category{}}
+	for i := range categories {
+		repo.categories[categories[i].ID] = &categories[i]
+	}
+	return repo
+}
+
+func (r *memoryCategoryRepository) GetCategoryByID(id uint) (*domain.Category, error) {
+	category, ok := r.categories[id]
+	if !ok {
+		return nil, repository.ErrCategoryNotFound
+	}
+	copied := *category
+	return &copied, nil
+}
+
+func (r *memoryCategoryRepository) GetCategoryByName(name string) (*domain.Category, error) {
+	for _, category := range r.categories {
+		if category.Name == name {
+			copied := *category
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestProductApplyBatchNameUniqueness(t *testing.T) {
+	form := func(name string) *domain.ProductForm {
+		return &domain.ProductForm{Name: name, Price: 10000, CategoryID: 1}
+	}
+	tests := []struct {
+		name   string
+		ops    []domain.ProductBatchOperation
+		failed map[int]string
+	}{
+		{
+			name:   "update to a name taken in the database",
+			ops:    []domain.ProductBatchOperation{{Op: domain.BatchOpUpdate, ID: 1, Data: form("Teh")}},
+			failed: map[int]string{0: "name must be unique"},
+		},
+		{
+			name: "update keeping its own name",
+			ops:  []domain.ProductBatchOperation{{Op: domain.BatchOpUpdate, ID: 1, Data: form("Kopi")}},
+		},
+		{
+			name: "two updates to the same new name",
+			ops: []domain.ProductBatchOperation{
+				{Op: domain.BatchOpUpdate, ID: 1, Data: form("Susu")},
+				{Op: domain.BatchOpUpdate, ID: 2, Data: form("Susu")},
+			},
+			failed: map[int]string{1: "name is duplicated in operation 0"},
+		},
+		{
+			name: "create and update to the same new name",
+			ops: []domain.ProductBatchOperation{
+				{Op: domain.BatchOpCreate, Data: form("Susu")},
+				{Op: domain.BatchOpUpdate, ID: 2, Data: form("Susu")},
+			},
+			failed: map[int]string{1: "name is duplicated in operation 0"},
+		},
+		{
+			name: "create with a name kept by an update",
+			ops: []domain.ProductBatchOperation{
+				{Op: domain.BatchOpUpdate, ID: 1, Data: form("Kopi")},
+				{Op: domain.BatchOpCreate, Data: form("Kopi")},
+			},
+			failed: map[int]string{1: "name is duplicated in operation 0"},
+		},
+		{
+			name: "name freed by a rename in the same batch",
+			ops: []domain.ProductBatchOperation{
+				{Op: domain.BatchOpUpdate, ID: 1, Data: form("Kopi Susu")},
+				{Op: domain.BatchOpUpdate, ID: 2, Data: form("Kopi")},
+			},
+		},
+		{
+			name: "name freed by a delete in the same batch",
+			ops: []domain.ProductBatchOperation{
+				{Op: domain.BatchOpDelete, ID: 2},
+				{Op: domain.BatchOpCreate, Data: form("Teh")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := newMemoryProductRepository(
+				domain.Product{ID: 1, Name: "Kopi", CategoryID: 1},
+				domain.Product{ID: 2, Name: "Teh", CategoryID: 1},
+			)
+			s := NewProductService(productRepo, newMemoryCategoryRepository(domain.Category{ID: 1, Name: "Minuman"}), nil, nil)
+
+			results, err := s.ApplyBatch(context.Background(), tt.ops)
+			if len(tt.failed) == 0 {
+				if err != nil {
+					t.Fatalf("expected batch to be applied, got %v: %+v", err, results)
+				}
+				if len(productRepo.batches) != 1 {
+					t.Fatalf("expected batch to reach the repository")
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrBatchRejected) {
+				t.Fatalf("expected ErrBatchRejected, got %v", err)
+			}
+			if len(productRepo.batches) != 0 {
+				t.Fatalf("rejected batch must not reach the repository")
+			}
+			for i, result := range results {
+				want, failed := tt.failed[i]
+				if !failed {
+					if result.Status != domain.BatchStatusRolledBack {
+						t.Fatalf("operation %d: expected rolled back, got %+v", i, result)
+					}
+					continue
+				}
+				if result.Status != domain.BatchStatusFailed || result.Errors["name"] != want {
+					t.Fatalf("operation %d: expected name error %q, got %+v", i, want, result)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateProductNameUniqueness(t *testing.T) {
+	tests := []struct {
+		name       string
+		product    domain.Product
+		wantUnique bool
+	}{
+		{"keeps its own name", domain.Product{ID: 1, Name: "Kopi", Price: 12000, CategoryID: 1}, true},
+		{"takes a free name", domain.Product{ID: 1, Name: "Kopi Susu", Price: 12000, CategoryID: 1}, true},
+		{"takes the name of another product", domain.Product{ID: 1, Name: "Teh", Price: 12000, CategoryID: 1}, false},
+		{"same name in another category", domain.Product{ID: 1, Name: "Teh", Price: 12000, CategoryID: 2}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			productRepo := newMemoryProductRepository(
+				domain.Product{ID: 1, Name: "Kopi", CategoryID: 1},
+				domain.Product{ID: 2, Name: "Teh", CategoryID: 1},
+			)
+			categoryRepo := newMemoryCategoryRepository(domain.Category{ID: 1, Name: "Minuman"}, domain.Category{ID: 2, Name: "Makanan"})
+			s := NewProductService(productRepo, categoryRepo, nil, nil)
+
+			product := tt.product
+			err := s.UpdateProduct(context.Background(), &product)
+			if tt.wantUnique {
+				if err != nil {
+					t.Fatalf("expected update to succeed, got %v", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Errors["name"] != "name must be unique" {
+				t.Fatalf("expected name must be unique, got %v", err)
+			}
+			if productRepo.products[1].Name != "Kopi" {
+				t.Fatalf("rejected update must not reach the repository")
+			}
+		})
+	}
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidReportRange = errors.New("from must not be after to")
+	ErrReportRangeTooLong = errors.New("report range must not exceed 366 days")
+)
+
+type ReportService interface {
+	ParseFilter(form *domain.ReportQueryForm) (domain.ReportFilter, error)
+	GetRevenueSeries(filter domain.ReportFilter) ([]domain.RevenuePoint, error)
+	GetTopProducts(filter domain.ReportFilter) ([]domain.ProductSales, error)
+	GetRevenueByCategory(filter domain.ReportFilter) ([]domain.CategorySales, error)
+	GetOrderValueSummary(filter domain.ReportFilter) (*domain.OrderValueSummary, error)
+}
+
+type reportService struct {
+	reportRepo repository.ReportRepository
+}
+
+func NewReportService(reportRepo repository.ReportRepository) ReportService {
+	return &reportService{reportRepo}
+}
+
+// ParseFilter resolves the query form into absolute instants. Dates are read in
+// the requested timezone (server local time when tz is empty) and default to
+// the last 30 days including today.
+func (s *reportService) ParseFilter(form *domain.ReportQueryForm) (domain.ReportFilter, error) {
+	location := time.Local
+	if form.TZ != "" {
+		loc, err := time.LoadLocation(form.TZ)
+		if err != nil {
+			return domain.ReportFilter{}, err
+		}
+		location = loc
+	}
+
+	now := time.Now().In(location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	from := today.AddDate(0, 0, -29)
+	to := today
+
+	if form.From != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", form.From, location)
+		if err != nil {
+			return domain.ReportFilter{}, err
+		}
+		from = parsed
+	}
+	if form.To != "" {
+		parsed, err := time.ParseInLocation("2006-01-02", form.To, location)
+		if err != nil {
+			return domain.ReportFilter{}, err
+		}
+		to = parsed
+	}
+	if from.After(to) {
+		return domain.ReportFilter{}, ErrInvalidReportRange
+	}
+	if to.Sub(from) > 366*24*time.Hour {
+		return domain.ReportFilter{}, ErrReportRangeTooLong
+	}
+
+	filter := domain.ReportFilter{
+		From:     from,
+		To:       to.AddDate(0, 0, 1), // to inklusif, jadi batas atas adalah awal hari berikutnya
+		Location: location,
+		Interval: form.Interval,
+		SortBy:   form.SortBy,
+		Limit:    form.Limit,
+	}
+	if filter.Interval == "" {
+		filter.Interval = domain.ReportIntervalDay
+	}
+	if filter.SortBy == "" {
+		filter.SortBy = "quantity"
+	}
+	if filter.Limit == 0 {
+		filter.Limit = 10
+	}
+	return filter, nil
+}
+
+func (s *reportService) GetRevenueSeries(filter domain.ReportFilter) ([]domain.RevenuePoint, error) {
+	return s.reportRepo.GetRevenueSeries(filter)
+}
+
+func (s *reportService) GetTopProducts(filter domain.ReportFilter) ([]domain.ProductSales, error) {
+	return s.reportRepo.GetTopProducts(filter)
+}
+
+func (s *reportService) GetRevenueByCategory(filter domain.ReportFilter) ([]domain.CategorySales, error) {
+	return s.reportRepo.GetRevenueByCategory(filter)
+}
+
+func (s *reportService) GetOrderValueSummary(filter domain.ReportFilter) (*domain.OrderValueSummary, error) {
+	return s.reportRepo.GetOrderValueSummary(filter)
+}
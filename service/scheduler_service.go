@@ -0,0 +1,32 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/scheduler"
+)
+
+type SchedulerService interface {
+	GetJobs() ([]domain.ScheduledJob, error)
+	GetJobRuns(name string, limit int) ([]domain.ScheduledJobRun, error)
+	TriggerJob(name string) (*domain.ScheduledJobRun, error)
+}
+
+type schedulerService struct {
+	scheduler *scheduler.Scheduler
+}
+
+func NewSchedulerService(scheduler *scheduler.Scheduler) SchedulerService {
+	return &schedulerService{scheduler}
+}
+
+func (s *schedulerService) GetJobs() ([]domain.ScheduledJob, error) {
+	return s.scheduler.Jobs()
+}
+
+func (s *schedulerService) GetJobRuns(name string, limit int) ([]domain.ScheduledJobRun, error) {
+	return s.scheduler.Runs(name, limit)
+}
+
+func (s *schedulerService) TriggerJob(name string) (*domain.ScheduledJobRun, error) {
+	return s.scheduler.Trigger(name)
+}
@@ -0,0 +1,27 @@
+package service
+
+import (
+	"crud-clean-architecture/domain"
+	"strconv"
+)
+
+// resolveSlug mengubah parameter path menjadi ID. Angka dianggap ID, selain
+// itu dicari sebagai slug; slug lama mengembalikan slug aktif sebagai tujuan redirect.
+func resolveSlug(idOrSlug string, find func(slug string) (*domain.Slug, error),
+	currentSlug func(id uint) (string, error)) (uint, string, error) {
+	if id, err := strconv.ParseUint(idOrSlug, 10, 64); err == nil {
+		return uint(id), "", nil
+	}
+	slug, err := find(idOrSlug)
+	if err != nil {
+		return 0, "", err
+	}
+	if slug.Current {
+		return slug.EntityID, "", nil
+	}
+	current, err := currentSlug(slug.EntityID)
+	if err != nil {
+		return 0, "", err
+	}
+	return slug.EntityID, current, nil
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"strings"
+)
+
+type TagService interface {
+	CreateTag(ctx context.Context, form *domain.TagForm) (*domain.Tag, error)
+	GetTagCounts(filter domain.ProductFilter) ([]domain.TagCount, error)
+	GetTagByID(id uint) (*domain.Tag, error)
+	UpdateTag(ctx context.Context, id uint, form *domain.TagForm) (*domain.Tag, error)
+	DeleteTag(ctx context.Context, id uint) error
+}
+
+type tagService struct {
+	tagRepo repository.TagRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository) TagService {
+	return &tagService{tagRepo}
+}
+
+func (s *tagService) CreateTag(ctx context.Context, form *domain.TagForm) (*domain.Tag, error) {
+	name, err := s.checkName(form.Name, 0)
+	if err != nil {
+		return nil, err
+	}
+	tag := &domain.Tag{Name: name}
+	if err := s.tagRepo.CreateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+// GetTagCounts returns every tag with the number of products matching filter
+// that have it, for faceted navigation.
+func (s *tagService) GetTagCounts(filter domain.ProductFilter) ([]domain.TagCount, error) {
+	return s.tagRepo.GetTagCounts(filter)
+}
+
+func (s *tagService) GetTagByID(id uint) (*domain.Tag, error) {
+	return s.tagRepo.GetTagByID(id)
+}
+
+func (s *tagService) UpdateTag(ctx context.Context, id uint, form *domain.TagForm) (*domain.Tag, error) {
+	if _, err := s.tagRepo.GetTagByID(id); err != nil {
+		return nil, err
+	}
+	name, err := s.checkName(form.Name, id)
+	if err != nil {
+		return nil, err
+	}
+
+	tag := &domain.Tag{ID: id, Name: name}
+	if err := s.tagRepo.UpdateTag(ctx, tag); err != nil {
+		return nil, err
+	}
+	return tag, nil
+}
+
+func (s *tagService) DeleteTag(ctx context.Context, id uint) error {
+	return s.tagRepo.DeleteTag(ctx, id)
+}
+
+// checkName menormalkan nama tag ke huruf kecil lalu memastikan formatnya valid dan unik
+func (s *tagService) checkName(name string, excludeID uint) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !domain.ValidTagName(name) {
+		return "", &ValidationError{Errors: map[string]string{"name": "name must contain only lowercase letters, digits and hyphens"}}
+	}
+	isUnique, err := s.tagRepo.IsTagNameUnique(name, excludeID)
+	if err != nil {
+		return "", err
+	}
+	if !isUnique {
+		return "", &ValidationError{Errors: map[string]string{"name": "name must be unique"}}
+	}
+	return name, nil
+}
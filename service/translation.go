@@ -0,0 +1,18 @@
+package service
+
+import "crud-clean-architecture/domain"
+
+// checkTranslation memastikan locale bisa diterjemahkan; nama di DefaultLocale
+// diubah lewat field name biasa
+func checkTranslation(locale string, form *domain.TranslationForm) (*domain.Translation, error) {
+	if !domain.IsSupportedLocale(locale) {
+		return nil, &ValidationError{Errors: map[string]string{"locale": "locale is not supported"}}
+	}
+	if locale == domain.DefaultLocale {
+		return nil, &ValidationError{Errors: map[string]string{"locale": "the default locale is set through name"}}
+	}
+	if form == nil {
+		return nil, nil
+	}
+	return &domain.Translation{Name: form.Name, Description: form.Description}, nil
+}
@@ -0,0 +1,65 @@
+package service
+
+import (
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"errors"
+	"time"
+)
+
+var (
+	ErrDeliveryNotDead = errors.New("only dead deliveries can be retried")
+)
+
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error
+	GetAllSubscriptions() ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id uint) error
+	GetDeadLetters() ([]domain.WebhookDelivery, error)
+	RetryDelivery(id uint) (*domain.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository) WebhookService {
+	return &webhookService{webhookRepo}
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, subscription *domain.WebhookSubscription) error {
+	subscription.Active = true
+	return s.webhookRepo.CreateSubscription(ctx, subscription)
+}
+
+func (s *webhookService) GetAllSubscriptions() ([]domain.WebhookSubscription, error) {
+	return s.webhookRepo.GetAllSubscriptions()
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id uint) error {
+	return s.webhookRepo.DeleteSubscription(ctx, id)
+}
+
+func (s *webhookService) GetDeadLetters() ([]domain.WebhookDelivery, error) {
+	return s.webhookRepo.GetDeliveriesByStatus(domain.WebhookDeliveryDead)
+}
+
+func (s *webhookService) RetryDelivery(id uint) (*domain.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.GetDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.Status != domain.WebhookDeliveryDead {
+		return nil, ErrDeliveryNotDead
+	}
+
+	// Kembalikan ke antrean; dispatcher akan mengirim ulang pada polling berikutnya
+	delivery.Status = domain.WebhookDeliveryPending
+	delivery.Attempts = 0
+	delivery.NextAttemptAt = time.Now()
+	if err := s.webhookRepo.UpdateDelivery(delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
@@ -0,0 +1,30 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+var ErrInvalidKey = errors.New("invalid storage key")
+
+// Storage stores uploaded files under a key such as "products/1/abc.jpg" and
+// returns the public URL to reach them.
+type Storage interface {
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Delete(ctx context.Context, key string) error
+	URL(key string) string
+}
+
+// validKey menolak key kosong, absolut atau yang keluar dari folder dengan ".."
+func validKey(key string) bool {
+	if key == "" || strings.HasPrefix(key, "/") {
+		return false
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return false
+		}
+	}
+	return true
+}
@@ -2,18 +2,28 @@ package main
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
 	"testing"
 
 	"crud-clean-architecture/config"
 	"crud-clean-architecture/domain"
 	"crud-clean-architecture/handler"
+	"crud-clean-architecture/middleware"
+	"crud-clean-architecture/outbox"
+	"crud-clean-architecture/payment"
+	"crud-clean-architecture/queue"
 	"crud-clean-architecture/repository"
 	"crud-clean-architecture/routes"
 	"crud-clean-architecture/service"
+	"crud-clean-architecture/storage"
+	"crud-clean-architecture/webhook"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -26,7 +36,14 @@ func setupTestRouter() *gin.Engine {
 	}
 	// Setup database
 	db := config.InitDB()
-	_ = db.AutoMigrate(&domain.Category{}, &domain.Product{}, &domain.Order{}, &domain.OrderDetail{})
+	_ = db.AutoMigrate(
+		&domain.Category{}, &domain.Tag{}, &domain.Product{}, &domain.Slug{}, &domain.ProductPrice{}, &domain.ProductVariant{},
+		&domain.ModifierGroup{}, &domain.ModifierOption{}, &domain.BundleComponent{}, &domain.ProductImage{},
+		&domain.Order{}, &domain.OrderDetail{}, &domain.OrderDetailModifier{}, &domain.OrderDetailComponent{},
+		&domain.Payment{}, &domain.PaymentEvent{},
+		&domain.WebhookSubscription{}, &domain.WebhookDelivery{}, &domain.OutboxMessage{},
+		&domain.AuditLog{},
+	)
 
 	// Setup Redis
 	config.InitRedis()
@@ -35,23 +52,68 @@ func setupTestRouter() *gin.Engine {
 	// Initialize repositories
 	categoryRepo := repository.NewCategoryRepository(db, redisClient)
 	productRepo := repository.NewProductRepository(db, redisClient)
+	tagRepo := repository.NewTagRepository(db, redisClient)
 	orderRepo := repository.NewOrderRepository(db, redisClient)
+	paymentRepo := repository.NewPaymentRepository(db, redisClient)
+	webhookRepo := repository.NewWebhookRepository(db)
+	outboxRepo := repository.NewOutboxRepository(db)
+	auditRepo := repository.NewAuditRepository(db)
+	exportRepo := repository.NewExportRepository(redisClient)
+
+	// Start Webhook Dispatcher
+	webhookDispatcher := webhook.NewDispatcher(webhookRepo)
+	go webhookDispatcher.Start(context.Background())
+
+	// Start Outbox Relay
+	outboxRelay := outbox.NewRelay(outboxRepo,
+		outbox.NewCacheInvalidationConsumer(redisClient),
+		outbox.NewRedisStreamConsumer(redisClient, "events"),
+		webhookDispatcher,
+	)
+	go outboxRelay.Start(context.Background())
+
+	// Initialize payment providers; QRIS memakai fake provider yang langsung sukses
+	paymentProviders := payment.NewRegistry()
+	paymentProviders.Register(domain.PaymentMethodCash, payment.NewCashProvider())
+	paymentProviders.Register(domain.PaymentMethodQRIS, payment.NewFakeProvider("fake", domain.PaymentStatusPaid))
 
 	// Initialize services
+	auditService := service.NewAuditService(auditRepo)
 	categoryService := service.NewCategoryService(categoryRepo)
-	productService := service.NewProductService(productRepo)
-	orderService := service.NewOrderService(orderRepo, productRepo)
+	mediaStorage := storage.NewLocalStorage(filepath.Join(os.TempDir(), "media"), "/media")
+	productService := service.NewProductService(productRepo, categoryRepo, tagRepo, mediaStorage)
+	tagService := service.NewTagService(tagRepo)
+	orderService := service.NewOrderService(orderRepo, productRepo, paymentRepo, paymentProviders)
+	webhookService := service.NewWebhookService(webhookRepo)
+	paymentService := service.NewPaymentService(paymentRepo, paymentProviders)
+	exportService := service.NewExportService(orderRepo, productRepo, exportRepo, queue.New(redisClient),
+		filepath.Join(os.TempDir(), "exports"))
+	productImportService := service.NewProductImportService(productRepo, categoryRepo)
+	productImageService := service.NewProductImageService(productRepo, mediaStorage)
 
 	// Initialize handlers
 	categoryHandler := handler.NewCategoryHandler(categoryService)
 	productHandler := handler.NewProductHandler(productService)
+	tagHandler := handler.NewTagHandler(tagService)
 	orderHandler := handler.NewOrderHandler(orderService)
+	paymentHandler := handler.NewPaymentHandler(paymentService)
+	webhookHandler := handler.NewWebhookHandler(webhookService)
+	exportHandler := handler.NewExportHandler(exportService)
+	productImportHandler := handler.NewProductImportHandler(productImportService)
+	productImageHandler := handler.NewProductImageHandler(productImageService, productService)
+	auditHandler := handler.NewAuditHandler(auditService)
 
 	// Setup router
 	r := gin.Default()
+	r.Use(middleware.RequestContext())
 	routes.RegisterCategoryRoutes(r.Group("/categories"), categoryHandler)
-	routes.RegisterProductRoutes(r.Group("/products"), productHandler)
-	routes.RegisterOrderRoutes(r.Group("/orders"), orderHandler)
+	routes.RegisterProductRoutes(r.Group("/products"), productHandler, exportHandler, productImportHandler, productImageHandler)
+	routes.RegisterTagRoutes(r.Group("/tags"), tagHandler)
+	routes.RegisterOrderRoutes(r.Group("/orders"), orderHandler, exportHandler)
+	routes.RegisterPaymentRoutes(r.Group("/payments"), paymentHandler)
+	routes.RegisterWebhookRoutes(r.Group("/webhooks"), webhookHandler)
+	routes.RegisterExportRoutes(r.Group("/exports"), exportHandler)
+	routes.RegisterAuditRoutes(r.Group("/audit"), auditHandler)
 
 	return r
 }
@@ -103,3 +165,62 @@ func TestE2EOrderAPI(t *testing.T) {
 	data := response["data"].(map[string]interface{})
 	assert.Equal(t, float64(3000), data["total_price"])
 }
+
+func TestE2EOrderPaymentAPI(t *testing.T) {
+	router := setupTestRouter()
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	// Step 1: Create a new order (total 3000)
+	orderPayload := map[string]interface{}{
+		"details": []map[string]interface{}{
+			{"product_id": 1, "quantity": 2},
+		},
+	}
+	orderBody, _ := json.Marshal(orderPayload)
+	resp, err := http.Post(server.URL+"/orders", "application/json", bytes.NewBuffer(orderBody))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var created map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&created)
+	assert.NoError(t, err)
+	orderID := int(created["data"].(map[string]interface{})["id"].(float64))
+	paymentsURL := server.URL + "/orders/" + strconv.Itoa(orderID) + "/payments"
+
+	// Step 2: Pay part of it in cash and check the change
+	cashBody, _ := json.Marshal(map[string]interface{}{"method": "cash", "amount": 1000, "tendered": 2000})
+	resp, err = http.Post(paymentsURL, "application/json", bytes.NewBuffer(cashBody))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	var cashResponse map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&cashResponse)
+	assert.NoError(t, err)
+	assert.Equal(t, float64(1000), cashResponse["data"].(map[string]interface{})["change"])
+
+	// Step 3: Paying more than the outstanding balance is rejected
+	tooMuchBody, _ := json.Marshal(map[string]interface{}{"method": "qris", "amount": 5000})
+	resp, err = http.Post(paymentsURL, "application/json", bytes.NewBuffer(tooMuchBody))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Step 4: Settle the rest through the fake QRIS provider
+	qrisBody, _ := json.Marshal(map[string]interface{}{"method": "qris"})
+	resp, err = http.Post(paymentsURL, "application/json", bytes.NewBuffer(qrisBody))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	// Step 5: The order is now paid
+	resp, err = http.Get(server.URL + "/orders/" + strconv.Itoa(orderID))
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	assert.NoError(t, err)
+
+	data := response["data"].(map[string]interface{})
+	assert.Equal(t, "paid", data["status"])
+	assert.Equal(t, float64(3000), data["paid_amount"])
+}
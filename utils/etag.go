@@ -0,0 +1,102 @@
+package utils
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+var ErrInvalidETag = errors.New("invalid ETag")
+
+// ETag formats a resource version as an entity tag. The versions of embedded
+// resources that have their own version (such as the category of a product)
+// are appended after a dash, so the tag changes when any of them does.
+func ETag(version uint, embedded ...uint) string {
+	parts := make([]string, 0, len(embedded)+1)
+	parts = append(parts, strconv.FormatUint(uint64(version), 10))
+	for _, v := range embedded {
+		parts = append(parts, strconv.FormatUint(uint64(v), 10))
+	}
+	return `"` + strings.Join(parts, "-") + `"`
+}
+
+// WithLocale appends the languages of a localized representation to an entity
+// tag, so every language has its own strong ETag: "5-2" becomes "5-2-en".
+func WithLocale(etag string, locales ...string) string {
+	if len(locales) == 0 || len(etag) < 2 {
+		return etag
+	}
+	return etag[:len(etag)-1] + "-" + strings.Join(locales, "-") + `"`
+}
+
+// ParseETag reads the resource version from a single entity tag; the versions
+// of embedded resources and trailing locales are validated and ignored. The
+// wildcard "*" yields 0, meaning any version.
+func ParseETag(value string) (uint, error) {
+	opaque, err := opaqueTag(value)
+	if err != nil || opaque == "*" {
+		return 0, err
+	}
+	var version uint64
+	locales := false
+	for i, part := range strings.Split(opaque, "-") {
+		// Locale hanya boleh di akhir, setelah semua versi
+		if i > 0 && isLocalePart(part) {
+			locales = true
+			continue
+		}
+		parsed, err := strconv.ParseUint(part, 10, 64)
+		if err != nil || parsed == 0 || locales {
+			return 0, ErrInvalidETag
+		}
+		if i == 0 {
+			version = parsed
+		}
+	}
+	return uint(version), nil
+}
+
+// isLocalePart cek bagian ETag berupa kode bahasa huruf kecil, mis. "en"
+func isLocalePart(part string) bool {
+	if part == "" {
+		return false
+	}
+	for _, r := range part {
+		if r < 'a' || r > 'z' {
+			return false
+		}
+	}
+	return true
+}
+
+// ETagMatches reports whether a comma separated If-None-Match header lists
+// etag (weak comparison) or is the wildcard.
+func ETagMatches(header string, etag string) bool {
+	want, err := opaqueTag(etag)
+	if err != nil {
+		return false
+	}
+	for _, tag := range strings.Split(header, ",") {
+		if strings.TrimSpace(tag) == "" {
+			continue
+		}
+		opaque, err := opaqueTag(tag)
+		if err == nil && (opaque == "*" || opaque == want) {
+			return true
+		}
+	}
+	return false
+}
+
+// opaqueTag mengembalikan isi entity tag tanpa W/ dan tanda kutip, atau "*"
+func opaqueTag(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "*" {
+		return value, nil
+	}
+	value = strings.TrimPrefix(value, "W/")
+	if len(value) < 3 || !strings.HasPrefix(value, `"`) || !strings.HasSuffix(value, `"`) {
+		return "", ErrInvalidETag
+	}
+	return value[1 : len(value)-1], nil
+}
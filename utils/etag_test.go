@@ -0,0 +1,96 @@
+package utils
+
+import "testing"
+
+func TestETag(t *testing.T) {
+	if got := ETag(3); got != `"3"` {
+		t.Fatalf(`ETag(3) = %s, want "3"`, got)
+	}
+	if got := ETag(3, 7); got != `"3-7"` {
+		t.Fatalf(`ETag(3, 7) = %s, want "3-7"`, got)
+	}
+}
+
+func TestWithLocale(t *testing.T) {
+	if got := WithLocale(ETag(3, 7), "en"); got != `"3-7-en"` {
+		t.Fatalf(`WithLocale = %s, want "3-7-en"`, got)
+	}
+	if got := WithLocale(ETag(3), "en", "id"); got != `"3-en-id"` {
+		t.Fatalf(`WithLocale = %s, want "3-en-id"`, got)
+	}
+	if got := WithLocale(ETag(3)); got != `"3"` {
+		t.Fatalf(`WithLocale without locale = %s, want "3"`, got)
+	}
+}
+
+func TestParseETag(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    uint
+		wantErr bool
+	}{
+		{`"5"`, 5, false},
+		{` "5" `, 5, false},
+		{`W/"5"`, 5, false},
+		{`"5-2"`, 5, false},
+		{`W/"12-1"`, 12, false},
+		{`"5-2-en"`, 5, false},
+		{`"5-id"`, 5, false},
+		{`"5-2-en-id"`, 5, false},
+		{`"en"`, 0, true},
+		{`"5-en-2"`, 0, true},
+		{`"5-EN"`, 0, true},
+		{`*`, 0, false},
+		{`5`, 0, true},
+		{`""`, 0, true},
+		{`"0"`, 0, true},
+		{`"-1"`, 0, true},
+		{`"5-"`, 0, true},
+		{`"5-0"`, 0, true},
+		{`"abc"`, 0, true},
+		{`"5`, 0, true},
+		{`W/5`, 0, true},
+		{``, 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseETag(tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseETag(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseETag(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestETagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		etag   string
+		want   bool
+	}{
+		{`"5"`, `"5"`, true},
+		{`W/"5"`, `"5"`, true},
+		{`"4", "5"`, `"5"`, true},
+		{`"4",,"5"`, `"5"`, true},
+		{`*`, `"5"`, true},
+		{`"4"`, `"5"`, false},
+		{``, `"5"`, false},
+		{`5`, `"5"`, false},
+		// Versi kategori berbeda berarti representasinya berbeda
+		{`"5-2"`, `"5-2"`, true},
+		{`"5-1"`, `"5-2"`, false},
+		{`"5"`, `"5-2"`, false},
+		{`"5-2"`, `"5"`, false},
+		{`"5"`, `invalid`, false},
+		// Setiap bahasa punya ETag sendiri
+		{`"5-2-id"`, `"5-2-id"`, true},
+		{`"5-2-id"`, `"5-2-en"`, false},
+	}
+	for _, tt := range tests {
+		if got := ETagMatches(tt.header, tt.etag); got != tt.want {
+			t.Errorf("ETagMatches(%q, %q) = %v, want %v", tt.header, tt.etag, got, tt.want)
+		}
+	}
+}
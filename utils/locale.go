@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// NegotiateLocale returns the supported locale preferred by an
+// Accept-Language style header ("en-US,en;q=0.9,id;q=0.8"), matching on the
+// primary language. It returns "" when none of them is supported.
+func NegotiateLocale(header string, supported []string) string {
+	best, bestQ := "", 0.0
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if value, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			parsed, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			q = parsed
+		}
+		// Ambil bahasa utama saja: en-US -> en
+		language, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(tag)), "-")
+		language, _, _ = strings.Cut(language, "_")
+		if q <= bestQ {
+			continue
+		}
+		for _, locale := range supported {
+			if locale == language {
+				best, bestQ = locale, q
+				break
+			}
+		}
+	}
+	return best
+}
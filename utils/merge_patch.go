@@ -0,0 +1,39 @@
+package utils
+
+import "encoding/json"
+
+// MergePatch applies an RFC 7396 JSON merge patch to the target document:
+// objects are merged recursively, null removes a member and any other value
+// replaces the target.
+func MergePatch(target, patch []byte) ([]byte, error) {
+	var patchValue interface{}
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, err
+	}
+	var targetValue interface{}
+	if len(target) > 0 {
+		if err := json.Unmarshal(target, &targetValue); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(mergeValue(targetValue, patchValue))
+}
+
+func mergeValue(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergeValue(targetObject[key], value)
+	}
+	return targetObject
+}
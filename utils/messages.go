@@ -0,0 +1,359 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DefaultMessageLocale is the language of response messages when
+// Accept-Language names none of MessageLocales. Messages stay in English for
+// existing clients; Indonesian is opted into with Accept-Language: id.
+const DefaultMessageLocale = sourceMessageLocale
+
+// sourceMessageLocale adalah bahasa pesan di kode, yang juga menjadi ID pesan
+const sourceMessageLocale = "en"
+
+// MessageLocales lists the languages response messages are available in.
+var MessageLocales = []string{DefaultMessageLocale, "id"}
+
+// messages maps the English messages, which double as message IDs, to their
+// translations. Messages may contain fmt verbs; a formatted message is
+// translated by matching it against its template. Translations may reorder
+// the arguments with explicit indexes such as %[2]s. Errors wrapped as
+// "%w: detail" are translated part by part.
+var messages = map[string]map[string]string{
+	"id": {
+		// Validasi input
+		"Validation error":                                             "Kesalahan validasi",
+		"%s is required":                                               "%s wajib diisi",
+		"%s exceeds maximum length of %s":                              "%s melebihi panjang maksimum %s",
+		"%s is below minimum length of %s":                             "%s kurang dari panjang minimum %s",
+		"%s must be exactly %s characters long":                        "%s harus tepat %s karakter",
+		"%s must not have more than %s items":                          "%s tidak boleh lebih dari %s item",
+		"%s must have at least %s items":                               "%s minimal berisi %s item",
+		"%s must have exactly %s items":                                "%s harus berisi tepat %s item",
+		"%s must be equal to %s":                                       "%s harus sama dengan %s",
+		"%s must not be equal to %s":                                   "%s tidak boleh sama dengan %s",
+		"%s must be greater than %s":                                   "%s harus lebih besar dari %s",
+		"%s must be greater than or equal to %s":                       "%s harus lebih besar dari atau sama dengan %s",
+		"%s must be less than %s":                                      "%s harus lebih kecil dari %s",
+		"%s must be less than or equal to %s":                          "%s harus lebih kecil dari atau sama dengan %s",
+		"%s must be a numeric value":                                   "%s harus berupa angka",
+		"%s must be one of %s":                                         "%s harus salah satu dari %s",
+		"%s must be a date in the format %s":                           "%s harus berupa tanggal dengan format %s",
+		"%s must be a valid time zone":                                 "%s harus berupa zona waktu yang valid",
+		"%s must be a valid URL":                                       "%s harus berupa URL yang valid",
+		"%s must be a valid email address":                             "%s harus berupa alamat email yang valid",
+		"%s must be a valid UUID":                                      "%s harus berupa UUID yang valid",
+		"%s must be a boolean":                                         "%s harus berupa boolean",
+		"%s must contain only letters":                                 "%s hanya boleh berisi huruf",
+		"%s must contain only letters and digits":                      "%s hanya boleh berisi huruf dan angka",
+		"%s must be lowercase":                                         "%s harus huruf kecil",
+		"%s must be uppercase":                                         "%s harus huruf besar",
+		"%s must contain unique values":                                "%s harus berisi nilai yang unik",
+		"%s does not satisfy the %s rule":                              "%s tidak memenuhi aturan %s",
+		"%s must be a number":                                          "%s harus berupa angka",
+		"%s must be a string":                                          "%s harus berupa teks",
+		"%s is not an attribute of the category":                       "%s bukan atribut kategori ini",
+		"enum attribute %s must have options":                          "atribut enum %s harus memiliki opsi",
+		"only enum attributes can have options, %s is %s":              "hanya atribut enum yang boleh memiliki opsi, %s bertipe %s",
+		"options of %s must be unique":                                 "opsi %s harus unik",
+		"attribute names must be unique":                               "nama atribut harus unik",
+		"option names must be unique":                                  "nama opsi harus unik",
+		"name must be unique":                                          "nama harus unik",
+		"sku must be unique":                                           "sku harus unik",
+		"name must contain only lowercase letters, digits and hyphens": "nama hanya boleh berisi huruf kecil, angka dan tanda hubung",
+		"name is duplicated in operation %s":                           "nama sudah dipakai di operasi %s",
+		"name is duplicated in the file (line %s)":                     "nama sudah dipakai di file (baris %s)",
+		"min_select cannot exceed the number of options":               "min_select tidak boleh melebihi jumlah opsi",
+		"bundle products cannot have variants":                         "produk bundle tidak boleh memiliki varian",
+		"products with variants cannot be bundles":                     "produk dengan varian tidak boleh menjadi bundle",
+		"image_ids must list every image of the product exactly once":  "image_ids harus memuat setiap gambar produk tepat satu kali",
+		"a product can have at most %s images":                         "produk maksimal memiliki %s gambar",
+		"tag %s not found":                                             "tag %s tidak ditemukan",
+		"locale is not supported":                                      "locale tidak didukung",
+		"start_time and end_time must be set together":                 "start_time dan end_time harus diisi bersamaan",
+		"end_time must differ from start_time":                         "end_time harus berbeda dari start_time",
+		"end_date must not be before start_date":                       "end_date tidak boleh sebelum start_date",
+		"timezone must be a valid time zone":                           "timezone harus berupa zona waktu yang valid",
+		"available_at must be an RFC 3339 time or now":                 "available_at harus berupa waktu RFC 3339 atau now",
+		"%s is not available at this time":                             "%s sedang tidak tersedia",
+		"the default locale is set through name":                       "locale default diubah lewat field name",
+
+		// Pesan request
+		"Invalid ID format":                                 "Format ID tidak valid",
+		"Invalid image ID format":                           "Format ID gambar tidak valid",
+		"Invalid modifier group ID format":                  "Format ID grup modifier tidak valid",
+		"Invalid payment ID format":                         "Format ID pembayaran tidak valid",
+		"Invalid price ID format":                           "Format ID harga tidak valid",
+		"Invalid tag ID format":                             "Format ID tag tidak valid",
+		"Invalid variant ID format":                         "Format ID varian tidak valid",
+		"Invalid input":                                     "Input tidak valid",
+		"Invalid limit":                                     "Limit tidak valid",
+		"Invalid request payload":                           "Payload request tidak valid",
+		"Failed to read request body":                       "Gagal membaca body request",
+		"Content-Type must be application/merge-patch+json": "Content-Type harus application/merge-patch+json",
+		"If-Match header is required":                       "Header If-Match wajib diisi",
+		"If-Match must contain a single ETag":               "If-Match harus berisi satu ETag",
+		"File is too large":                                 "Ukuran file terlalu besar",
+		"file is required":                                  "file wajib diisi",
+
+		// Pesan gagal
+		"Failed to check category uniqueness":           "Gagal memeriksa keunikan kategori",
+		"Failed to check product uniqueness":            "Gagal memeriksa keunikan produk",
+		"Failed to fetch orders":                        "Gagal mengambil order",
+		"Failed to fetch product facets":                "Gagal mengambil facet produk",
+		"Failed to fetch products":                      "Gagal mengambil produk",
+		"Failed to fetch tags":                          "Gagal mengambil tag",
+		"Category not found":                            "Kategori tidak ditemukan",
+		"Product not found":                             "Produk tidak ditemukan",
+		"Import has invalid rows, nothing was imported": "Import memiliki baris tidak valid, tidak ada yang diimport",
+		"Dry run completed, nothing was imported":       "Dry run selesai, tidak ada yang diimport",
+		"Some items are not available":                  "Beberapa item tidak tersedia",
+		"Event already processed":                       "Event sudah diproses",
+
+		// Pesan berhasil
+		"Audit logs retrieved successfully":            "Log audit berhasil diambil",
+		"Average order value retrieved successfully":   "Rata-rata nilai order berhasil diambil",
+		"Bundle components removed successfully":       "Komponen bundle berhasil dihapus",
+		"Bundle components updated successfully":       "Komponen bundle berhasil diperbarui",
+		"Categories batch applied successfully":        "Batch kategori berhasil diterapkan",
+		"Categories retrieved successfully":            "Kategori berhasil diambil",
+		"Category attributes updated successfully":     "Atribut kategori berhasil diperbarui",
+		"Category created successfully":                "Kategori berhasil dibuat",
+		"Category deleted successfully":                "Kategori berhasil dihapus",
+		"Category retrieved successfully":              "Kategori berhasil diambil",
+		"Category translation deleted successfully":    "Terjemahan kategori berhasil dihapus",
+		"Category translation updated successfully":    "Terjemahan kategori berhasil diperbarui",
+		"Category updated successfully":                "Kategori berhasil diperbarui",
+		"Dead jobs retrieved successfully":             "Job gagal berhasil diambil",
+		"Dead letters retrieved successfully":          "Dead letter berhasil diambil",
+		"Delivery re-queued successfully":              "Pengiriman berhasil diantrekan ulang",
+		"Export queued":                                "Export masuk antrean",
+		"Export retrieved successfully":                "Export berhasil diambil",
+		"Image deleted successfully":                   "Gambar berhasil dihapus",
+		"Image uploaded successfully":                  "Gambar berhasil diupload",
+		"Images reordered successfully":                "Urutan gambar berhasil diubah",
+		"Images retrieved successfully":                "Gambar berhasil diambil",
+		"Job deleted successfully":                     "Job berhasil dihapus",
+		"Job re-queued successfully":                   "Job berhasil diantrekan ulang",
+		"Job runs retrieved successfully":              "Riwayat job berhasil diambil",
+		"Job triggered successfully":                   "Job berhasil dijalankan",
+		"Modifier group created successfully":          "Grup modifier berhasil dibuat",
+		"Modifier group deleted successfully":          "Grup modifier berhasil dihapus",
+		"Modifier group updated successfully":          "Grup modifier berhasil diperbarui",
+		"Modifier groups retrieved successfully":       "Grup modifier berhasil diambil",
+		"Order cancelled successfully":                 "Order berhasil dibatalkan",
+		"Order created successfully":                   "Order berhasil dibuat",
+		"Order deleted successfully":                   "Order berhasil dihapus",
+		"Order fetched successfully":                   "Order berhasil diambil",
+		"Orders fetched successfully":                  "Order berhasil diambil",
+		"Payment recorded successfully":                "Pembayaran berhasil dicatat",
+		"Payment updated successfully":                 "Pembayaran berhasil diperbarui",
+		"Payments fetched successfully":                "Pembayaran berhasil diambil",
+		"Price history retrieved successfully":         "Riwayat harga berhasil diambil",
+		"Price scheduled successfully":                 "Harga berhasil dijadwalkan",
+		"Product created successfully":                 "Produk berhasil dibuat",
+		"Product deleted successfully":                 "Produk berhasil dihapus",
+		"Product retrieved successfully":               "Produk berhasil diambil",
+		"Product availability removed successfully":    "Jadwal ketersediaan produk berhasil dihapus",
+		"Product availability updated successfully":    "Jadwal ketersediaan produk berhasil diperbarui",
+		"Product tag removed successfully":             "Tag produk berhasil dilepas",
+		"Product tags updated successfully":            "Tag produk berhasil diperbarui",
+		"Product translation deleted successfully":     "Terjemahan produk berhasil dihapus",
+		"Product translation updated successfully":     "Terjemahan produk berhasil diperbarui",
+		"Product updated successfully":                 "Produk berhasil diperbarui",
+		"Products batch applied successfully":          "Batch produk berhasil diterapkan",
+		"Products imported successfully":               "Produk berhasil diimport",
+		"Products retrieved successfully":              "Produk berhasil diambil",
+		"Queue stats retrieved successfully":           "Statistik antrean berhasil diambil",
+		"Revenue by category retrieved successfully":   "Pendapatan per kategori berhasil diambil",
+		"Revenue report retrieved successfully":        "Laporan pendapatan berhasil diambil",
+		"Scheduled jobs retrieved successfully":        "Job terjadwal berhasil diambil",
+		"Scheduled price cancelled successfully":       "Harga terjadwal berhasil dibatalkan",
+		"Tag created successfully":                     "Tag berhasil dibuat",
+		"Tag deleted successfully":                     "Tag berhasil dihapus",
+		"Tag retrieved successfully":                   "Tag berhasil diambil",
+		"Tag updated successfully":                     "Tag berhasil diperbarui",
+		"Tags retrieved successfully":                  "Tag berhasil diambil",
+		"Top products retrieved successfully":          "Produk terlaris berhasil diambil",
+		"Variant created successfully":                 "Varian berhasil dibuat",
+		"Variant deleted successfully":                 "Varian berhasil dihapus",
+		"Variant updated successfully":                 "Varian berhasil diperbarui",
+		"Variants retrieved successfully":              "Varian berhasil diambil",
+		"Webhook processed successfully":               "Webhook berhasil diproses",
+		"Webhook subscription created successfully":    "Langganan webhook berhasil dibuat",
+		"Webhook subscription deleted successfully":    "Langganan webhook berhasil dihapus",
+		"Webhook subscriptions retrieved successfully": "Langganan webhook berhasil diambil",
+
+		// Error dari service dan repository
+		"CSV must have a header row with name, price and category columns": "CSV harus memiliki baris header dengan kolom name, price dan category",
+		"CSV must not have more than 5000 rows":                            "CSV tidak boleh lebih dari 5000 baris",
+		"batch was rejected, no operation was applied":                     "batch ditolak, tidak ada operasi yang diterapkan",
+		"bundle component must be a simple product with its variant named": "komponen bundle harus produk biasa dengan variannya disebutkan",
+		"category name already exists":                                     "nama kategori sudah ada",
+		"category not found":                                               "kategori tidak ditemukan",
+		"effective_from must be in the future":                             "effective_from harus di masa depan",
+		"export is not ready yet":                                          "export belum siap",
+		"export not found":                                                 "export tidak ditemukan",
+		"from must not be after to":                                        "from tidak boleh setelah to",
+		"image dimensions are too large":                                   "dimensi gambar terlalu besar",
+		"image not found":                                                  "gambar tidak ditemukan",
+		"insufficient variant stock":                                       "stok varian tidak cukup",
+		"invalid ETag":                                                     "ETag tidak valid",
+		"invalid cron expression":                                          "ekspresi cron tidak valid",
+		"invalid merge patch document":                                     "dokumen merge patch tidak valid",
+		"invalid modifiers":                                                "modifier tidak valid",
+		"invalid storage key":                                              "kunci storage tidak valid",
+		"invalid webhook payload":                                          "payload webhook tidak valid",
+		"invalid webhook signature":                                        "signature webhook tidak valid",
+		"job not found":                                                    "job tidak ditemukan",
+		"job with the same unique key was already enqueued":                "job dengan unique key yang sama sudah ada di antrean",
+		"modifier group not found":                                         "grup modifier tidak ditemukan",
+		"only dead deliveries can be retried":                              "hanya pengiriman yang gagal yang dapat diulang",
+		"order is already paid":                                            "order sudah dibayar",
+		"order is cancelled":                                               "order sudah dibatalkan",
+		"order has a pending payment":                                      "order masih memiliki pembayaran pending",
+		"order not found":                                                  "order tidak ditemukan",
+		"orders with received payments cannot be deleted":                  "order yang sudah menerima pembayaran tidak dapat dihapus",
+		"payment amount exceeds outstanding balance":                       "jumlah pembayaran melebihi sisa tagihan",
+		"payment event already processed":                                  "event pembayaran sudah diproses",
+		"payment not found":                                                "pembayaran tidak ditemukan",
+		"payment provider does not accept webhooks":                        "payment provider tidak menerima webhook",
+		"payment provider not found":                                       "payment provider tidak ditemukan",
+		"payment status transition is not allowed":                         "perubahan status pembayaran tidak diizinkan",
+		"price is already effective":                                       "harga sudah berlaku",
+		"product is a component of a bundle":                               "produk adalah komponen sebuah bundle",
+		"product not found":                                                "produk tidak ditemukan",
+		"report range must not exceed 366 days":                            "rentang laporan tidak boleh lebih dari 366 hari",
+		"resource was modified by another request":                         "data sudah diubah oleh request lain",
+		"scheduled job is already running":                                 "job terjadwal sedang berjalan",
+		"scheduled job not found":                                          "job terjadwal tidak ditemukan",
+		"scheduled price not found":                                        "harga terjadwal tidak ditemukan",
+		"tag not found":                                                    "tag tidak ditemukan",
+		"tendered cash is less than the payment amount":                    "uang tunai kurang dari jumlah pembayaran",
+		"translation not found":                                            "terjemahan tidak ditemukan",
+		"unknown gateway status":                                           "status gateway tidak dikenal",
+		"unsupported export format":                                        "format export tidak didukung",
+		"unsupported image format, use jpeg, png or gif":                   "format gambar tidak didukung, gunakan jpeg, png atau gif",
+		"variant does not belong to the product":                           "varian bukan milik produk ini",
+		"variant not found":                                                "varian tidak ditemukan",
+		"variant_id is required for products with variants":                "variant_id wajib diisi untuk produk dengan varian",
+		"webhook delivery not found":                                       "pengiriman webhook tidak ditemukan",
+		"webhook subscription not found":                                   "langganan webhook tidak ditemukan",
+
+		// Detail dari error yang dibungkus ("%w: detail")
+		"%s requires between %s and %s selections, got %s": "%s membutuhkan %s sampai %s pilihan, dipilih %s",
+		"event_id and reference are required":              "event_id dan reference wajib diisi",
+		"option %s is not available for product %s":        "opsi %s tidak tersedia untuk produk %s",
+		"option %s is selected more than once":             "opsi %s dipilih lebih dari sekali",
+	},
+}
+
+type messageTemplate struct {
+	pattern     *regexp.Regexp
+	translation string
+}
+
+// messageTemplates berisi pesan yang memakai verb fmt, yang paling spesifik lebih dulu
+var messageTemplates = compileMessageTemplates()
+
+var fmtVerb = regexp.MustCompile(`%(\[\d+\])?[sdqv]`)
+
+func compileMessageTemplates() map[string][]messageTemplate {
+	templates := make(map[string][]messageTemplate, len(messages))
+	for locale, catalog := range messages {
+		var keys []string
+		for key := range catalog {
+			if fmtVerb.MatchString(key) {
+				keys = append(keys, key)
+			}
+		}
+		// Template dengan teks tetap lebih panjang dicoba lebih dulu
+		sort.Slice(keys, func(i, j int) bool {
+			li, lj := len(fmtVerb.ReplaceAllString(keys[i], "")), len(fmtVerb.ReplaceAllString(keys[j], ""))
+			if li != lj {
+				return li > lj
+			}
+			return keys[i] < keys[j]
+		})
+		for _, key := range keys {
+			literals := fmtVerb.Split(key, -1)
+			for i, literal := range literals {
+				literals[i] = regexp.QuoteMeta(literal)
+			}
+			templates[locale] = append(templates[locale], messageTemplate{
+				pattern:     regexp.MustCompile("^" + strings.Join(literals, "(.+?)") + "$"),
+				translation: catalog[key],
+			})
+		}
+	}
+	return templates
+}
+
+// TranslateMessage returns message in locale, or message itself when the
+// catalog has no translation for it.
+func TranslateMessage(locale, message string) string {
+	if translation, ok := messages[locale][message]; ok {
+		return translation
+	}
+	// Error yang dibungkus fmt.Errorf("%w: ...") diterjemahkan per bagian,
+	// sebelum template supaya "%s ..." tidak menelan awalan error-nya
+	if prefix, detail, found := strings.Cut(message, ": "); found {
+		if translation, ok := lookupMessage(locale, prefix); ok {
+			return translation + ": " + TranslateMessage(locale, detail)
+		}
+	}
+	if translation, ok := lookupMessage(locale, message); ok {
+		return translation
+	}
+	return message
+}
+
+// lookupMessage mencari message di katalog locale, persis atau lewat template
+func lookupMessage(locale, message string) (string, bool) {
+	catalog, ok := messages[locale]
+	if !ok {
+		return "", false
+	}
+	if translation, ok := catalog[message]; ok {
+		return translation, true
+	}
+	for _, template := range messageTemplates[locale] {
+		match := template.pattern.FindStringSubmatch(message)
+		if match == nil {
+			continue
+		}
+		args := make([]interface{}, len(match)-1)
+		for i, arg := range match[1:] {
+			args[i] = arg
+		}
+		return fmt.Sprintf(template.translation, args...), true
+	}
+	return "", false
+}
+
+// MessageLocale picks the language of response messages from Accept-Language.
+func MessageLocale(c *gin.Context) string {
+	if locale := NegotiateLocale(c.GetHeader("Accept-Language"), MessageLocales); locale != "" {
+		return locale
+	}
+	return DefaultMessageLocale
+}
+
+// translateErrors menerjemahkan pesan di map errors; tipe lain dikirim apa adanya
+func translateErrors(locale string, errors interface{}) interface{} {
+	fields, ok := errors.(map[string]string)
+	if !ok || locale == sourceMessageLocale {
+		return errors
+	}
+	translated := make(map[string]string, len(fields))
+	for field, message := range fields {
+		translated[field] = TranslateMessage(locale, message)
+	}
+	return translated
+}
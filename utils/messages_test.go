@@ -0,0 +1,121 @@
+package utils
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestMessageCatalogCoverage memastikan setiap pesan literal di JSONResponse dan
+// setiap error Err* yang diekspor punya terjemahan di katalog
+func TestMessageCatalogCoverage(t *testing.T) {
+	sources := map[string]string{}
+	fset := token.NewFileSet()
+	err := filepath.WalkDir("..", func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && strings.HasPrefix(d.Name(), ".") && path != ".." {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !strings.HasSuffix(path, ".go") || strings.HasSuffix(path, "_test.go") {
+			return nil
+		}
+		file, err := parser.ParseFile(fset, path, nil, 0)
+		if err != nil {
+			return err
+		}
+		collectMessages(fset, file, sources)
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sources) == 0 {
+		t.Fatal("no messages found")
+	}
+
+	for _, locale := range MessageLocales {
+		if locale == sourceMessageLocale {
+			continue
+		}
+		for message, source := range sources {
+			if _, ok := lookupMessage(locale, message); !ok {
+				t.Errorf("%s: %q has no %s translation", source, message, locale)
+			}
+		}
+	}
+}
+
+func collectMessages(fset *token.FileSet, file *ast.File, sources map[string]string) {
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.CallExpr:
+			if name := calleeName(node.Fun); (name == "JSONResponse" || name == "JSONResponseWithMeta") && len(node.Args) > 2 {
+				if message, ok := stringLiteral(node.Args[2]); ok {
+					sources[message] = fset.Position(node.Pos()).String()
+				}
+			}
+		case *ast.ValueSpec:
+			for i, name := range node.Names {
+				if !strings.HasPrefix(name.Name, "Err") || !name.IsExported() || i >= len(node.Values) {
+					continue
+				}
+				call, ok := node.Values[i].(*ast.CallExpr)
+				if !ok || calleeName(call.Fun) != "New" || len(call.Args) != 1 {
+					continue
+				}
+				if message, ok := stringLiteral(call.Args[0]); ok {
+					sources[message] = fset.Position(name.Pos()).String()
+				}
+			}
+		}
+		return true
+	})
+}
+
+func calleeName(fun ast.Expr) string {
+	switch fun := fun.(type) {
+	case *ast.Ident:
+		return fun.Name
+	case *ast.SelectorExpr:
+		return fun.Sel.Name
+	}
+	return ""
+}
+
+func stringLiteral(expr ast.Expr) (string, bool) {
+	literal, ok := expr.(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(literal.Value)
+	return value, err == nil
+}
+
+func TestTranslateMessage(t *testing.T) {
+	tests := []struct {
+		message string
+		want    string
+	}{
+		{"Validation error", "Kesalahan validasi"},
+		{"name is required", "name wajib diisi"},
+		{"Kopi is not available at this time", "Kopi sedang tidak tersedia"},
+		{"no translation yet", "no translation yet"},
+		{"invalid modifiers: option 3 is selected more than once", "modifier tidak valid: opsi 3 dipilih lebih dari sekali"},
+		{"invalid modifiers: something new", "modifier tidak valid: something new"},
+	}
+	for _, tt := range tests {
+		if got := TranslateMessage("id", tt.message); got != tt.want {
+			t.Errorf("TranslateMessage(id, %q) = %q, want %q", tt.message, got, tt.want)
+		}
+	}
+	if got := TranslateMessage(sourceMessageLocale, "Validation error"); got != "Validation error" {
+		t.Errorf("expected English messages unchanged, got %q", got)
+	}
+}
@@ -2,48 +2,135 @@ package utils
 
 import (
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
-// JSONResponse is a helper function to standardize API responses
+// JSONResponse is a helper function to standardize API responses. The message
+// and validation errors are translated to the language of Accept-Language.
 func JSONResponse(c *gin.Context, status int, message string, data interface{}, errors interface{}) {
+	locale := MessageLocale(c)
+	c.Header("Vary", "Accept-Language")
 	c.JSON(status, gin.H{
-		"message": message,
+		"message": TranslateMessage(locale, message),
 		"data":    data,
-		"errors":  errors,
+		"errors":  translateErrors(locale, errors),
 	})
 }
 
+// JSONResponseWithMeta is JSONResponse with a meta member, e.g. the facets of a list.
+func JSONResponseWithMeta(c *gin.Context, status int, message string, data interface{}, meta interface{}) {
+	c.Header("Vary", "Accept-Language")
+	c.JSON(status, gin.H{
+		"message": TranslateMessage(MessageLocale(c), message),
+		"data":    data,
+		"errors":  nil,
+		"meta":    meta,
+	})
+}
+
+// FormatValidationErrors describes each failed binding rule in English, keyed
+// by field. JSONResponse translates the messages.
 func FormatValidationErrors(err error) map[string]string {
 	errors := make(map[string]string)
 
 	if validationErrors, ok := err.(validator.ValidationErrors); ok {
 		for _, fieldError := range validationErrors {
-			field := fieldError.Field()
-			switch fieldError.Tag() {
-			case "required":
-				errors[field] = fmt.Sprintf("%s is required", field)
-			case "max":
-				errors[field] = fmt.Sprintf("%s exceeds maximum length of %s", field, fieldError.Param())
-			case "min":
-				errors[field] = fmt.Sprintf("%s is below minimum length of %s", field, fieldError.Param())
-			case "gt":
-				errors[field] = fmt.Sprintf("%s must be greater than %s", field, fieldError.Param())
-			case "gte":
-				errors[field] = fmt.Sprintf("%s must be greater than or equal to %s", field, fieldError.Param())
-			case "lt":
-				errors[field] = fmt.Sprintf("%s must be less than %s", field, fieldError.Param())
-			case "lte":
-				errors[field] = fmt.Sprintf("%s must be less than or equal to %s", field, fieldError.Param())
-			case "isnumeric":
-				errors[field] = fmt.Sprintf("%s must be a numeric value", field)
-			default:
-				errors[field] = fmt.Sprintf("Invalid value for %s", field)
-			}
+			errors[fieldError.Field()] = validationMessage(fieldError)
 		}
 	}
 
 	return errors
 }
+
+func validationMessage(fieldError validator.FieldError) string {
+	field, param := fieldError.Field(), fieldError.Param()
+	switch fieldError.Tag() {
+	case "required", "required_if", "required_unless", "required_with", "required_without":
+		return fmt.Sprintf("%s is required", field)
+	case "max":
+		switch kindOf(fieldError) {
+		case reflect.String:
+			return fmt.Sprintf("%s exceeds maximum length of %s", field, param)
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("%s must not have more than %s items", field, param)
+		default:
+			return fmt.Sprintf("%s must be less than or equal to %s", field, param)
+		}
+	case "min":
+		switch kindOf(fieldError) {
+		case reflect.String:
+			return fmt.Sprintf("%s is below minimum length of %s", field, param)
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("%s must have at least %s items", field, param)
+		default:
+			return fmt.Sprintf("%s must be greater than or equal to %s", field, param)
+		}
+	case "len":
+		switch kindOf(fieldError) {
+		case reflect.String:
+			return fmt.Sprintf("%s must be exactly %s characters long", field, param)
+		case reflect.Slice, reflect.Map, reflect.Array:
+			return fmt.Sprintf("%s must have exactly %s items", field, param)
+		default:
+			return fmt.Sprintf("%s must be equal to %s", field, param)
+		}
+	case "eq", "eqfield":
+		return fmt.Sprintf("%s must be equal to %s", field, param)
+	case "ne", "nefield":
+		return fmt.Sprintf("%s must not be equal to %s", field, param)
+	case "gt", "gtfield":
+		return fmt.Sprintf("%s must be greater than %s", field, param)
+	case "gte", "gtefield":
+		return fmt.Sprintf("%s must be greater than or equal to %s", field, param)
+	case "lt", "ltfield":
+		return fmt.Sprintf("%s must be less than %s", field, param)
+	case "lte", "ltefield":
+		return fmt.Sprintf("%s must be less than or equal to %s", field, param)
+	case "isnumeric", "numeric", "number":
+		return fmt.Sprintf("%s must be a numeric value", field)
+	case "oneof":
+		return fmt.Sprintf("%s must be one of %s", field, strings.Join(strings.Fields(param), ", "))
+	case "datetime":
+		return fmt.Sprintf("%s must be a date in the format %s", field, param)
+	case "timezone":
+		return fmt.Sprintf("%s must be a valid time zone", field)
+	case "url", "http_url", "uri":
+		return fmt.Sprintf("%s must be a valid URL", field)
+	case "email":
+		return fmt.Sprintf("%s must be a valid email address", field)
+	case "uuid", "uuid4":
+		return fmt.Sprintf("%s must be a valid UUID", field)
+	case "boolean":
+		return fmt.Sprintf("%s must be a boolean", field)
+	case "alpha":
+		return fmt.Sprintf("%s must contain only letters", field)
+	case "alphanum":
+		return fmt.Sprintf("%s must contain only letters and digits", field)
+	case "lowercase":
+		return fmt.Sprintf("%s must be lowercase", field)
+	case "uppercase":
+		return fmt.Sprintf("%s must be uppercase", field)
+	case "unique":
+		return fmt.Sprintf("%s must contain unique values", field)
+	default:
+		// Tag lain tetap menyebut aturannya supaya pesan tidak kabur
+		rule := fieldError.Tag()
+		if param != "" {
+			rule += "=" + param
+		}
+		return fmt.Sprintf("%s does not satisfy the %s rule", field, rule)
+	}
+}
+
+// kindOf mengembalikan jenis field, mengikuti pointer seperti validator
+func kindOf(fieldError validator.FieldError) reflect.Kind {
+	kind := fieldError.Kind()
+	if kind == reflect.Ptr && fieldError.Type() != nil {
+		kind = fieldError.Type().Elem().Kind()
+	}
+	return kind
+}
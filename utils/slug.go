@@ -0,0 +1,28 @@
+package utils
+
+import "strings"
+
+const maxSlugLength = 100
+
+// Slugify turns a name into a URL slug: lowercase ASCII letters and digits
+// separated by single hyphens, e.g. "Sate Ayam (10 tusuk)" -> "sate-ayam-10-tusuk".
+func Slugify(name string) string {
+	var b strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if hyphen && b.Len() > 0 {
+				b.WriteByte('-')
+			}
+			b.WriteRune(r)
+			hyphen = false
+			continue
+		}
+		hyphen = true
+	}
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		slug = strings.TrimRight(slug[:maxSlugLength], "-")
+	}
+	return slug
+}
@@ -0,0 +1,213 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crud-clean-architecture/domain"
+	"crud-clean-architecture/repository"
+	"crud-clean-architecture/utils"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"net/http"
+	"time"
+)
+
+const (
+	EventHeader     = "X-Webhook-Event"
+	EventIDHeader   = "X-Webhook-ID"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Signature"
+)
+
+// Envelope is the JSON body posted to subscribers.
+type Envelope struct {
+	ID        string           `json:"id"`
+	Type      domain.EventType `json:"type"`
+	CreatedAt time.Time        `json:"created_at"`
+	Data      interface{}      `json:"data"`
+}
+
+// Dispatcher delivers outbox events to webhook subscriptions in the background.
+// Deliveries are persisted first so retries survive a restart, and are moved
+// to the dead-letter list after MaxAttempts. Several workers may run at once;
+// each batch is claimed for ClaimTimeout, which must cover sending BatchSize
+// deliveries with the client timeout.
+type Dispatcher struct {
+	repo         repository.WebhookRepository
+	client       *http.Client
+	wake         chan struct{}
+	BatchSize    int
+	ClaimTimeout time.Duration
+	MaxAttempts  int
+	BaseBackoff  time.Duration
+	MaxBackoff   time.Duration
+	PollInterval time.Duration
+}
+
+func NewDispatcher(repo repository.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		repo:         repo,
+		client:       &http.Client{Timeout: 10 * time.Second},
+		wake:         make(chan struct{}, 1),
+		BatchSize:    20,
+		ClaimTimeout: 5 * time.Minute,
+		MaxAttempts:  8,
+		BaseBackoff:  10 * time.Second,
+		MaxBackoff:   time.Hour,
+		PollInterval: 5 * time.Second,
+	}
+}
+
+func (d *Dispatcher) Name() string {
+	return "webhook"
+}
+
+// Consume turns an outbox message into one delivery per matching subscription.
+// A message seen twice (the relay is at-least-once) is only enqueued once.
+func (d *Dispatcher) Consume(ctx context.Context, message *domain.OutboxMessage) error {
+	eventID := fmt.Sprintf("evt_%d", message.ID)
+	subscriptions, err := d.repo.GetSubscriptionsForEvent(message.EventType)
+	if err != nil || len(subscriptions) == 0 {
+		return err
+	}
+
+	envelope := Envelope{
+		ID:        eventID,
+		Type:      message.EventType,
+		CreatedAt: message.CreatedAt,
+		Data:      json.RawMessage(message.Payload),
+	}
+	payload, err := json.Marshal(envelope)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := make([]domain.WebhookDelivery, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		deliveries = append(deliveries, domain.WebhookDelivery{
+			SubscriptionID: subscription.ID,
+			EventID:        eventID,
+			EventType:      message.EventType,
+			Payload:        string(payload),
+			Status:         domain.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+	// Delivery yang sudah ada (pesan dikirim ulang) dilewati oleh unique index
+	created, err := d.repo.CreateDeliveries(deliveries)
+	if err != nil || created == 0 {
+		return err
+	}
+
+	// Bangunkan dispatcher agar tidak menunggu polling berikutnya
+	select {
+	case d.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Start runs the dispatcher until ctx is cancelled.
+func (d *Dispatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(d.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-d.wake:
+			d.deliverDue(ctx)
+		case <-ticker.C:
+			d.deliverDue(ctx)
+		}
+	}
+}
+
+func (d *Dispatcher) deliverDue(ctx context.Context) {
+	deliveries, err := d.repo.ClaimDueDeliveries(time.Now(), d.ClaimTimeout, d.BatchSize)
+	if err != nil {
+		log.Printf("webhook: failed to load due deliveries: %v", err)
+		return
+	}
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		d.attempt(ctx, &deliveries[i])
+	}
+}
+
+func (d *Dispatcher) attempt(ctx context.Context, delivery *domain.WebhookDelivery) {
+	delivery.Attempts++
+
+	subscription, err := d.repo.GetSubscriptionByID(delivery.SubscriptionID)
+	if err == nil {
+		delivery.ResponseStatus, err = d.send(ctx, subscription, delivery)
+	}
+
+	now := time.Now()
+	switch {
+	case err == nil:
+		delivery.Status = domain.WebhookDeliveryDelivered
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	case delivery.Attempts >= d.MaxAttempts:
+		// Pindahkan ke dead-letter setelah semua percobaan gagal
+		delivery.Status = domain.WebhookDeliveryDead
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = now.Add(d.backoff(delivery.Attempts))
+	}
+
+	if err := d.repo.UpdateDelivery(delivery); err != nil {
+		log.Printf("webhook: failed to update delivery %d: %v", delivery.ID, err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, subscription *domain.WebhookSubscription, delivery *domain.WebhookDelivery) (int, error) {
+	body := []byte(delivery.Payload)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, subscription.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, string(delivery.EventType))
+	req.Header.Set(EventIDHeader, delivery.EventID)
+	timestamp := time.Now().Unix()
+	req.Header.Set(TimestampHeader, fmt.Sprint(timestamp))
+	req.Header.Set(SignatureHeader, Sign(subscription.Secret, timestamp, body))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("subscriber responded with %s", resp.Status)
+	}
+	return resp.StatusCode, nil
+}
+
+// Sign returns the signature sent in SignatureHeader: the HMAC-SHA256 of
+// "<timestamp>.<body>", so a captured request cannot be replayed with a new
+// timestamp. Subscribers should also reject timestamps that are too old.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return utils.SignHMAC(secret, []byte(fmt.Sprintf("%d.%s", timestamp, body)))
+}
+
+// backoff returns BaseBackoff * 2^(attempts-1), capped at MaxBackoff.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := float64(d.BaseBackoff) * math.Pow(2, float64(attempts-1))
+	if delay > float64(d.MaxBackoff) {
+		return d.MaxBackoff
+	}
+	return time.Duration(delay)
+}